@@ -0,0 +1,96 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+func init() {
+	parseInt := func(val string) (*big.Int, error) {
+		if val == "" {
+			val = "0"
+		}
+		n, ok := new(big.Int).SetString(val, 10)
+		if !ok {
+			return nil, Error{Classification: ERR_INTERGER_TYPE, Message: "Value could not be parsed as integer"}
+		}
+		return n, nil
+	}
+	parseRat := func(val string) (*big.Rat, error) {
+		if val == "" {
+			val = "0"
+		}
+		r, ok := new(big.Rat).SetString(val)
+		if !ok {
+			return nil, Error{Classification: ERR_FLOAT_TYPE, Message: "Value could not be parsed as rational number"}
+		}
+		return r, nil
+	}
+
+	AddConverter(big.Int{}, func(val string) (interface{}, error) {
+		n, err := parseInt(val)
+		if err != nil {
+			return nil, err
+		}
+		return *n, nil
+	})
+	AddConverter(&big.Int{}, func(val string) (interface{}, error) {
+		return parseInt(val)
+	})
+	AddConverter(big.Rat{}, func(val string) (interface{}, error) {
+		r, err := parseRat(val)
+		if err != nil {
+			return nil, err
+		}
+		return *r, nil
+	})
+	AddConverter(&big.Rat{}, func(val string) (interface{}, error) {
+		return parseRat(val)
+	})
+	ruleParamCheckers["MaxBits"] = func(param string) error {
+		if n, err := strconv.Atoi(param); err != nil || n < 0 {
+			return fmt.Errorf("invalid number of bits %q", param)
+		}
+		return nil
+	}
+}
+
+// bitLen returns the number of bits needed to represent a big.Int, or the
+// larger of the numerator and denominator of a big.Rat. ok is false for
+// values of other types.
+func bitLen(v interface{}) (n int, ok bool) {
+	switch v := v.(type) {
+	case big.Int:
+		return v.BitLen(), true
+	case *big.Int:
+		return v.BitLen(), true
+	case big.Rat:
+		return ratBitLen(&v), true
+	case *big.Rat:
+		return ratBitLen(v), true
+	}
+	return 0, false
+}
+
+func ratBitLen(r *big.Rat) int {
+	n, d := r.Num().BitLen(), r.Denom().BitLen()
+	if d > n {
+		return d
+	}
+	return n
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bigForm struct {
+	Balance  big.Int  `form:"balance" json:"balance" binding:"MaxBits(128)"`
+	Supply   *big.Int `form:"supply" json:"supply" binding:"Required;MaxBits(256)"`
+	Ratio    big.Rat  `form:"ratio" json:"ratio" binding:"MaxBits(16)"`
+	Fraction *big.Rat `form:"fraction" json:"fraction"`
+}
+
+func Test_BigNumbers(t *testing.T) {
+	t.Run("Form", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/?balance=-123456789012345678901234567890&supply=115792089237316195423570985008687907853269984665640564039457584007913129639935&ratio=1/3&fraction=0.25", nil)
+		var form bigForm
+		errs := Form(req, &form)
+		assert.Empty(t, errs)
+		assert.EqualValues(t, "-123456789012345678901234567890", form.Balance.String())
+		assert.EqualValues(t, 256, form.Supply.BitLen())
+		assert.EqualValues(t, "1/3", form.Ratio.String())
+		assert.EqualValues(t, "1/4", form.Fraction.String())
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"balance":340282366920938463463374607431768211456,"supply":1,"ratio":"65536/3"}`))
+		var form bigForm
+		errs := JSON(req, &form)
+		assert.Len(t, errs, 2)
+		assert.True(t, errs.Has(ERR_MAX_BITS))
+		assert.EqualValues(t, 1, form.Supply.Int64())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/?supply=12x&fraction=1/0", nil)
+		var form bigForm
+		errs := Form(req, &form)
+		assert.True(t, errs.Has(ERR_INTERGER_TYPE))
+		assert.True(t, errs.Has(ERR_FLOAT_TYPE))
+		assert.True(t, errs.Has(ERR_REQUIRED))
+	})
+}
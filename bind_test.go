@@ -16,7 +16,14 @@
 package binding
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	chi "github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_Bind(t *testing.T) {
@@ -44,3 +51,230 @@ func Test_Bind(t *testing.T) {
 		}
 	})
 }
+
+func Test_BindFuncs(t *testing.T) {
+	for _, testCase := range formTestCases {
+		performFormTest(t, BindForm, testCase)
+	}
+	for _, testCase := range jsonTestCases {
+		performJsonTest(t, BindJSON, testCase)
+	}
+	for _, testCase := range multipartFormTestCases {
+		performMultipartFormTest(t, BindMultipartForm, testCase)
+	}
+}
+
+func Test_BindQuery(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/?title=From+the+query&content=q", strings.NewReader("title=From+the+body"))
+	req.Header.Set("Content-Type", formContentType)
+	var post Post
+	errs := BindQuery(req, &post)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, Post{Title: "From the query", Content: "q"}, post)
+	assert.Nil(t, req.PostForm)
+
+	req, _ = http.NewRequest("GET", "/?title=short&id=x", nil)
+	var blogPost BlogPost
+	errs = BindQuery(req, &blogPost)
+	assert.Len(t, errs, 4)
+	assert.EqualValues(t, ERR_INTERGER_TYPE, errs[0].Classification)
+	assert.EqualValues(t, SOURCE_QUERY, errs[0].Source)
+}
+
+func Test_QueryTag(t *testing.T) {
+	type listPosts struct {
+		Search string   `query:"q" binding:"MaxSize(20)"`
+		Tags   []string `query:"tag"`
+		Page   int      `form:"-" query:"page" binding:"Range(1,100)"`
+		Limit  int      `form:"limit"`
+		Paging struct {
+			Cursor string `query:"cursor"`
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/?q=go&tag=a&tag=b&page=2&limit=10&cursor=c1", nil)
+	var list listPosts
+	assert.Empty(t, Query(req, &list))
+	assert.EqualValues(t, "go", list.Search)
+	assert.EqualValues(t, []string{"a", "b"}, list.Tags)
+	assert.EqualValues(t, 2, list.Page)
+	assert.EqualValues(t, 10, list.Limit)
+	assert.EqualValues(t, "c1", list.Paging.Cursor)
+
+	// Fields excluded from forms are still validated.
+	req, _ = http.NewRequest("GET", "/?page=200&q=a+rather+long+search+query", nil)
+	list = listPosts{}
+	errs := Query(req, &list)
+	if assert.Len(t, errs, 2) {
+		assert.EqualValues(t, []string{"Search"}, errs[0].FieldNames)
+		assert.EqualValues(t, []string{"Page"}, errs[1].FieldNames)
+		assert.EqualValues(t, ERR_RANGE, errs[1].Classification)
+	}
+
+	// Query tags only name query parameters.
+	req, _ = http.NewRequest("POST", "/", strings.NewReader("q=go&search=form"))
+	req.Header.Set("Content-Type", formContentType)
+	list = listPosts{}
+	assert.Empty(t, Form(req, &list))
+	assert.EqualValues(t, "form", list.Search)
+}
+
+func Test_ErrorsFrom(t *testing.T) {
+	var post Post
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	assert.Nil(t, ErrorsFrom(req))
+
+	// Errors stashed by a middleware are seen by the next handler.
+	var seen Errors
+	middleware := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			Bind(req, &post)
+			next.ServeHTTP(rw, req)
+		})
+	}
+	Handler(middleware(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		seen = ErrorsFrom(req)
+	}))).ServeHTTP(nil, req)
+	assert.Len(t, seen, 2)
+	assert.EqualValues(t, ERR_REQUIRED, seen[0].Classification)
+
+	// Without Handler, the errors are not kept.
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	assert.Len(t, Bind(req, &post), 2)
+	assert.Nil(t, ErrorsFrom(req))
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "text/plain")
+	req = withState(req)
+	errs := Bind(req, &post)
+	assert.EqualValues(t, errs, ErrorsFrom(req))
+	assert.EqualValues(t, ERR_CONTENT_TYPE, ErrorsFrom(req)[0].Classification)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"title":"Hello, world"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = withState(req)
+	assert.Empty(t, JSON(req, &post))
+	assert.Empty(t, ErrorsFrom(req))
+}
+
+func Test_BindMethods(t *testing.T) {
+	newRequest := func(method, contentType string) *http.Request {
+		req, _ := http.NewRequest(method, "/?title=Hello,+query", strings.NewReader(`{"title":"Hello, body"}`))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		return req
+	}
+
+	for _, method := range []string{"GET", "HEAD", "DELETE"} {
+		var post Post
+		assert.Empty(t, Bind(newRequest(method, "application/json"), &post), method)
+		assert.EqualValues(t, "Hello, query", post.Title, method)
+	}
+	for _, method := range []string{"POST", "PUT", "PATCH"} {
+		var post Post
+		assert.Empty(t, Bind(newRequest(method, "application/json"), &post), method)
+		assert.EqualValues(t, "Hello, body", post.Title, method)
+
+		errs := Bind(newRequest(method, ""), &post)
+		assert.EqualValues(t, ERR_CONTENT_TYPE, errs[0].Classification, method)
+	}
+
+	// The body of DELETE requests is bound when DELETE is not bodyless.
+	var post Post
+	b := New(WithBodylessMethods("GET", "HEAD"))
+	assert.Empty(t, b.Bind(newRequest("DELETE", "application/json"), &post))
+	assert.EqualValues(t, "Hello, body", post.Title)
+}
+
+func Test_BindMethodsPerRouter(t *testing.T) {
+	handler := func(rw http.ResponseWriter, req *http.Request) {
+		var post Post
+		if errs := Bind(req, &post); len(errs) > 0 {
+			rw.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		rw.Write([]byte(post.Title))
+	}
+	r := chi.NewRouter()
+	r.Delete("/posts", handler)
+	r.Route("/v2", func(r chi.Router) {
+		r.Use(New(WithBodylessMethods("GET", "HEAD")).Handler)
+		r.Get("/posts", handler)
+		r.Delete("/posts", handler)
+	})
+
+	for path, expected := range map[string]string{
+		"/posts":    "Hello, query",
+		"/v2/posts": "Hello, body",
+	} {
+		req, _ := http.NewRequest("DELETE", path+"?title=Hello,+query", strings.NewReader(`{"title":"Hello, body"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rw := httptest.NewRecorder()
+		r.ServeHTTP(rw, req)
+		assert.EqualValues(t, expected, rw.Body.String(), path)
+	}
+
+	req, _ := http.NewRequest("GET", "/v2/posts?title=Hello,+query", nil)
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, req)
+	assert.EqualValues(t, "Hello, query", rw.Body.String())
+}
+
+func Test_RejectUnexpectedBody(t *testing.T) {
+	b := New(WithRejectUnexpectedBody(true))
+	var post Post
+
+	req, _ := http.NewRequest("GET", "/?title=Hello,+query", strings.NewReader(`{"title":"Hello, body"}`))
+	req.Header.Set("Content-Type", "application/json")
+	errs := b.Bind(req, &post)
+	assert.EqualValues(t, ERR_UNEXPECTED_BODY, errs[0].Classification)
+	assert.EqualValues(t, "Unexpected body for GET request", errs[0].Message)
+
+	// A body of unknown length is only rejected if it is not empty.
+	req, _ = http.NewRequest("DELETE", "/?title=Hello,+query", ioutil.NopCloser(strings.NewReader("x")))
+	req.ContentLength = -1
+	errs = b.Bind(req, &post)
+	assert.EqualValues(t, ERR_UNEXPECTED_BODY, errs[0].Classification)
+
+	req, _ = http.NewRequest("DELETE", "/?title=Hello,+query", ioutil.NopCloser(strings.NewReader("")))
+	req.ContentLength = -1
+	assert.Empty(t, b.Bind(req, &post))
+	assert.EqualValues(t, "Hello, query", post.Title)
+
+	req, _ = http.NewRequest("GET", "/?title=Hello,+query", nil)
+	assert.Empty(t, b.Bind(req, &post))
+
+	// Bodies are ignored by default.
+	req, _ = http.NewRequest("GET", "/?title=Hello,+query", strings.NewReader(`{"title":"Hello, body"}`))
+	assert.Empty(t, Bind(req, &post))
+}
+
+func Test_MediaTypeSuffix(t *testing.T) {
+	for contentType, format := range map[string]string{
+		"application/json": "json",
+		"application/vnd.myapp.v2+json; charset=utf-8": "json",
+		"Application/Problem+JSON":                     "json",
+		"application/atom+xml":                         "xml",
+		"text/xml":                                     "xml",
+		"application/x-yaml":                           "yaml",
+		"application/x-www-form-urlencoded":            "form",
+		"multipart/form-data; boundary=x":              "multipart",
+		"application/jsonp":                            "",
+		"text/plain":                                   "",
+		"BoGuS":                                        "",
+	} {
+		assert.EqualValues(t, format, mediaFormat(contentType), contentType)
+	}
+
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"title":"Hello, world"}`))
+	req.Header.Set("Content-Type", "application/vnd.myapp.v2+json; charset=utf-8")
+	var post Post
+	assert.Empty(t, Bind(req, &post))
+	assert.EqualValues(t, "Hello, world", post.Title)
+	mediaType, params := MediaType(req)
+	assert.EqualValues(t, "application/vnd.myapp.v2+json", mediaType)
+	assert.EqualValues(t, map[string]string{"charset": "utf-8"}, params)
+}
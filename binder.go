@@ -0,0 +1,232 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+type (
+	// Binder is a configured set of binding settings. The package level
+	// functions use the Binder attached to the request by Handler, or
+	// else the package level settings such as MaxMemory.
+	Binder struct {
+		maxMemory             int64
+		requirePresence       bool
+		emptyAsNil            bool
+		validation            *ValidationConfig
+		errorResponder        ErrorResponder
+		tracer                Tracer
+		formLimits            FormLimits
+		maxJSONDepth          int
+		disallowDuplicateKeys bool
+		useNumber             bool
+		stringIntegers        bool
+		rawBodyLimit          int64
+		bodylessMethods       []string
+		rejectUnexpectedBody  bool
+		fieldMaskParam        string
+		strictFieldMask       bool
+		maxDecompressedSize   int64
+		timeLayout            string
+		numberLocale          string
+	}
+
+	// Option configures a Binder.
+	Option func(*Binder)
+)
+
+// New returns a Binder starting from the current package level settings,
+// with opts applied.
+func New(opts ...Option) *Binder {
+	b := defaultBinder()
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// WithMaxMemory sets the maximum amount of memory used when parsing a
+// multipart form; see MaxMemory.
+func WithMaxMemory(n int64) Option {
+	return func(b *Binder) {
+		b.maxMemory = n
+	}
+}
+
+// WithRequirePresence sets whether Required is satisfied by fields
+// present in the request with their zero value; see RequirePresence.
+func WithRequirePresence(require bool) Option {
+	return func(b *Binder) {
+		b.requirePresence = require
+	}
+}
+
+// WithEmptyAsNil sets whether empty form and query values leave pointer
+// fields nil; see EmptyAsNil.
+func WithEmptyAsNil(empty bool) Option {
+	return func(b *Binder) {
+		b.emptyAsNil = empty
+	}
+}
+
+// WithUseNumber sets whether JSON numbers are bound into interface{}
+// fields as json.Number; see UseNumber.
+func WithUseNumber(use bool) Option {
+	return func(b *Binder) {
+		b.useNumber = use
+	}
+}
+
+// WithValidation sets the validation settings used when the request
+// context does not carry any; see WithValidationConfig.
+func WithValidation(cfg *ValidationConfig) Option {
+	return func(b *Binder) {
+		b.validation = cfg
+	}
+}
+
+// WithErrorResponder sets the function used by MustBind to write the
+// error response; see SetErrorResponder.
+func WithErrorResponder(fn ErrorResponder) Option {
+	return func(b *Binder) {
+		b.errorResponder = fn
+	}
+}
+
+type binderKey struct{}
+
+// defaultBinder returns a Binder holding the package level settings.
+func defaultBinder() *Binder {
+	return &Binder{
+		maxMemory:             MaxMemory,
+		requirePresence:       RequirePresence,
+		emptyAsNil:            EmptyAsNil,
+		errorResponder:        errorResponder,
+		tracer:                tracer,
+		formLimits:            DefaultFormLimits,
+		maxJSONDepth:          MaxJSONDepth,
+		disallowDuplicateKeys: DisallowDuplicateKeys,
+		useNumber:             UseNumber,
+		stringIntegers:        StringIntegers,
+		rawBodyLimit:          RawBodyLimit,
+		bodylessMethods:       BodylessMethods,
+		rejectUnexpectedBody:  RejectUnexpectedBody,
+		fieldMaskParam:        FieldMaskParam,
+		strictFieldMask:       StrictFieldMask,
+		maxDecompressedSize:   MaxDecompressedSize,
+		timeLayout:            TimeLayout,
+		numberLocale:          numberLocale,
+	}
+}
+
+// binderFrom returns the Binder attached to req, or the package level
+// settings if there is none.
+func binderFrom(req *http.Request) *Binder {
+	if req != nil {
+		if b, ok := req.Context().Value(binderKey{}).(*Binder); ok {
+			return b
+		}
+	}
+	return defaultBinder()
+}
+
+// attach returns req, or a copy of it, using the settings of b and
+// having a requestState; see withState.
+func (b *Binder) attach(req *http.Request) *http.Request {
+	req = withState(req)
+	if binderFrom(req) != b {
+		req = req.WithContext(context.WithValue(req.Context(), binderKey{}, b))
+	}
+	return req
+}
+
+// bind binds req into obj with fn, using the settings of b.
+func (b *Binder) bind(req *http.Request, obj interface{}, fn func(*http.Request, interface{}) Errors) Errors {
+	r := b.attach(req)
+	defer keepForms(req, r)
+	return fn(r, obj)
+}
+
+// Handler is a middleware making the package level functions use the
+// settings of b for the requests it handles, and keeping what binding
+// records about them like the package level Handler, e.g. for a chi
+// sub-router:
+//
+//	r.Route("/admin", func(r chi.Router) {
+//		r.Use(binding.New(binding.WithMaxMemory(100 << 20)).Handler)
+//		...
+//	})
+func (b *Binder) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(rw, b.attach(req))
+	})
+}
+
+// Bind is like the package level Bind, using the settings of b.
+func (b *Binder) Bind(req *http.Request, obj interface{}) Errors {
+	return b.bind(req, obj, Bind)
+}
+
+// Form is like the package level Form, using the settings of b.
+func (b *Binder) Form(req *http.Request, obj interface{}) Errors {
+	return b.bind(req, obj, Form)
+}
+
+// MultipartForm is like the package level MultipartForm, using the
+// settings of b.
+func (b *Binder) MultipartForm(req *http.Request, obj interface{}) Errors {
+	return b.bind(req, obj, MultipartForm)
+}
+
+// JSON is like the package level JSON, using the settings of b.
+func (b *Binder) JSON(req *http.Request, obj interface{}) Errors {
+	return b.bind(req, obj, JSON)
+}
+
+// XML is like the package level XML, using the settings of b.
+func (b *Binder) XML(req *http.Request, obj interface{}) Errors {
+	return b.bind(req, obj, XML)
+}
+
+// YAML is like the package level YAML, using the settings of b.
+func (b *Binder) YAML(req *http.Request, obj interface{}) Errors {
+	return b.bind(req, obj, YAML)
+}
+
+// Query is like the package level Query, using the settings of b.
+func (b *Binder) Query(req *http.Request, obj interface{}) Errors {
+	return b.bind(req, obj, Query)
+}
+
+// BindReader is like the package level BindReader, using the settings of
+// b.
+func (b *Binder) BindReader(r io.Reader, contentType string, obj interface{}) Errors {
+	return Bind(b.attach(readerRequest(r, contentType)), obj)
+}
+
+// BindValues is like the package level BindValues, using the settings of
+// b.
+func (b *Binder) BindValues(values url.Values, obj interface{}) Errors {
+	return Form(b.attach(valuesRequest(values)), obj)
+}
+
+// Validate is like the package level Validate, using the settings of b.
+func (b *Binder) Validate(req *http.Request, obj interface{}) Errors {
+	return Validate(b.attach(req), obj)
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	chi "github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Binder(t *testing.T) {
+	type noteForm struct {
+		Count int    `form:"count" binding:"Required"`
+		Note  string `form:"note" binding:"MaxSize(10)"`
+	}
+
+	strict := New(
+		WithRequirePresence(true),
+		WithValidation(&ValidationConfig{Limits: map[string]string{"Note.MaxSize": "3"}}),
+		WithErrorResponder(func(rw http.ResponseWriter, req *http.Request, errs Errors) {
+			rw.WriteHeader(http.StatusTeapot)
+		}),
+	)
+
+	var errs Errors
+	m := chi.NewRouter()
+	m.Get("/", func(rw http.ResponseWriter, req *http.Request) {
+		var form noteForm
+		errs = Form(req, &form)
+	})
+	m.Route("/strict", func(r chi.Router) {
+		r.Use(strict.Handler)
+		r.Get("/", func(rw http.ResponseWriter, req *http.Request) {
+			var form noteForm
+			errs = Form(req, &form)
+		})
+	})
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/?count=0&note=long", nil))
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/strict/?count=0&note=long", nil))
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, ERR_MAX_SIZE, errs[0].Classification)
+
+	// Methods of the Binder apply its settings without the middleware.
+	var form noteForm
+	errs = strict.Form(httptest.NewRequest("GET", "/?count=0&note=n", nil), &form)
+	assert.Empty(t, errs)
+
+	lenient := New()
+	form = noteForm{}
+	errs = lenient.Form(httptest.NewRequest("GET", "/?count=0&note=n", nil), &form)
+	assert.Len(t, errs, 1)
+}
+
+func Test_BinderParsing(t *testing.T) {
+	type eventForm struct {
+		Date  time.Time  `form:"date"`
+		Until *time.Time `form:"until"`
+		Stamp time.Time  `form:"stamp" time_format:"2006-01-02 15:04"`
+		Price float64    `form:"price"`
+	}
+
+	b := New(WithTimeLayout("02.01.2006"), WithNumberLocale("de"))
+	var form eventForm
+	errs := b.Form(httptest.NewRequest("GET", "/?date=24.12.2020&until=31.12.2020&stamp=2020-12-24+18:30&price=1.234,5", nil), &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, time.Date(2020, 12, 24, 0, 0, 0, 0, time.UTC), form.Date)
+	if assert.NotNil(t, form.Until) {
+		assert.EqualValues(t, time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), *form.Until)
+	}
+	assert.EqualValues(t, time.Date(2020, 12, 24, 18, 30, 0, 0, time.UTC), form.Stamp)
+	assert.EqualValues(t, 1234.5, form.Price)
+
+	errs = b.Form(httptest.NewRequest("GET", "/?date=2020-12-24", nil), &form)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_TIME, errs[0].Classification)
+		assert.EqualValues(t, "Value could not be parsed as time in format 02.01.2006", errs[0].Message)
+	}
+
+	// The package level settings are unaffected.
+	form = eventForm{}
+	errs = Form(httptest.NewRequest("GET", "/?date=2020-12-24T00:00:00Z&price=1.5", nil), &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, time.Date(2020, 12, 24, 0, 0, 0, 0, time.UTC), form.Date)
+	assert.EqualValues(t, 1.5, form.Price)
+}
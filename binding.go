@@ -18,8 +18,12 @@
 package binding
 
 import (
+	"bytes"
+	"encoding"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -27,6 +31,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/goccy/go-json"
@@ -35,34 +40,218 @@ import (
 
 // Bind wraps up the functionality of the Form and Json middleware
 // according to the Content-Type and verb of the request.
-// A Content-Type is required for POST and PUT requests.
-// Bind invokes the ErrorHandler middleware to bail out if errors
-// occurred. If you want to perform your own error handling, use
-// Form or Json middleware directly. An interface pointer can
-// be added as a second argument in order to map the struct to
-// a specific interface.
+// Requests with a method listed in BodylessMethods are bound from the
+// query string. A Content-Type is required for POST, PUT and PATCH
+// requests.
+// Fields tagged with param, query, header or cookie are bound from those
+// parts of the request too, so one struct can mix them with the body. A
+// field tagged for several of them is bound from the last one present, in
+// the order body, query, header, cookie, param: URL parameters win.
+// Bind only returns the errors that occurred and never writes to
+// the response; use MustBind to have the error response written.
 func Bind(req *http.Request, obj interface{}) Errors {
+	decode, fromQuery, errors := decoderFor(req)
+	if decode == nil {
+		stashErrors(req, errors)
+		return errors
+	}
+	return bindWith(req, obj, decode, fromQuery)
+}
+
+// BodylessMethods are the methods of requests that Bind binds from the
+// query string, whatever their Content-Type.
+var BodylessMethods = []string{"GET", "HEAD", "DELETE"}
+
+// WithBodylessMethods sets the methods of requests that Bind binds from
+// the query string; see BodylessMethods. Use it with Binder.Handler to
+// choose the methods per router, e.g. for an API whose DELETE requests
+// carry a body:
+//
+//	r.Use(binding.New(binding.WithBodylessMethods("GET", "HEAD")).Handler)
+func WithBodylessMethods(methods ...string) Option {
+	return func(b *Binder) {
+		b.bodylessMethods = methods
+	}
+}
+
+// RejectUnexpectedBody makes Bind fail with ERR_UNEXPECTED_BODY when a
+// request with one of the BodylessMethods has a body, which would
+// otherwise be ignored.
+var RejectUnexpectedBody = false
+
+// WithRejectUnexpectedBody sets whether Bind fails on bodies sent with
+// bodyless methods; see RejectUnexpectedBody.
+func WithRejectUnexpectedBody(reject bool) Option {
+	return func(b *Binder) {
+		b.rejectUnexpectedBody = reject
+	}
+}
+
+// hasBody reports whether req has a non-empty body. A body of unknown
+// length is peeked at, and left readable.
+func hasBody(req *http.Request) bool {
+	if req.ContentLength > 0 {
+		return true
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return false
+	}
+	var first [1]byte
+	n, _ := io.ReadFull(req.Body, first[:])
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(first[:n]), req.Body), req.Body}
+	return n > 0
+}
+
+// isBodyless reports whether requests with the given method are bound
+// from the query string.
+func (b *Binder) isBodyless(method string) bool {
+	for _, m := range b.bodylessMethods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// decoder decodes the request into obj without validating it.
+type decoder func(req *http.Request, obj interface{}) Errors
+
+// decoderFor returns the decoder Bind uses for req, and whether it binds
+// req from its query string rather than its body, or an error if the
+// Content-Type of req is not supported.
+func decoderFor(req *http.Request) (decode decoder, fromQuery bool, errors Errors) {
+	if b := binderFrom(req); b.isBodyless(req.Method) {
+		if b.rejectUnexpectedBody && hasBody(req) {
+			errors.Add([]string{}, ERR_UNEXPECTED_BODY, "Unexpected body for "+req.Method+" request")
+			return nil, false, errors
+		}
+		return decodeQuery, true, nil
+	}
 	contentType := req.Header.Get("Content-Type")
-	if req.Method == "POST" || req.Method == "PUT" || len(contentType) > 0 {
-		switch {
-		case strings.Contains(contentType, "form-urlencoded"):
-			return Form(req, obj)
-		case strings.Contains(contentType, "multipart/form-data"):
-			return MultipartForm(req, obj)
-		case strings.Contains(contentType, "json"):
-			return JSON(req, obj)
+	if fn := registeredBinder(contentType); fn != nil {
+		return fn, false, nil
+	}
+	if req.Method == "POST" || req.Method == "PUT" || req.Method == "PATCH" || len(contentType) > 0 {
+		switch mediaFormat(contentType) {
+		case "form":
+			return decodeForm, false, nil
+		case "multipart":
+			return decodeMultipartForm, false, nil
+		case "json":
+			return decodeJSON, false, nil
+		case "xml":
+			return decodeXML, false, nil
+		case "yaml":
+			return decodeYAML, false, nil
 		default:
-			var errors Errors
 			if contentType == "" {
 				errors.Add([]string{}, ERR_CONTENT_TYPE, "Empty Content-Type")
 			} else {
 				errors.Add([]string{}, ERR_CONTENT_TYPE, "Unsupported Content-Type")
 			}
-			return errors
+			return nil, false, errors
 		}
 	} else {
-		return Form(req, obj)
+		return decodeForm, false, nil
+	}
+}
+
+// MediaType returns the media type of the body of req, in lower case, and
+// its parameters, e.g. "application/vnd.myapp.v2+json" and {"charset":
+// "utf-8"}. Binding dispatches on the structured syntax suffix of vendor
+// media types such as this one, so hooks and Validators can use MediaType
+// to tell versions apart.
+func MediaType(req *http.Request) (string, map[string]string) {
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return "", nil
+	}
+	return mediaType, params
+}
+
+// mediaFormat returns the format of bodies with the given Content-Type:
+// "form", "multipart", "json", "xml" or "yaml", or "" if it is not known.
+// Media types with a structured syntax suffix, such as
+// "application/vnd.myapp.v2+json", have the format of their suffix.
+func mediaFormat(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	subtype := mediaType[strings.IndexByte(mediaType, '/')+1:]
+	if i := strings.LastIndexByte(subtype, '+'); i >= 0 {
+		subtype = subtype[i+1:]
+	}
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		return "form"
+	case mediaType == "multipart/form-data":
+		return "multipart"
+	case subtype == "json":
+		return "json"
+	case subtype == "xml":
+		return "xml"
+	case subtype == "yaml" || subtype == "x-yaml":
+		return "yaml"
+	case subtype == "ndjson" || subtype == "x-ndjson":
+		return "ndjson"
 	}
+	return ""
+}
+
+// bindWith runs the binding hooks around decoding req into obj, and
+// validates it. fromQuery tells that decode binds obj from the query
+// string, so fields tagged with query are not bound again. The errors are
+// also kept for ErrorsFrom.
+func bindWith(req *http.Request, obj interface{}, decode decoder, fromQuery bool) Errors {
+	ensurePointer(obj)
+	if stateOf(req) == nil {
+		// The record of the binding is needed while binding, e.g. by Merge.
+		r := withState(req)
+		defer keepForms(req, r)
+		req = r
+	}
+	checkTypeRules(reflect.TypeOf(obj).Elem())
+	// The raw body and signatures are those of the body as sent, before
+	// its Content-Encoding is removed.
+	captureRawBody(req)
+	errors := verifyBody(req, obj)
+	var inflated *inflatedBody
+	if len(errors) == 0 {
+		inflated, errors = decompressBody(req)
+	}
+	if len(errors) == 0 {
+		errors = beforeBind(req, obj)
+	}
+	if len(errors) == 0 {
+		decode, errors = withFieldMask(req, obj, decode)
+	}
+	if len(errors) == 0 {
+		errors = finishBind(req, obj, bindSources(req, obj, !fromQuery, inflated.check(decode(req, obj))))
+	}
+	stashErrors(req, errors)
+	return errors
+}
+
+// stashErrors records errs as the result of the last binding of req.
+func stashErrors(req *http.Request, errs Errors) {
+	if s := stateOf(req); s != nil {
+		s.errors = errs
+	}
+}
+
+// ErrorsFrom returns the errors of the last binding of req. The binders
+// never write an error response themselves, so middleware that binds a
+// request can leave the response to a later handler, which picks the
+// errors up from the request. It needs Handler.
+func ErrorsFrom(req *http.Request) Errors {
+	if s := stateOf(req); s != nil {
+		return s.errors
+	}
+	return nil
 }
 
 const (
@@ -82,8 +271,18 @@ const (
 func errorHandler(errs Errors, rw http.ResponseWriter) {
 	if len(errs) > 0 {
 		rw.Header().Set("Content-Type", _JSON_CONTENT_TYPE)
-		if errs.Has(ERR_DESERIALIZATION) {
+		if errs.Has(ERR_CONFIG) {
+			rw.WriteHeader(http.StatusInternalServerError)
+		} else if errs.Has(ERR_DESERIALIZATION) {
+			rw.WriteHeader(http.StatusBadRequest)
+		} else if errs.Has(ERR_LIMIT) {
+			rw.WriteHeader(http.StatusBadRequest)
+		} else if errs.Has(ERR_UNEXPECTED_BODY) {
 			rw.WriteHeader(http.StatusBadRequest)
+		} else if errs.Has(ERR_FIELD_MASK) {
+			rw.WriteHeader(http.StatusBadRequest)
+		} else if errs.Has(ERR_SIGNATURE) {
+			rw.WriteHeader(http.StatusUnauthorized)
 		} else if errs.Has(ERR_CONTENT_TYPE) {
 			rw.WriteHeader(http.StatusUnsupportedMediaType)
 		} else {
@@ -95,6 +294,43 @@ func errorHandler(errs Errors, rw http.ResponseWriter) {
 	}
 }
 
+// ErrorResponder writes the response for a request that failed binding.
+type ErrorResponder func(rw http.ResponseWriter, req *http.Request, errs Errors)
+
+var errorResponder ErrorResponder = func(rw http.ResponseWriter, req *http.Request, errs Errors) {
+	if errs.Has(ERR_CONTENT_TYPE) {
+		setAcceptHeader(rw, req)
+	}
+	errorHandler(errs, rw)
+}
+
+// mediaTypes are the media types of the bodies Bind can decode.
+var mediaTypes = []string{"application/json", "application/xml", "application/yaml", "application/x-www-form-urlencoded", "multipart/form-data"}
+
+// setAcceptHeader lists the media types Bind can decode in the
+// Accept-Patch header of the response to a PATCH request, or in the
+// Accept-Post header otherwise, to tell the client what to send instead.
+func setAcceptHeader(rw http.ResponseWriter, req *http.Request) {
+	header := "Accept-Post"
+	if req != nil && req.Method == "PATCH" {
+		header = "Accept-Patch"
+	}
+	rw.Header().Set(header, strings.Join(mediaTypes, ", "))
+}
+
+// SetErrorResponder replaces the function used by MustBind to write the
+// response for errors. The default writes the errors as JSON, with a
+// status code depending on their classification.
+func SetErrorResponder(fn ErrorResponder) {
+	errorResponder = fn
+}
+
+// RespondErrors writes the response for errs, as returned by binding req,
+// using the ErrorResponder of the request's Binder.
+func RespondErrors(rw http.ResponseWriter, req *http.Request, errs Errors) {
+	binderFrom(req).errorResponder(rw, req, errs)
+}
+
 // Form is middleware to deserialize form-urlencoded data from the request.
 // It gets data from the form-urlencoded body, if present, or from the
 // query string. It uses the http.Request.ParseForm() method
@@ -102,12 +338,16 @@ func errorHandler(errs Errors, rw http.ResponseWriter) {
 // into the struct with the proper type. Structs with primitive slice types
 // (bool, float, int, string) can support deserialization of repeated form
 // keys, for example: key=val1&key=val2&key=val3
+// Bodies with a charset other than UTF-8 are converted to UTF-8 first;
+// see RegisterCharset.
 // An interface pointer can be added as a second argument in order
 // to map the struct to a specific interface.
 func Form(req *http.Request, formStruct interface{}) Errors {
-	var errors Errors
+	return bindWith(req, formStruct, decodeForm, false)
+}
 
-	ensurePointer(formStruct)
+func decodeForm(req *http.Request, formStruct interface{}) Errors {
+	var errors Errors
 	formStructV := reflect.ValueOf(formStruct)
 	parseErr := req.ParseForm()
 
@@ -118,10 +358,37 @@ func Form(req *http.Request, formStruct interface{}) Errors {
 	if parseErr != nil {
 		errors.Add([]string{}, ERR_DESERIALIZATION, parseErr.Error())
 	}
-	errors = mapForm(formStructV, req.Form, nil, errors)
-	return append(errors, Validate(req, formStruct)...)
+	if errs := transcodeForm(req); len(errs) > 0 {
+		return append(errors, errs...)
+	}
+	b := binderFrom(req)
+	if errs := b.formLimits.check(req.Form, nil); len(errs) > 0 {
+		return append(errors, errs...)
+	}
+	present := newFieldPaths()
+	errors = mapForm(formStructV, req.Form, nil, "form", func(key string) string {
+		if _, ok := req.PostForm[key]; ok {
+			return SOURCE_FORM
+		}
+		return SOURCE_QUERY
+	}, present, b, errors)
+	setProvided(req, formStruct, present)
+	return errors
 }
 
+// EmptyAsNil makes empty form and query values leave pointer fields nil
+// and absent, rather than pointing to an empty or zero value. HTML forms
+// submit all inputs, including those left empty. It can be enabled for a
+// single field with the omitempty option of its form tag:
+//
+//	Nickname *string `form:"nickname,omitempty"`
+var EmptyAsNil = false
+
+// UseNumber makes JSON numbers bound into interface{} fields, including
+// the values of maps and slices of interface{}, json.Number values rather
+// than float64, so large integers and precise decimals are kept intact.
+var UseNumber = false
+
 // MaxMemory represents maximum amount of memory to use when parsing a multipart form.
 // Set this to whatever value you prefer; default is 10 MB.
 var MaxMemory = int64(1024 * 1024 * 10)
@@ -131,24 +398,45 @@ var MaxMemory = int64(1024 * 1024 * 10)
 // you can pass in an interface to make the interface available for injection
 // into other handlers later.
 func MultipartForm(req *http.Request, formStruct interface{}) Errors {
+	return bindWith(req, formStruct, decodeMultipartForm, false)
+}
+
+func decodeMultipartForm(req *http.Request, formStruct interface{}) Errors {
 	var errors Errors
-	ensurePointer(formStruct)
 	formStructV := reflect.ValueOf(formStruct)
 	// This if check is necessary due to https://github.com/martini-contrib/csrf/issues/6
 	if req.MultipartForm == nil {
 		// Workaround for multipart forms returning nil instead of an error
 		// when content is not multipart; see https://code.google.com/p/go/issues/detail?id=6334
+		decode, errs := charsetDecoder(req)
+		if len(errs) > 0 {
+			return errs
+		}
 		if multipartReader, err := req.MultipartReader(); err != nil {
 			errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
 		} else {
-			form, parseErr := multipartReader.ReadForm(MaxMemory)
+			// ReadForm collects every part before anything is mapped, so
+			// value and file parts may arrive in any order; files larger
+			// than MaxMemory are spooled to disk.
+			form, parseErr := multipartReader.ReadForm(binderFrom(req).maxMemory)
 			if parseErr != nil {
 				errors.Add([]string{}, ERR_DESERIALIZATION, parseErr.Error())
+				return errors
+			}
+			if decode != nil {
+				if err := transcodeMultipart(form, decode); err != nil {
+					errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+					return errors
+				}
+				markUTF8(req)
 			}
 
 			if req.Form == nil {
 				req.ParseForm()
 			}
+			if req.Form == nil {
+				req.Form = make(url.Values)
+			}
 			for k, v := range form.Value {
 				req.Form[k] = append(req.Form[k], v...)
 			}
@@ -156,8 +444,17 @@ func MultipartForm(req *http.Request, formStruct interface{}) Errors {
 			req.MultipartForm = form
 		}
 	}
-	errors = mapForm(formStructV, req.MultipartForm.Value, req.MultipartForm.File, errors)
-	return append(errors, Validate(req, formStruct)...)
+	if req.MultipartForm == nil {
+		return errors
+	}
+	b := binderFrom(req)
+	if errs := b.formLimits.check(req.MultipartForm.Value, req.MultipartForm.File); len(errs) > 0 {
+		return append(errors, errs...)
+	}
+	present := newFieldPaths()
+	errors = mapForm(formStructV, req.MultipartForm.Value, req.MultipartForm.File, "form", nil, present, b, errors)
+	setProvided(req, formStruct, present)
+	return errors
 }
 
 // JSON is middleware to deserialize a JSON payload from the request
@@ -166,17 +463,125 @@ func MultipartForm(req *http.Request, formStruct interface{}) Errors {
 // An interface pointer can be added as a second argument in order
 // to map the struct to a specific interface.
 func JSON(req *http.Request, jsonStruct interface{}) Errors {
-	var errors Errors
-	ensurePointer(jsonStruct)
+	return bindWith(req, jsonStruct, decodeJSON, false)
+}
 
+func decodeJSON(req *http.Request, jsonStruct interface{}) Errors {
+	var errors Errors
 	if req.Body != nil {
 		defer req.Body.Close()
-		err := json.NewDecoder(req.Body).Decode(jsonStruct)
-		if err != nil && err != io.EOF {
-			errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+		data, err := ioutil.ReadAll(req.Body)
+		decoded := data
+		if err == nil {
+			b := binderFrom(req)
+			if errs := checkJSONDepth(data, b.maxJSONDepth); len(errs) > 0 {
+				return errs
+			}
+			if b.disallowDuplicateKeys {
+				errors = duplicateJSONKeys(data)
+			}
+			if typ := reflect.TypeOf(jsonStruct); b.stringIntegers || holdsDuration(typ) {
+				decoded = unquoteJSONIntegers(data, typ, b.stringIntegers)
+			}
+			var converted []convertedJSONValue
+			if typ := reflect.TypeOf(jsonStruct); holdsJSONConverted(typ) {
+				decoded, converted = extractConvertedJSON(decoded, typ)
+			}
+			dec := json.NewDecoder(bytes.NewReader(decoded))
+			if b.useNumber {
+				dec.UseNumber()
+			}
+			err = dec.Decode(jsonStruct)
+			errors = setConvertedJSON(jsonStruct, converted, errors)
+			present := jsonPresence(data, jsonStruct)
+			setProvided(req, jsonStruct, present)
+			for _, path := range *present.paths {
+				b.trace(TraceEvent{Stage: TRACE_BIND, Field: path, Source: SOURCE_BODY})
+			}
+		}
+		if typeErr, ok := err.(*json.UnmarshalTypeError); ok && typeErr.Type != nil {
+			if isJSONOverflow(typeErr) {
+				errors = addOverflowError(errors, typeErr.Field, typeErr.Type)
+			} else {
+				errors.Add([]string{typeErr.Field}, ERR_DESERIALIZATION, err.Error())
+			}
+			field, found := jsonErrorField(reflect.TypeOf(jsonStruct), typeErr.Struct, typeErr.Field)
+			annotateErrors(errors[len(errors)-1:], typeErr.Type, jsonErrorValue(decoded, typeErr.Offset), SOURCE_BODY, !found || isSensitive(field))
+		} else if err != nil && err != io.EOF {
+			errors = append(errors, unmarshalerErrors(decoded, jsonStruct, err)...)
+		}
+		if len(errors) > 0 {
+			binderFrom(req).trace(TraceEvent{Stage: TRACE_REJECT, Field: strings.Join(errors[0].FieldNames, ","), Source: SOURCE_BODY, Errors: errors})
 		}
 	}
-	return append(errors, Validate(req, jsonStruct)...)
+	return errors
+}
+
+var jsonIntegerPattern = regexp.MustCompile(`^-\d*$|^\d+$`)
+
+// isJSONOverflow reports whether a JSON type error was caused by a
+// well-formed number that does not fit into a numeric field.
+func isJSONOverflow(err *json.UnmarshalTypeError) bool {
+	if err.Type == nil || !strings.HasPrefix(err.Value, "number ") {
+		return false
+	}
+	switch err.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return jsonIntegerPattern.MatchString(err.Value[7:])
+	}
+	return false
+}
+
+// BindJSON binds the JSON body of req into obj and validates it. Unlike
+// Bind it does not look at the Content-Type, and like all binders it only
+// returns the errors, leaving the response to the caller.
+func BindJSON(req *http.Request, obj interface{}) Errors {
+	return JSON(req, obj)
+}
+
+// BindForm binds the form-urlencoded body and query string of req into
+// obj and validates it.
+func BindForm(req *http.Request, obj interface{}) Errors {
+	return Form(req, obj)
+}
+
+// BindMultipartForm binds the multipart body of req into obj and
+// validates it.
+func BindMultipartForm(req *http.Request, obj interface{}) Errors {
+	return MultipartForm(req, obj)
+}
+
+// Query binds only the query string of req into obj and validates it;
+// the request body is never read. Fields are named by their query tag,
+// e.g. query:"page", or else by their form tag. Repeated parameters are
+// bound into slices.
+func Query(req *http.Request, obj interface{}) Errors {
+	return bindWith(req, obj, decodeQuery, true)
+}
+
+// BindQuery binds only the query string of req into obj and validates
+// it, like Query.
+func BindQuery(req *http.Request, obj interface{}) Errors {
+	return Query(req, obj)
+}
+
+func decodeQuery(req *http.Request, obj interface{}) Errors {
+	var errors Errors
+	query, err := url.ParseQuery(req.URL.RawQuery)
+	if err != nil {
+		errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+	}
+	b := binderFrom(req)
+	if errs := b.formLimits.check(query, nil); len(errs) > 0 {
+		return append(errors, errs...)
+	}
+	present := newFieldPaths()
+	errors = mapForm(reflect.ValueOf(obj), query, nil, "query", func(string) string {
+		return SOURCE_QUERY
+	}, present, b, errors)
+	setProvided(req, obj, present)
+	return errors
 }
 
 // RawValidate is same as Validate but does not require a HTTP context,
@@ -193,10 +598,10 @@ func RawValidate(obj interface{}) Errors {
 	if k == reflect.Slice || k == reflect.Array {
 		for i := 0; i < v.Len(); i++ {
 			e := v.Index(i).Interface()
-			errs = validateStruct(errs, e)
+			errs = validateStruct(errs, &validation{}, e)
 		}
 	} else {
-		errs = validateStruct(errs, obj)
+		errs = validateStruct(errs, &validation{}, obj)
 	}
 	return errs
 }
@@ -207,6 +612,14 @@ func RawValidate(obj interface{}) Errors {
 // performs no error handling: it merely detects errors and maps them.
 func Validate(req *http.Request, obj interface{}) Errors {
 	var errs Errors
+	b := binderFrom(req)
+	vd := &validation{req: req, presence: b.requirePresence, cfg: b.validation, b: b, provided: providedFields(req, obj),
+		mask: fieldMaskOf(req, obj)}
+	if req != nil {
+		if cfg := ValidationConfigFrom(req.Context()); cfg != nil {
+			vd.cfg = cfg
+		}
+	}
 	v := reflect.ValueOf(obj)
 	k := v.Kind()
 	if k == reflect.Interface || k == reflect.Ptr {
@@ -216,13 +629,13 @@ func Validate(req *http.Request, obj interface{}) Errors {
 	if k == reflect.Slice || k == reflect.Array {
 		for i := 0; i < v.Len(); i++ {
 			e := v.Index(i).Interface()
-			errs = validateStruct(errs, e)
+			errs = validateStruct(errs, vd, e)
 			if validator, ok := e.(Validator); ok {
 				errs = validator.Validate(req, errs)
 			}
 		}
 	} else {
-		errs = validateStruct(errs, obj)
+		errs = validateStruct(errs, vd, obj)
 		if validator, ok := obj.(Validator); ok {
 			errs = validator.Validate(req, errs)
 		}
@@ -233,6 +646,9 @@ func Validate(req *http.Request, obj interface{}) Errors {
 var (
 	AlphaDashPattern    = regexp.MustCompile(`[^\d\w-_]`)
 	AlphaDashDotPattern = regexp.MustCompile(`[^\d\w-_\.]`)
+	AlphaPattern        = regexp.MustCompile(`[^a-zA-Z]`)
+	AlphaNumericPattern = regexp.MustCompile(`[^a-zA-Z0-9]`)
+	AlphaUnicodePattern = regexp.MustCompile(`[^\pL\pM]`)
 	EmailPattern        = regexp.MustCompile(`\A[\w!#$%&'*+/=?^_`+"`"+`{|}~-]+(?:\.[\w!#$%&'*+/=?^_`+"`"+`{|}~-]+)*@(?:[\w](?:[\w-]*[\w])?\.)+[a-zA-Z0-9](?:[\w-]*[\w])?\z`)
 )
 
@@ -306,6 +722,16 @@ func AddParamRule(r *ParamRule) {
 	paramRuleMapper = append(paramRuleMapper, r)
 }
 
+// ruleString formats a field value for the string rules. Pointers are
+// followed, so a *string field is checked by the string it points to.
+func ruleString(v interface{}) string {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("%v", rv.Interface())
+}
+
 func in(fieldValue interface{}, arr string) bool {
 	val := fmt.Sprintf("%v", fieldValue)
 	vals := strings.Split(arr, ",")
@@ -319,7 +745,51 @@ func in(fieldValue interface{}, arr string) bool {
 	return isIn
 }
 
+// SanitizeSearchQuery prepares a user supplied search term for use in
+// SQL LIKE clauses or search engine queries. It trims surrounding white
+// space, strips control characters and, if maxLen is positive, truncates
+// the result to at most maxLen runes. When escapeWildcards is true, the
+// LIKE and glob metacharacters (\, %, _, * and ?) are escaped with a
+// backslash; the escapes count towards maxLen and are never cut in half.
+func SanitizeSearchQuery(str string, maxLen int, escapeWildcards bool) string {
+	str = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, str)
+	str = strings.TrimSpace(str)
+
+	if maxLen <= 0 && !escapeWildcards {
+		return str
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range str {
+		escape := escapeWildcards && strings.ContainsRune(searchWildcards, r)
+		size := 1
+		if escape {
+			size = 2
+		}
+		if maxLen > 0 && n+size > maxLen {
+			return strings.TrimSpace(b.String())
+		}
+		if escape {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	return b.String()
+}
+
+// searchWildcards are the characters escaped by SanitizeSearchQuery.
+const searchWildcards = `\%_*?`
+
 func parseFormName(raw, actual string) string {
+	if i := strings.IndexByte(actual, ','); i >= 0 {
+		actual = actual[:i]
+	}
 	if len(actual) > 0 {
 		return actual
 	}
@@ -327,7 +797,7 @@ func parseFormName(raw, actual string) string {
 }
 
 // Performs required field checking on a struct
-func validateStruct(errors Errors, obj interface{}) Errors {
+func validateStruct(errors Errors, vd *validation, obj interface{}) Errors {
 	typ := reflect.TypeOf(obj)
 	val := reflect.ValueOf(obj)
 
@@ -335,26 +805,64 @@ func validateStruct(errors Errors, obj interface{}) Errors {
 		typ = typ.Elem()
 		val = val.Elem()
 	}
+	checkTypeRules(typ)
 
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
 
 		// Allow ignored fields in the struct
-		if field.Tag.Get("form") == "-" || !val.Field(i).CanInterface() {
+		if field.Tag.Get("form") == "-" && !hasSourceTag(field) || !val.Field(i).CanInterface() {
+			continue
+		}
+		if !vd.isMasked(field.Name) {
+			continue
+		}
+		rules, err := vd.cfg.rules(typ, field)
+		if err != nil {
+			errors.Add([]string{field.Name}, ERR_CONFIG, "Invalid validation config: "+err.Error())
 			continue
 		}
 
 		fieldVal := val.Field(i)
 		fieldValue := fieldVal.Interface()
-		zero := reflect.Zero(field.Type).Interface()
+		fieldType := field.Type
+		zero := reflect.Zero(fieldType).Interface()
+
+		// Rules of optional fields apply to their value if present,
+		// and Required is satisfied by presence alone.
+		if isOptional(fieldType) {
+			if !fieldVal.FieldByName("Present").Bool() {
+				errors = validateField(errors, vd, rules, zero, field, fieldVal, zero)
+				continue
+			}
+			fieldVal = fieldVal.FieldByName("Value")
+			fieldValue = fieldVal.Interface()
+			fieldType = fieldVal.Type()
+			zero = reflect.Zero(fieldType).Interface()
+		}
 
 		// Validate nested and embedded structs (if pointer, only do so if not nil)
-		if field.Type.Kind() == reflect.Struct ||
-			(field.Type.Kind() == reflect.Ptr && !reflect.DeepEqual(zero, fieldValue) &&
-				field.Type.Elem().Kind() == reflect.Struct) {
-			errors = validateStruct(errors, fieldValue)
+		if fieldType.Kind() == reflect.Struct ||
+			(fieldType.Kind() == reflect.Ptr && !reflect.DeepEqual(zero, fieldValue) &&
+				fieldType.Elem().Kind() == reflect.Struct) {
+			// Pass nested structs by address when possible so that rules
+			// which modify values (e.g. Default) can reach their fields.
+			if fieldVal.Kind() == reflect.Struct && fieldVal.CanAddr() {
+				errors = validateStruct(errors, vd.at(field.Name), fieldVal.Addr().Interface())
+			} else {
+				errors = validateStruct(errors, vd.at(field.Name), fieldValue)
+			}
+		}
+		if fieldType != field.Type && reflect.DeepEqual(zero, fieldValue) {
+			continue
+		}
+		errors = validateField(errors, vd, rules, zero, field, fieldVal, fieldValue)
+	}
+
+	if len(expressionTags) > 0 {
+		for i := 0; i < typ.NumField(); i++ {
+			errors = validateExpressions(errors, val, i)
 		}
-		errors = validateField(errors, zero, field, fieldVal, fieldValue)
 	}
 	return errors
 }
@@ -366,31 +874,32 @@ func ensureNotPointer(obj interface{}) {
 	}
 }
 
-func validateField(errors Errors, zero interface{}, field reflect.StructField, fieldVal reflect.Value, fieldValue interface{}) Errors {
-	if fieldVal.Kind() == reflect.Slice {
-		for i := 0; i < fieldVal.Len(); i++ {
-			sliceVal := fieldVal.Index(i)
-			if sliceVal.Kind() == reflect.Ptr {
-				sliceVal = sliceVal.Elem()
-			}
-
-			sliceValue := sliceVal.Interface()
-			zero := reflect.Zero(sliceVal.Type()).Interface()
-			if sliceVal.Kind() == reflect.Struct ||
-				(sliceVal.Kind() == reflect.Ptr && !reflect.DeepEqual(zero, sliceValue) &&
-					sliceVal.Elem().Kind() == reflect.Struct) {
-				errors = validateStruct(errors, sliceValue)
-			}
-			/* Apply validation rules to each item in a slice. ISSUE #3
-			else {
-				errors = validateField(errors, zero, field, sliceVal, sliceValue)
-			}*/
-		}
+func validateField(errors Errors, vd *validation, rules []string, zero interface{}, field reflect.StructField, fieldVal reflect.Value, fieldValue interface{}) Errors {
+	switch fieldVal.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		errors = validateElements(errors, vd, field.Name, fieldVal)
 	}
 
-	rules := strings.Split(field.Tag.Get("binding"), ";")
+	rules, elemRules, keyRules := splitDive(rules)
+	provided := vd.presence && vd.isProvided(field.Name)
+	n := len(errors)
+	errors = validateRules(errors, rules, provided, zero, field, fieldVal, fieldValue)
+	renderMessages(errors[n:], vd.cfg.locale(), rules, field.Name, fieldValue)
+	if vd.b != nil && len(rules) > 0 && rules[0] != "" {
+		vd.b.trace(TraceEvent{Stage: TRACE_VALIDATE, Field: vd.prefix + field.Name, Rules: rules, Errors: errors[n:]})
+	}
+	if elemRules != nil || keyRules != nil {
+		errors = validateDive(errors, vd, elemRules, keyRules, field, fieldVal)
+	}
+	return errors
+}
 
-	if reflect.DeepEqual(zero, fieldValue) {
+// validateRules applies rules to the value of a field. Provided reports
+// whether the field was present in the request when RequirePresence is set;
+// submitted zero numbers and booleans then satisfy Required and are checked
+// by the other rules like any other value.
+func validateRules(errors Errors, rules []string, provided bool, zero interface{}, field reflect.StructField, fieldVal reflect.Value, fieldValue interface{}) Errors {
+	if reflect.DeepEqual(zero, fieldValue) && !(provided && isPresenceKind(fieldVal.Kind())) {
 		for _, rule := range rules {
 			if rule == "Required" {
 				errors.Add([]string{field.Name}, ERR_REQUIRED, "Required")
@@ -409,6 +918,11 @@ func validateField(errors Errors, zero interface{}, field reflect.StructField, f
 		return errors
 	}
 
+	if err := cursorError(reflect.ValueOf(fieldValue)); err != nil {
+		errors.Add([]string{field.Name}, ERR_CURSOR, err.Error())
+		return errors
+	}
+
 VALIDATE_RULES:
 	for _, rule := range rules {
 		if len(rule) == 0 {
@@ -422,6 +936,10 @@ VALIDATE_RULES:
 			continue
 		case rule == "OmitEmpty": // legacy
 			continue
+		case rule == "Sensitive":
+			continue
+		case rule == "Dive" || rule == "Keys" || rule == "EndKeys":
+			continue
 
 		case rule == "AlphaDash":
 			if AlphaDashPattern.MatchString(fmt.Sprintf("%v", fieldValue)) {
@@ -433,13 +951,28 @@ VALIDATE_RULES:
 				errors.Add([]string{field.Name}, ERR_ALPHA_DASH_DOT, "AlphaDashDot")
 				break VALIDATE_RULES
 			}
+		case rule == "Alpha":
+			if AlphaPattern.MatchString(ruleString(fieldValue)) {
+				errors.Add([]string{field.Name}, ERR_ALPHA, "Alpha")
+				break VALIDATE_RULES
+			}
+		case rule == "AlphaNumeric":
+			if AlphaNumericPattern.MatchString(ruleString(fieldValue)) {
+				errors.Add([]string{field.Name}, ERR_ALPHA_NUMERIC, "AlphaNumeric")
+				break VALIDATE_RULES
+			}
+		case rule == "AlphaUnicode":
+			if AlphaUnicodePattern.MatchString(ruleString(fieldValue)) {
+				errors.Add([]string{field.Name}, ERR_ALPHA_UNICODE, "AlphaUnicode")
+				break VALIDATE_RULES
+			}
 		case strings.HasPrefix(rule, "Size("):
 			size, _ := strconv.Atoi(rule[5 : len(rule)-1])
 			if str, ok := fieldValue.(string); ok && utf8.RuneCountInString(str) != size {
 				errors.Add([]string{field.Name}, ERR_SIZE, "Size")
 				break VALIDATE_RULES
 			}
-			if fieldVal.Kind() == reflect.Slice && fieldVal.Len() != size {
+			if (fieldVal.Kind() == reflect.Slice || fieldVal.Kind() == reflect.Map) && fieldVal.Len() != size {
 				errors.Add([]string{field.Name}, ERR_SIZE, "Size")
 				break VALIDATE_RULES
 			}
@@ -449,7 +982,7 @@ VALIDATE_RULES:
 				errors.Add([]string{field.Name}, ERR_MIN_SIZE, "MinSize")
 				break VALIDATE_RULES
 			}
-			if fieldVal.Kind() == reflect.Slice && fieldVal.Len() < min {
+			if (fieldVal.Kind() == reflect.Slice || fieldVal.Kind() == reflect.Map) && fieldVal.Len() < min {
 				errors.Add([]string{field.Name}, ERR_MIN_SIZE, "MinSize")
 				break VALIDATE_RULES
 			}
@@ -459,10 +992,42 @@ VALIDATE_RULES:
 				errors.Add([]string{field.Name}, ERR_MAX_SIZE, "MaxSize")
 				break VALIDATE_RULES
 			}
-			if fieldVal.Kind() == reflect.Slice && fieldVal.Len() > max {
+			if (fieldVal.Kind() == reflect.Slice || fieldVal.Kind() == reflect.Map) && fieldVal.Len() > max {
 				errors.Add([]string{field.Name}, ERR_MAX_SIZE, "MaxSize")
 				break VALIDATE_RULES
 			}
+		case strings.HasPrefix(rule, "MaxBits("):
+			max, _ := strconv.Atoi(rule[8 : len(rule)-1])
+			if n, ok := bitLen(fieldValue); ok && n > max {
+				errors.Add([]string{field.Name}, ERR_MAX_BITS, "MaxBits")
+				break VALIDATE_RULES
+			}
+		case strings.HasPrefix(rule, "MaxFileSize("):
+			max, _ := parseByteSize(rule[12 : len(rule)-1])
+			for _, file := range uploadedFiles(fieldValue) {
+				if file.Size > max {
+					errors.Add([]string{field.Name}, ERR_FILE_SIZE, "MaxFileSize")
+					break VALIDATE_RULES
+				}
+			}
+		case strings.HasPrefix(rule, "FileType("):
+			types := strings.Split(rule[9:len(rule)-1], ",")
+			for _, file := range uploadedFiles(fieldValue) {
+				if !isFileType(file, types) {
+					errors.Add([]string{field.Name}, ERR_FILE_TYPE, "FileType")
+					break VALIDATE_RULES
+				}
+			}
+		case strings.HasPrefix(rule, "MinDuration("):
+			if d, ok := durationValue(fieldValue); ok && d < parseDurationParam(rule, 12) {
+				errors.Add([]string{field.Name}, ERR_MIN_DURATION, "MinDuration")
+				break VALIDATE_RULES
+			}
+		case strings.HasPrefix(rule, "MaxDuration("):
+			if d, ok := durationValue(fieldValue); ok && d > parseDurationParam(rule, 12) {
+				errors.Add([]string{field.Name}, ERR_MAX_DURATION, "MaxDuration")
+				break VALIDATE_RULES
+			}
 		case strings.HasPrefix(rule, "Range("):
 			nums := strings.Split(rule[6:len(rule)-1], ",")
 			if len(nums) != 2 {
@@ -473,17 +1038,149 @@ VALIDATE_RULES:
 				errors.Add([]string{field.Name}, ERR_RANGE, "Range")
 				break VALIDATE_RULES
 			}
+		case strings.HasPrefix(rule, "Min("):
+			if in, ok := inNumberRange(fieldValue, rule[4:len(rule)-1], ""); ok && !in {
+				errors.Add([]string{field.Name}, ERR_MIN, "Min")
+				break VALIDATE_RULES
+			}
+		case strings.HasPrefix(rule, "Max("):
+			if in, ok := inNumberRange(fieldValue, "", rule[4:len(rule)-1]); ok && !in {
+				errors.Add([]string{field.Name}, ERR_MAX, "Max")
+				break VALIDATE_RULES
+			}
+		case strings.HasPrefix(rule, "Between("):
+			bounds := strings.Split(rule[8:len(rule)-1], ",")
+			if len(bounds) != 2 {
+				break VALIDATE_RULES
+			}
+			if in, ok := inNumberRange(fieldValue, bounds[0], bounds[1]); ok && !in {
+				errors.Add([]string{field.Name}, ERR_BETWEEN, "Between")
+				break VALIDATE_RULES
+			}
 		case rule == "Email":
-			if !EmailPattern.MatchString(fmt.Sprintf("%v", fieldValue)) {
+			if !EmailPattern.MatchString(emailAddress(fieldValue)) {
 				errors.Add([]string{field.Name}, ERR_EMAIL, "Email")
 				break VALIDATE_RULES
 			}
+		case rule == "UUID":
+			if !isUUID(fieldValue, 0) {
+				errors.Add([]string{field.Name}, ERR_UUID, "UUID")
+				break VALIDATE_RULES
+			}
+		case rule == "UUID4":
+			if !isUUID(fieldValue, 4) {
+				errors.Add([]string{field.Name}, ERR_UUID, "UUID4")
+				break VALIDATE_RULES
+			}
+		case rule == "IP":
+			if !isIP(fieldValue, 0) {
+				errors.Add([]string{field.Name}, ERR_IP, "IP")
+				break VALIDATE_RULES
+			}
+		case rule == "IPv4":
+			if !isIP(fieldValue, 4) {
+				errors.Add([]string{field.Name}, ERR_IP, "IPv4")
+				break VALIDATE_RULES
+			}
+		case rule == "IPv6":
+			if !isIP(fieldValue, 6) {
+				errors.Add([]string{field.Name}, ERR_IP, "IPv6")
+				break VALIDATE_RULES
+			}
+		case rule == "CIDR":
+			if !isCIDR(fieldValue) {
+				errors.Add([]string{field.Name}, ERR_CIDR, "CIDR")
+				break VALIDATE_RULES
+			}
+		case rule == "MAC":
+			if !isMAC(fieldValue) {
+				errors.Add([]string{field.Name}, ERR_MAC, "MAC")
+				break VALIDATE_RULES
+			}
+		case rule == "Hostname":
+			if !isHostname(fieldValue) {
+				errors.Add([]string{field.Name}, ERR_HOSTNAME, "Hostname")
+				break VALIDATE_RULES
+			}
+		case rule == "FQDN":
+			if !isFQDN(fieldValue) {
+				errors.Add([]string{field.Name}, ERR_FQDN, "FQDN")
+				break VALIDATE_RULES
+			}
+		case rule == "DNSLabel":
+			if !isDNSLabel(ruleString(fieldValue)) {
+				errors.Add([]string{field.Name}, ERR_DNS_LABEL, "DNSLabel")
+				break VALIDATE_RULES
+			}
+		case rule == "Port":
+			if !isPort(fieldValue) {
+				errors.Add([]string{field.Name}, ERR_PORT, "Port")
+				break VALIDATE_RULES
+			}
+		case rule == "HostPort":
+			if !isHostPort(fieldValue) {
+				errors.Add([]string{field.Name}, ERR_HOST_PORT, "HostPort")
+				break VALIDATE_RULES
+			}
+		case rule == "Json":
+			if !isJSON(fieldValue) {
+				errors.Add([]string{field.Name}, ERR_JSON, "Json")
+				break VALIDATE_RULES
+			}
+		case rule == "Base64":
+			if !isBase64(fieldValue) {
+				errors.Add([]string{field.Name}, ERR_BASE64, "Base64")
+				break VALIDATE_RULES
+			}
+		case rule == "Base64URL":
+			if !isBase64URL(fieldValue) {
+				errors.Add([]string{field.Name}, ERR_BASE64_URL, "Base64URL")
+				break VALIDATE_RULES
+			}
+		case rule == "Hexadecimal":
+			if !isHexadecimal(fieldValue) {
+				errors.Add([]string{field.Name}, ERR_HEXADECIMAL, "Hexadecimal")
+				break VALIDATE_RULES
+			}
+		case rule == "HexColor":
+			if !isHexColor(fieldValue) {
+				errors.Add([]string{field.Name}, ERR_HEX_COLOR, "HexColor")
+				break VALIDATE_RULES
+			}
+		case rule == "CreditCard" || strings.HasPrefix(rule, "CreditCard("):
+			var brands string
+			if rule != "CreditCard" {
+				brands = rule[11 : len(rule)-1]
+			}
+			if !isCreditCard(fieldValue, brands) {
+				errors.Add([]string{field.Name}, ERR_CREDIT_CARD, "CreditCard")
+				break VALIDATE_RULES
+			}
+		case rule == "Phone" || strings.HasPrefix(rule, "Phone("):
+			var format string
+			if rule != "Phone" {
+				format = rule[6 : len(rule)-1]
+			}
+			if !isPhone(fieldValue, format) {
+				errors.Add([]string{field.Name}, ERR_PHONE, "Phone")
+				break VALIDATE_RULES
+			}
 		case rule == "Url":
 			str := fmt.Sprintf("%v", fieldValue)
 			if !isURL(str) {
 				errors.Add([]string{field.Name}, ERR_URL, "Url")
 				break VALIDATE_RULES
 			}
+		case strings.HasPrefix(rule, "Scheme("):
+			if !hasScheme(fieldValue, rule[7:len(rule)-1]) {
+				errors.Add([]string{field.Name}, ERR_SCHEME, "Scheme")
+				break VALIDATE_RULES
+			}
+		case strings.HasPrefix(rule, "Match("):
+			if !matchPattern(rule[6 : len(rule)-1]).MatchString(ruleString(fieldValue)) {
+				errors.Add([]string{field.Name}, ERR_MATCH, "Match")
+				break VALIDATE_RULES
+			}
 		case strings.HasPrefix(rule, "In("):
 			if !in(fieldValue, rule[3:len(rule)-1]) {
 				errors.Add([]string{field.Name}, ERR_IN, "In")
@@ -504,6 +1201,33 @@ VALIDATE_RULES:
 				errors.Add([]string{field.Name}, ERR_EXCLUDE, "Exclude")
 				break VALIDATE_RULES
 			}
+		case rule == "SearchQuery" || strings.HasPrefix(rule, "SearchQuery("):
+			strVal := fieldVal
+			if strVal.Kind() == reflect.Ptr && !strVal.IsNil() {
+				strVal = strVal.Elem()
+			}
+			if strVal.Kind() != reflect.String {
+				continue
+			}
+			var maxLen int
+			var escape bool
+			if strings.HasPrefix(rule, "SearchQuery(") {
+				params := strings.Split(rule[12:len(rule)-1], ",")
+				maxLen, _ = strconv.Atoi(strings.TrimSpace(params[0]))
+				escape = len(params) > 1 && strings.TrimSpace(params[1]) == "escape"
+			}
+			sanitized := SanitizeSearchQuery(strVal.String(), maxLen, escape)
+			if sanitized == strVal.String() {
+				continue
+			}
+			if !strVal.CanSet() {
+				errors.Add([]string{field.Name}, ERR_SEARCH_QUERY, "SearchQuery")
+				break VALIDATE_RULES
+			}
+			strVal.SetString(sanitized)
+			if fieldVal.Kind() == reflect.String {
+				fieldValue = sanitized
+			}
 		default:
 			// Apply custom validation rules
 			var isValid bool
@@ -552,47 +1276,162 @@ func SetNameMapper(nm NameMapper) {
 	nameMapper = nm
 }
 
+// FormName returns the form key the struct field is bound from, taking
+// its form tag and the name mapper into account, or "" if the field is
+// ignored with `form:"-"`.
+func FormName(field reflect.StructField) string {
+	name := parseFormName(field.Name, field.Tag.Get("form"))
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// isNestedStruct reports whether fields of type typ hold structs whose
+// own fields are bound, rather than values bound as a whole.
+func isNestedStruct(typ reflect.Type) bool {
+	_, ok := converters[typ]
+	return !ok && typ.Kind() == reflect.Struct && !isOptional(typ) && !reflect.PtrTo(typ).Implements(unmarshalerType) &&
+		!reflect.PtrTo(typ).Implements(textUnmarshalerType)
+}
+
+// isSingleValue reports whether slices of type typ, such as net.IP, are
+// converted from a single value rather than from repeated values.
+func isSingleValue(typ reflect.Type) bool {
+	_, ok := converters[typ]
+	return ok || reflect.PtrTo(typ).Implements(textUnmarshalerType)
+}
+
+// setFieldValues converts the values submitted for the struct field
+// under name and sets the field to them, recording it as present.
+func setFieldValues(typeField reflect.StructField, structField reflect.Value, name string, inputValue []string,
+	source string, present fieldPaths, b *Binder, errors Errors) Errors {
+
+	present.add(typeField.Name)
+	sensitive := isSensitive(typeField)
+
+	// Optional fields are marked as present and bind their value.
+	target := structField
+	if isOptional(typeField.Type) {
+		structField.FieldByName("Present").SetBool(true)
+		target = structField.FieldByName("Value")
+	}
+
+	n := len(errors)
+	numElems := len(inputValue)
+	if target.Kind() == reflect.Slice && numElems > 0 && !isSingleValue(target.Type()) {
+		sliceOf := target.Type().Elem().Kind()
+		slice := reflect.MakeSlice(target.Type(), numElems, numElems)
+		for i := 0; i < numElems; i++ {
+			n := len(errors)
+			var ok bool
+			if errors, ok = setTimeValue(typeField, slice.Index(i), inputValue[i], b.timeLayout, name, errors); !ok {
+				errors = setLocalizedValue(typeField, sliceOf, inputValue[i], slice.Index(i), name, source, b.numberLocale, errors)
+			}
+			annotateErrors(errors[n:], slice.Index(i).Type(), inputValue[i], source, sensitive)
+		}
+		target.Set(slice)
+	} else {
+		n := len(errors)
+		var ok bool
+		if errors, ok = setTimeValue(typeField, target, inputValue[0], b.timeLayout, name, errors); !ok {
+			errors = setLocalizedValue(typeField, target.Kind(), inputValue[0], target, name, source, b.numberLocale, errors)
+		}
+		annotateErrors(errors[n:], target.Type(), inputValue[0], source, sensitive)
+	}
+	ev := TraceEvent{Stage: TRACE_BIND, Field: present.path(typeField.Name), Source: source}
+	if !sensitive {
+		ev.Value = strings.Join(inputValue, ",")
+	}
+	if len(errors) > n {
+		ev.Stage, ev.Errors = TRACE_REJECT, errors[n:]
+	}
+	b.trace(ev)
+	return errors
+}
+
 // Takes values from the form data and puts them into a struct
-func mapForm(formStruct reflect.Value, form map[string][]string,
-	formfile map[string][]*multipart.FileHeader, errors Errors) Errors {
+// Fields are named by their tag called tag, if they have one, or else by
+// their form tag; see inputName. Fields of nested structs are also bound
+// from keys prefixed with the name of the struct field, as in
+// "author.name" or "author[name]"; see nestedForm. Slices of structs are
+// bound from indexed keys such as "items[0].sku"; see mapFormSlice.
+// Fields of type map[string]string or map[string][]string are bound from
+// keys such as "meta[color]", or, if named "*", from all keys that are
+// not bound to another field of the struct.
+// sourceOf reports which part of the request a form key was read from;
+// if it is nil, all keys are reported as SOURCE_FORM. b holds the
+// settings of the request.
+func mapForm(formStruct reflect.Value, form map[string][]string, formfile map[string][]*multipart.FileHeader,
+	tag string, sourceOf func(string) string, present fieldPaths, b *Binder, errors Errors) Errors {
 
 	if formStruct.Kind() == reflect.Ptr {
 		formStruct = formStruct.Elem()
 	}
 	typ := formStruct.Type()
+	var restField reflect.Value
+	var restName string
 
 	for i := 0; i < typ.NumField(); i++ {
 		typeField := typ.Field(i)
 		structField := formStruct.Field(i)
 
 		if typeField.Type.Kind() == reflect.Ptr && typeField.Anonymous {
-			structField.Set(reflect.New(typeField.Type.Elem()))
-			errors = mapForm(structField.Elem(), form, formfile, errors)
-			if reflect.DeepEqual(structField.Elem().Interface(), reflect.Zero(structField.Elem().Type()).Interface()) {
+			// Keep embedded structs of existing values bound over.
+			allocated := structField.IsNil()
+			if allocated {
+				structField.Set(reflect.New(typeField.Type.Elem()))
+			}
+			n := present.len()
+			errors = mapForm(structField.Elem(), form, formfile, tag, sourceOf, present.at(typeField.Name), b, errors)
+			present.addParent(typeField.Name, n)
+			if allocated && reflect.DeepEqual(structField.Elem().Interface(), reflect.Zero(structField.Elem().Type()).Interface()) {
 				structField.Set(reflect.Zero(structField.Type()))
 			}
-		} else if typeField.Type.Kind() == reflect.Struct {
-			errors = mapForm(structField, form, formfile, errors)
+		} else if hasFormOption(typeField.Tag.Get("form"), "json") {
+			name := parseFormName(typeField.Name, typeField.Tag.Get("form"))
+			errors = mapJSONPart(structField, typeField.Name, name, form[name], formfile[name], present, errors)
+			continue
+		} else if isNestedStruct(typeField.Type) {
+			n := present.len()
+			nestedValues, nestedFiles, nestedSource := nestedForm(inputName(typeField, tag), form, formfile, sourceOf, true)
+			errors = mapForm(structField, nestedValues, nestedFiles, tag, nestedSource, present.at(typeField.Name), b, errors)
+			present.addParent(typeField.Name, n)
 		}
 
-		inputFieldName := parseFormName(typeField.Name, typeField.Tag.Get("form"))
+		inputFieldName := inputName(typeField, tag)
 		if len(inputFieldName) == 0 || !structField.CanSet() {
 			continue
 		}
 
+		if structSlice(typeField.Type) {
+			var ok bool
+			if errors, ok = mapFormSlice(structField, inputFieldName, form, formfile, tag, sourceOf, present.at(typeField.Name), b, errors); ok {
+				present.add(typeField.Name)
+				continue
+			}
+		}
+		if isFormMap(typeField.Type) {
+			if inputFieldName == formRest {
+				restField, restName = structField, typeField.Name
+			} else if setFormMap(structField, form, func(key string) (string, bool) { return mapKey(inputFieldName, key) }) {
+				present.add(typeField.Name)
+			}
+			continue
+		}
+
 		inputValue, exists := form[inputFieldName]
+		if exists && len(inputValue) > 0 && inputValue[0] == "" && structField.Kind() == reflect.Ptr &&
+			(b.emptyAsNil || hasFormOption(typeField.Tag.Get("form"), "omitempty")) {
+			// Empty inputs of pointer fields are treated as absent.
+			continue
+		}
 		if exists {
-			numElems := len(inputValue)
-			if structField.Kind() == reflect.Slice && numElems > 0 {
-				sliceOf := structField.Type().Elem().Kind()
-				slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
-				for i := 0; i < numElems; i++ {
-					errors = setWithProperType(sliceOf, inputValue[i], slice.Index(i), inputFieldName, errors)
-				}
-				formStruct.Field(i).Set(slice)
-			} else {
-				errors = setWithProperType(typeField.Type.Kind(), inputValue[0], structField, inputFieldName, errors)
+			source := SOURCE_FORM
+			if sourceOf != nil {
+				source = sourceOf(inputFieldName)
 			}
+			errors = setFieldValues(typeField, structField, inputFieldName, inputValue, source, present, b, errors)
 			continue
 		}
 
@@ -600,6 +1439,8 @@ func mapForm(formStruct reflect.Value, form map[string][]string,
 		if !exists {
 			continue
 		}
+		present.add(typeField.Name)
+		b.trace(TraceEvent{Stage: TRACE_BIND, Field: present.path(typeField.Name), Source: SOURCE_FORM, Value: inputFile[0].Filename})
 		fhType := reflect.TypeOf((*multipart.FileHeader)(nil))
 		numElems := len(inputFile)
 		if structField.Kind() == reflect.Slice && numElems > 0 && structField.Type().Elem() == fhType {
@@ -612,6 +1453,169 @@ func mapForm(formStruct reflect.Value, form map[string][]string,
 			structField.Set(reflect.ValueOf(inputFile[0]))
 		}
 	}
+
+	if restField.IsValid() && setFormMap(restField, form, func(key string) (string, bool) { return key, !formKeyBound(typ, tag, key) }) {
+		present.add(restName)
+	}
+	return errors
+}
+
+// hasFormOption reports whether the form tag carries the given option
+// after the field name, as in `form:"metadata,json"`.
+func hasFormOption(tag, option string) bool {
+	opts := strings.Split(tag, ",")
+	for _, opt := range opts[1:] {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// mapJSONPart decodes a form value or multipart part holding a JSON
+// document into field, so that a `metadata` part can carry a nested model
+// next to file parts. Parts sent with a filename are read from the file.
+func mapJSONPart(field reflect.Value, fieldName, name string, values []string,
+	files []*multipart.FileHeader, present fieldPaths, errors Errors) Errors {
+
+	var data []byte
+	switch {
+	case len(values) > 0:
+		data = []byte(values[0])
+	case len(files) > 0:
+		f, err := files[0].Open()
+		if err == nil {
+			data, err = ioutil.ReadAll(f)
+			f.Close()
+		}
+		if err != nil {
+			errors.Add([]string{name}, ERR_DESERIALIZATION, err.Error())
+			return errors
+		}
+	default:
+		return errors
+	}
+
+	present.add(fieldName)
+	if err := json.Unmarshal(data, field.Addr().Interface()); err != nil {
+		errors.Add([]string{name}, ERR_DESERIALIZATION, err.Error())
+		return errors
+	}
+	var doc interface{}
+	if json.Unmarshal(data, &doc) == nil {
+		addJSONPresence(present.at(fieldName), field.Type(), doc)
+	}
+	return errors
+}
+
+// isSensitive reports whether a field is marked with the Sensitive rule,
+// meaning its submitted value must not be echoed back in errors.
+func isSensitive(field reflect.StructField) bool {
+	for _, rule := range splitRules(field.Tag.Get("binding")) {
+		if rule == "Sensitive" {
+			return true
+		}
+	}
+	return false
+}
+
+// annotateErrors records the expected type, the raw value and the source
+// of a value on the errors produced while converting it.
+func annotateErrors(errors Errors, typ reflect.Type, val, source string, sensitive bool) {
+	for i := range errors {
+		errors[i].ExpectedType = typ.String()
+		errors[i].Source = source
+		if !sensitive {
+			errors[i].Value = val
+		}
+	}
+}
+
+// jsonErrorField finds the field a JSON type error refers to by the names
+// of the field and of the struct type declaring it, which is empty for
+// unnamed struct types. The struct types within typ are searched breadth
+// first.
+func jsonErrorField(typ reflect.Type, structName, fieldName string) (reflect.StructField, bool) {
+	seen := map[reflect.Type]bool{}
+	for queue := []reflect.Type{typ}; len(queue) > 0; queue = queue[1:] {
+		t := queue[0]
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || seen[t] {
+			continue
+		}
+		seen[t] = true
+		if t.Name() == structName {
+			if field, ok := t.FieldByName(fieldName); ok {
+				return field, true
+			}
+		}
+		for i := 0; i < t.NumField(); i++ {
+			queue = append(queue, t.Field(i).Type)
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// jsonErrorValue returns the JSON value at offset in data, where a type
+// error was found, with strings unquoted.
+func jsonErrorValue(data []byte, offset int64) string {
+	if offset < 0 || offset > int64(len(data)) {
+		return ""
+	}
+	var raw json.RawMessage
+	if err := json.NewDecoder(bytes.NewReader(data[offset:])).Decode(&raw); err != nil {
+		return ""
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str
+	}
+	return string(raw)
+}
+
+// setLocalizedValue sets a single value from the request, reading floats
+// in the number locale of the field, or else in locale; see localizeFloat.
+func setLocalizedValue(typeField reflect.StructField, valueKind reflect.Kind, val string, structField reflect.Value, nameInTag, source, locale string, errors Errors) Errors {
+	val, ok := localizeFloat(typeField, valueKind, val, locale)
+	if !ok {
+		errors.Add([]string{nameInTag}, ERR_FLOAT_TYPE, "Value could not be parsed as float")
+		return errors
+	}
+	return setValue(valueKind, val, structField, nameInTag, source, errors)
+}
+
+// setValue sets a single value from the request, letting fields that
+// implement Unmarshaler parse it themselves.
+func setValue(valueKind reflect.Kind, val string, structField reflect.Value, nameInTag, source string, errors Errors) Errors {
+	if valueKind == reflect.Ptr && structField.Type().Implements(unmarshalerType) {
+		ptr := reflect.New(structField.Type().Elem())
+		if err := ptr.Interface().(Unmarshaler).UnmarshalBinding(source, val); err != nil {
+			return addConversionError(errors, nameInTag, err)
+		}
+		structField.Set(ptr)
+		return errors
+	}
+	if structField.CanAddr() {
+		if u, ok := structField.Addr().Interface().(Unmarshaler); ok {
+			if err := u.UnmarshalBinding(source, val); err != nil {
+				errors = addConversionError(errors, nameInTag, err)
+			}
+			return errors
+		}
+	}
+	return setWithProperType(valueKind, val, structField, nameInTag, errors)
+}
+
+// addConversionError adds an error returned while converting a value.
+// Errors of type Error keep their classification and message.
+func addConversionError(errors Errors, name string, err error) Errors {
+	if e, ok := err.(Error); ok {
+		errors.Add([]string{name}, e.Classification, e.Message)
+	} else {
+		errors.Add([]string{name}, ERR_DESERIALIZATION, err.Error())
+	}
 	return errors
 }
 
@@ -620,13 +1624,47 @@ func mapForm(formStruct reflect.Value, form map[string][]string,
 // same type, so that not all deserialized values have to be strings.
 // Supported types are string, int, float, and bool.
 func setWithProperType(valueKind reflect.Kind, val string, structField reflect.Value, nameInTag string, errors Errors) Errors {
+	if convert, ok := converters[structField.Type()]; ok {
+		v, err := convert(val)
+		if err != nil {
+			errors = addConversionError(errors, nameInTag, err)
+		} else {
+			structField.Set(reflect.ValueOf(v))
+		}
+		return errors
+	}
+
+	// Pointers to primitive or convertible types are set to a new value,
+	// so that a submitted zero value can be told apart from a missing one.
+	if valueKind == reflect.Ptr && isBindablePtr(structField.Type()) {
+		ptr := reflect.New(structField.Type().Elem())
+		n := len(errors)
+		errors = setWithProperType(ptr.Elem().Kind(), val, ptr.Elem(), nameInTag, errors)
+		if len(errors) == n {
+			structField.Set(ptr)
+		}
+		return errors
+	}
+
+	// Types such as enums and IDs may parse their text themselves.
+	if structField.CanAddr() {
+		if u, ok := structField.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(val)); err != nil {
+				errors = addConversionError(errors, nameInTag, err)
+			}
+			return errors
+		}
+	}
+
 	switch valueKind {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		if val == "" {
 			val = "0"
 		}
-		intVal, err := strconv.ParseInt(val, 10, 64)
-		if err != nil {
+		intVal, err := strconv.ParseInt(val, 10, structField.Type().Bits())
+		if isRangeError(err) {
+			errors = addOverflowError(errors, nameInTag, structField.Type())
+		} else if err != nil {
 			errors.Add([]string{nameInTag}, ERR_INTERGER_TYPE, "Value could not be parsed as integer")
 		} else {
 			structField.SetInt(intVal)
@@ -635,33 +1673,30 @@ func setWithProperType(valueKind reflect.Kind, val string, structField reflect.V
 		if val == "" {
 			val = "0"
 		}
-		uintVal, err := strconv.ParseUint(val, 10, 64)
-		if err != nil {
+		uintVal, err := strconv.ParseUint(val, 10, structField.Type().Bits())
+		if _, intErr := strconv.ParseInt(val, 10, 64); isRangeError(err) || err != nil && intErr == nil {
+			// Negative numbers are out of range rather than malformed.
+			errors = addOverflowError(errors, nameInTag, structField.Type())
+		} else if err != nil {
 			errors.Add([]string{nameInTag}, ERR_INTERGER_TYPE, "Value could not be parsed as unsigned integer")
 		} else {
 			structField.SetUint(uintVal)
 		}
 	case reflect.Bool:
-		if val == "on" {
-			structField.SetBool(true)
-			break
-		}
-
-		if val == "" {
-			val = "false"
-		}
-		boolVal, err := strconv.ParseBool(val)
+		boolVal, err := parseBool(val)
 		if err != nil {
 			errors.Add([]string{nameInTag}, ERR_BOOLEAN_TYPE, "Value could not be parsed as boolean")
-		} else if boolVal {
-			structField.SetBool(true)
+		} else {
+			structField.SetBool(boolVal)
 		}
 	case reflect.Float32:
 		if val == "" {
 			val = "0.0"
 		}
 		floatVal, err := strconv.ParseFloat(val, 32)
-		if err != nil {
+		if isRangeError(err) {
+			errors = addOverflowError(errors, nameInTag, structField.Type())
+		} else if err != nil {
 			errors.Add([]string{nameInTag}, ERR_FLOAT_TYPE, "Value could not be parsed as 32-bit float")
 		} else {
 			structField.SetFloat(floatVal)
@@ -671,7 +1706,9 @@ func setWithProperType(valueKind reflect.Kind, val string, structField reflect.V
 			val = "0.0"
 		}
 		floatVal, err := strconv.ParseFloat(val, 64)
-		if err != nil {
+		if isRangeError(err) {
+			errors = addOverflowError(errors, nameInTag, structField.Type())
+		} else if err != nil {
 			errors.Add([]string{nameInTag}, ERR_FLOAT_TYPE, "Value could not be parsed as 64-bit float")
 		} else {
 			structField.SetFloat(floatVal)
@@ -682,6 +1719,91 @@ func setWithProperType(valueKind reflect.Kind, val string, structField reflect.V
 	return errors
 }
 
+// Converter converts a raw form value into a value of a particular type.
+// If the returned error is an Error, its classification and message are
+// reported for the field; other errors are reported as ERR_DESERIALIZATION.
+type Converter func(string) (interface{}, error)
+
+// optional is implemented by Optional. It is declared here so that
+// binding and validation work without type parameters.
+type optional interface {
+	isOptional()
+}
+
+var (
+	optionalType        = reflect.TypeOf((*optional)(nil)).Elem()
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func isOptional(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ.Implements(optionalType)
+}
+
+// parseBool parses a form value as a boolean, accepting "on" as sent
+// by checkboxes and treating an empty value as false.
+func parseBool(val string) (bool, error) {
+	switch val {
+	case "on":
+		return true, nil
+	case "":
+		return false, nil
+	}
+	return strconv.ParseBool(val)
+}
+
+func isBindablePtr(typ reflect.Type) bool {
+	if _, ok := converters[typ.Elem()]; ok {
+		return true
+	}
+	if typ.Implements(textUnmarshalerType) {
+		return true
+	}
+	switch typ.Elem().Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isRangeError(err error) bool {
+	numErr, ok := err.(*strconv.NumError)
+	return ok && numErr.Err == strconv.ErrRange
+}
+
+func addOverflowError(errors Errors, name string, typ reflect.Type) Errors {
+	errors.Add([]string{name}, ERR_OVERFLOW, fmt.Sprintf("Value of field %s overflows %s", name, typ))
+	return errors
+}
+
+// converters maps field types that are not bound by their kind to
+// functions converting a raw form value into a value of that type.
+var converters = map[reflect.Type]Converter{
+	reflect.TypeOf(Cursor{}): func(val string) (interface{}, error) {
+		var c Cursor
+		c.decode(val)
+		return c, nil
+	},
+}
+
+// AddConverter registers a converter for fields of the same type as typ,
+// so that such fields can be bound from form values, e.g. below. Structs
+// without an unmarshaler of their own are also converted from JSON
+// strings, numbers and booleans, e.g. sql.NullInt64 from {"count": 5}.
+//
+//	binding.AddConverter(decimal.Decimal{}, func(val string) (interface{}, error) {
+//		return decimal.NewFromString(val)
+//	})
+func AddConverter(typ interface{}, fn Converter) {
+	converters[reflect.TypeOf(typ)] = fn
+	jsonConvertedTypes.Range(func(key, _ interface{}) bool {
+		jsonConvertedTypes.Delete(key)
+		return true
+	})
+}
+
 // Pointers must be bind to.
 func ensurePointer(obj interface{}) {
 	if reflect.TypeOf(obj).Kind() != reflect.Ptr {
@@ -696,6 +1818,16 @@ type (
 		Error(*http.Request, Errors)
 	}
 
+	// Unmarshaler is the interface implemented by field types that parse
+	// form, query and other string values themselves.
+	Unmarshaler interface {
+		// UnmarshalBinding parses value, which was read from the given
+		// source (e.g. SOURCE_QUERY). Returning an Error controls the
+		// classification and message reported for the field; any other
+		// error is reported as ERR_DESERIALIZATION with its text.
+		UnmarshalBinding(source, value string) error
+	}
+
 	// Validator is the interface that handles some rudimentary
 	// request validation logic so your application doesn't have to.
 	Validator interface {
@@ -0,0 +1,178 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package bindingtest provides helpers for testing handlers and models
+// that use binding:
+//
+//	req := bindingtest.NewFormRequest("POST", "/posts", url.Values{"title": {""}})
+//	var form CreatePostForm
+//	errs := binding.Bind(req, &form)
+//	bindingtest.AssertHasError(t, errs, "Title", binding.ERR_REQUIRED)
+package bindingtest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gitea.com/go-chi/binding"
+	"github.com/goccy/go-json"
+)
+
+// TestingT is the subset of testing.TB used by the assertions.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// HasError reports whether errs holds an error of the given classification
+// for field. An empty field matches errors about the request as a whole.
+func HasError(errs binding.Errors, field, classification string) bool {
+	for _, err := range errs {
+		if err.Classification != classification {
+			continue
+		}
+		if field == "" && len(err.FieldNames) == 0 {
+			return true
+		}
+		for _, name := range err.FieldNames {
+			if name == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AssertHasError fails the test unless errs holds an error of the given
+// classification for field.
+func AssertHasError(t TestingT, errs binding.Errors, field, classification string) bool {
+	t.Helper()
+	if !HasError(errs, field, classification) {
+		t.Errorf("Expected %s for field %q, got %s", classification, field, describe(errs))
+		return false
+	}
+	return true
+}
+
+// AssertNoError fails the test unless errs holds no error of the given
+// classification for field.
+func AssertNoError(t TestingT, errs binding.Errors, field, classification string) bool {
+	t.Helper()
+	if HasError(errs, field, classification) {
+		t.Errorf("Unexpected %s for field %q in %s", classification, field, describe(errs))
+		return false
+	}
+	return true
+}
+
+// AssertNoErrors fails the test if errs is not empty.
+func AssertNoErrors(t TestingT, errs binding.Errors) bool {
+	t.Helper()
+	if len(errs) > 0 {
+		t.Errorf("Expected no errors, got %s", describe(errs))
+		return false
+	}
+	return true
+}
+
+// describe lists errs in failure messages.
+func describe(errs binding.Errors) string {
+	if len(errs) == 0 {
+		return "no errors"
+	}
+	parts := make([]string, len(errs))
+	for i, err := range errs {
+		parts[i] = fmt.Sprintf("%s%v: %s", err.Classification, err.FieldNames, err.Message)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// NewFormRequest returns a request with values as its form-urlencoded
+// body, or as its query string for GET and HEAD requests.
+func NewFormRequest(method, target string, values url.Values) *http.Request {
+	if method == "GET" || method == "HEAD" {
+		req := newRequest(method, target, nil)
+		query := req.URL.Query()
+		for key, vals := range values {
+			query[key] = append(query[key], vals...)
+		}
+		req.URL.RawQuery = query.Encode()
+		return req
+	}
+	req := newRequest(method, target, strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+// NewJSONRequest returns a request with body as its JSON body. A string
+// or []byte body is sent as is, other values are marshaled. It panics if
+// body cannot be marshaled.
+func NewJSONRequest(method, target string, body interface{}) *http.Request {
+	var data []byte
+	switch b := body.(type) {
+	case string:
+		data = []byte(b)
+	case []byte:
+		data = b
+	default:
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			panic("bindingtest: " + err.Error())
+		}
+	}
+	req := newRequest(method, target, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// File is a file part of a multipart request.
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// NewMultipartRequest returns a request with a multipart body holding
+// values and files, keyed by field name.
+func NewMultipartRequest(method, target string, values url.Values, files map[string][]File) *http.Request {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for key, vals := range values {
+		for _, val := range vals {
+			w.WriteField(key, val)
+		}
+	}
+	for key, fs := range files {
+		for _, f := range fs {
+			part, _ := w.CreateFormFile(key, f.Name)
+			part.Write(f.Content)
+		}
+	}
+	w.Close()
+	req := newRequest(method, target, body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func newRequest(method, target string, body io.Reader) *http.Request {
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		panic("bindingtest: " + err.Error())
+	}
+	return req
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package bindingtest
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"testing"
+
+	"gitea.com/go-chi/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+type postForm struct {
+	Title  string                `form:"title" json:"title" binding:"Required"`
+	Tags   []string              `form:"tag" json:"tags"`
+	Upload *multipart.FileHeader `form:"upload"`
+}
+
+func Test_Assertions(t *testing.T) {
+	var form postForm
+	errs := binding.Bind(NewFormRequest("POST", "/", url.Values{"tag": {"a"}}), &form)
+
+	r := &recorder{}
+	assert.True(t, AssertHasError(r, errs, "Title", binding.ERR_REQUIRED))
+	assert.True(t, AssertNoError(r, errs, "Tags", binding.ERR_REQUIRED))
+	assert.Empty(t, r.failures)
+
+	assert.False(t, AssertHasError(r, errs, "Tags", binding.ERR_REQUIRED))
+	assert.False(t, AssertNoErrors(r, errs))
+	assert.EqualValues(t, []string{
+		`Expected RequiredError for field "Tags", got RequiredError[Title]: Required`,
+		`Expected no errors, got RequiredError[Title]: Required`,
+	}, r.failures)
+
+	errs = binding.Bind(NewJSONRequest("POST", "/", "{"), &form)
+	AssertHasError(t, errs, "", binding.ERR_DESERIALIZATION)
+}
+
+func Test_Requests(t *testing.T) {
+	var form postForm
+	AssertNoErrors(t, binding.Bind(NewFormRequest("GET", "/?tag=a", url.Values{"title": {"Query"}, "tag": {"b"}}), &form))
+	assert.EqualValues(t, postForm{Title: "Query", Tags: []string{"a", "b"}}, form)
+
+	form = postForm{}
+	AssertNoErrors(t, binding.Bind(NewJSONRequest("PUT", "/", postForm{Title: "JSON", Tags: []string{"c"}}), &form))
+	assert.EqualValues(t, postForm{Title: "JSON", Tags: []string{"c"}}, form)
+
+	form = postForm{}
+	req := NewMultipartRequest("POST", "/", url.Values{"title": {"Multipart"}}, map[string][]File{
+		"upload": {{Name: "a.txt", Content: []byte("hello")}},
+	})
+	AssertNoErrors(t, binding.Bind(req, &form))
+	assert.EqualValues(t, "Multipart", form.Title)
+	assert.EqualValues(t, "a.txt", form.Upload.Filename)
+	assert.EqualValues(t, 5, form.Upload.Size)
+}
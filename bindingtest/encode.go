@@ -0,0 +1,150 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package bindingtest
+
+import (
+	"encoding"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"gitea.com/go-chi/binding"
+	"github.com/goccy/go-json"
+)
+
+// FormValues encodes the struct obj, or a pointer to it, into the form
+// values it would be bound from, which makes it the inverse of
+// binding.Form. Nested structs are flattened like binding does, nil
+// pointers, empty slices and absent Optionals are left out, and file
+// fields are skipped. It panics if obj is not a struct.
+func FormValues(obj interface{}) url.Values {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		panic("bindingtest: FormValues requires a struct")
+	}
+	values := make(url.Values)
+	encodeStruct(values, v)
+	return values
+}
+
+// FormRequest returns a form request holding the values of the struct
+// obj; see FormValues and NewFormRequest.
+func FormRequest(method, target string, obj interface{}) *http.Request {
+	return NewFormRequest(method, target, FormValues(obj))
+}
+
+// JSONRequest returns a request with obj marshaled as its JSON body; see
+// NewJSONRequest.
+func JSONRequest(method, target string, obj interface{}) *http.Request {
+	return NewJSONRequest(method, target, obj)
+}
+
+// MultipartRequest returns a multipart request holding the values of the
+// struct obj, see FormValues, and files keyed by field name.
+func MultipartRequest(method, target string, obj interface{}, files map[string][]File) *http.Request {
+	return NewMultipartRequest(method, target, FormValues(obj), files)
+}
+
+var (
+	fileHeaderType    = reflect.TypeOf((*multipart.FileHeader)(nil))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+func encodeStruct(values url.Values, v reflect.Value) {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field, fv := typ.Field(i), v.Field(i)
+		if !fv.CanInterface() {
+			continue
+		}
+		tag := field.Tag.Get("form")
+		name := binding.FormName(field)
+
+		if strings.HasSuffix(tag, ",json") || strings.Contains(tag, ",json,") {
+			if name != "" && !(fv.Kind() == reflect.Ptr && fv.IsNil()) {
+				data, _ := json.Marshal(fv.Interface())
+				values.Add(name, string(data))
+			}
+			continue
+		}
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() || fv.Type() == fileHeaderType {
+				continue
+			}
+			if fv.Elem().Kind() == reflect.Struct && field.Anonymous {
+				encodeStruct(values, fv.Elem())
+				continue
+			}
+		}
+		if isOptional(fv.Type()) {
+			if name != "" && fv.FieldByName("Present").Bool() {
+				encodeValue(values, name, fv.FieldByName("Value"))
+			}
+			continue
+		}
+		if fv.Kind() == reflect.Struct && fv.Type() != timeType && !fv.Type().Implements(textMarshalerType) &&
+			!reflect.PtrTo(fv.Type()).Implements(textMarshalerType) {
+			encodeStruct(values, fv)
+			continue
+		}
+		if name != "" {
+			encodeValue(values, name, fv)
+		}
+	}
+}
+
+func encodeValue(values url.Values, name string, v reflect.Value) {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			encodeValue(values, name, v.Index(i))
+		}
+		return
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() || v.Type() == fileHeaderType {
+			return
+		}
+		v = v.Elem()
+	}
+	values.Add(name, formatValue(v))
+}
+
+func formatValue(v reflect.Value) string {
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, _ := m.MarshalText()
+		return string(text)
+	}
+	if v.CanAddr() {
+		if m, ok := v.Addr().Interface().(encoding.TextMarshaler); ok {
+			text, _ := m.MarshalText()
+			return string(text)
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// isOptional reports whether typ is an instance of binding.Optional.
+func isOptional(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ.PkgPath() == "gitea.com/go-chi/binding" &&
+		strings.HasPrefix(typ.Name(), "Optional[")
+}
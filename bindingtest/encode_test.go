@@ -0,0 +1,97 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package bindingtest
+
+import (
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"gitea.com/go-chi/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	author struct {
+		Name string `form:"name"`
+	}
+
+	meta struct {
+		Source string `json:"source"`
+	}
+
+	article struct {
+		author
+		Title     string     `form:"title"`
+		Views     int        `form:"views"`
+		Published *time.Time `form:"published"`
+		Draft     *bool      `form:"draft"`
+		Ratings   []int      `form:"rating"`
+		Meta      meta       `form:"meta,json"`
+		Secret    string     `form:"-"`
+		Editor    struct {
+			Email string
+		}
+		Cover *multipart.FileHeader `form:"cover"`
+	}
+)
+
+func Test_FormValues(t *testing.T) {
+	published := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	a := article{
+		Title:     "Hello",
+		Views:     3,
+		Published: &published,
+		Ratings:   []int{4, 5},
+		Meta:      meta{Source: "api"},
+		Secret:    "s",
+	}
+	a.Editor.Email = "ed@example.com"
+	assert.EqualValues(t, url.Values{
+		"title":     {"Hello"},
+		"views":     {"3"},
+		"published": {"2020-05-01T12:00:00Z"},
+		"rating":    {"4", "5"},
+		"meta":      {`{"source":"api"}`},
+		"email":     {"ed@example.com"},
+	}, FormValues(&a))
+	assert.Panics(t, func() { FormValues("x") })
+}
+
+func Test_RequestBuilders(t *testing.T) {
+	type post struct {
+		Title   string                `form:"title" json:"title" binding:"Required"`
+		Tags    []string              `form:"tag" json:"tags"`
+		Count   int                   `form:"count" json:"count"`
+		Enabled *bool                 `form:"enabled" json:"enabled"`
+		Upload  *multipart.FileHeader `form:"upload" json:"-"`
+	}
+	enabled := false
+	want := post{Title: "Round trip", Tags: []string{"a", "b"}, Count: 7, Enabled: &enabled}
+
+	for _, req := range []*http.Request{
+		FormRequest("POST", "/", want),
+		FormRequest("GET", "/", want),
+		JSONRequest("PUT", "/", want),
+		MultipartRequest("POST", "/", want, map[string][]File{"upload": {{Name: "a.txt", Content: []byte("a")}}}),
+	} {
+		var got post
+		AssertNoErrors(t, binding.Bind(req, &got))
+		got.Upload = nil
+		assert.EqualValues(t, want, got)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// LoadCatalogs registers the message catalogs in the files of fsys
+// matching pattern, typically an embed.FS shipped with the application:
+//
+//	//go:embed locales
+//	var locales embed.FS
+//
+//	err := binding.LoadCatalogs(locales, "locales/*")
+//
+// Each file holds the catalog of the locale it is named after, e.g.
+// "de.json" or "pt-BR.toml", in the format of its extension. A JSON
+// catalog maps classifications to messages, which are either a string or
+// an object of plural forms. TOML catalogs are restricted to the same
+// shape, top-level keys and tables of string values:
+//
+//	RequiredError = "Pflichtfeld"
+//
+//	[MinSizeError]
+//	one = "Mindestens 1 Eintrag"
+//	other = "Mindestens {{.Count}} Einträge"
+func LoadCatalogs(fsys fs.FS, pattern string) error {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		ext := path.Ext(name)
+		if ext != ".json" && ext != ".toml" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		var catalog Catalog
+		if ext == ".json" {
+			err = json.Unmarshal(data, &catalog)
+		} else {
+			catalog, err = parseCatalogTOML(data)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		AddCatalog(strings.TrimSuffix(path.Base(name), ext), catalog)
+	}
+	return nil
+}
+
+// parseCatalogTOML parses a catalog written in the subset of TOML
+// described in LoadCatalogs.
+func parseCatalogTOML(data []byte) (Catalog, error) {
+	catalog := make(Catalog)
+	var table Message
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line[0] == '#':
+			continue
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: invalid table header", n)
+			}
+			table = make(Message)
+			catalog[strings.TrimSpace(line[1:len(line)-1])] = table
+			continue
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		key := strings.TrimSpace(line[:eq])
+		value, err := parseTOMLString(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		if table == nil {
+			catalog[key] = Message{"other": value}
+		} else {
+			table[key] = value
+		}
+	}
+	return catalog, scanner.Err()
+}
+
+// parseTOMLString parses a basic or literal TOML string, optionally
+// followed by a comment.
+func parseTOMLString(s string) (string, error) {
+	if len(s) > 0 && s[0] == '\'' {
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : end+1], checkTOMLRest(s[end+2:])
+	}
+	if len(s) == 0 || s[0] != '"' {
+		return "", fmt.Errorf("expected a string")
+	}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", err
+			}
+			return value, checkTOMLRest(s[i+1:])
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+func checkTOMLRest(s string) error {
+	s = strings.TrimSpace(s)
+	if s != "" && s[0] != '#' {
+		return fmt.Errorf("unexpected %q after string", s)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"testing"
+	"testing/fstest"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LoadCatalogs(t *testing.T) {
+	defer func() { catalogs = map[string]map[string]map[string]*template.Template{} }()
+
+	fsys := fstest.MapFS{
+		"locales/de.toml": {Data: []byte(`# Deutsch
+RequiredError = "{{.Field}} ist ein Pflichtfeld"
+
+[MinSizeError]
+one = 'Mindestens 1 Eintrag'
+other = "Mindestens {{.Count}} Einträge" # Plural
+`)},
+		"locales/nl.json":   {Data: []byte(`{"RequiredError": "{{.Field}} is verplicht", "MinSizeError": {"one": "Minstens 1 item", "other": "Minstens {{.Count}} items"}}`)},
+		"locales/README.md": {Data: []byte(`not a catalog`)},
+	}
+	assert.NoError(t, LoadCatalogs(fsys, "locales/*"))
+
+	type listForm struct {
+		Name string   `binding:"Required"`
+		Tags []string `binding:"MinSize(2)"`
+	}
+	validate := func(locale string) Errors {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req = req.WithContext(WithValidationConfig(req.Context(), &ValidationConfig{Locale: locale}))
+		return Validate(req, &listForm{Tags: []string{"a"}})
+	}
+
+	errs := validate("de")
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, "Name ist ein Pflichtfeld", errs[0].Message)
+	assert.EqualValues(t, "Mindestens 2 Einträge", errs[1].Message)
+
+	errs = validate("nl-BE")
+	assert.EqualValues(t, "Name is verplicht", errs[0].Message)
+	assert.EqualValues(t, "Minstens 2 items", errs[1].Message)
+
+	assert.Error(t, LoadCatalogs(fstest.MapFS{"fr.toml": {Data: []byte(`RequiredError = Obligatoire`)}}, "*"))
+	assert.Error(t, LoadCatalogs(fstest.MapFS{"fr.json": {Data: []byte(`{"RequiredError": 1}`)}}, "*"))
+}
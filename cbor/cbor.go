@@ -0,0 +1,45 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package cbor adds CBOR (RFC 8949) bodies to binding.Bind. Import it for
+// its side effects:
+//
+//	import _ "gitea.com/go-chi/binding/cbor"
+//
+// Bodies with Content-Type application/cbor are then decoded with
+// github.com/fxamacker/cbor, with fields named by their cbor tags, or else
+// by their json tags, and validated like JSON bodies.
+package cbor
+
+import (
+	"io"
+	"net/http"
+
+	"gitea.com/go-chi/binding"
+	"github.com/fxamacker/cbor/v2"
+)
+
+func init() {
+	binding.AddBodyDecoder(decode, "application/cbor")
+}
+
+func decode(body io.Reader, obj interface{}) error {
+	return cbor.NewDecoder(body).Decode(obj)
+}
+
+// Bind binds the CBOR body of req into obj and validates it. Unlike
+// binding.Bind it does not look at the Content-Type.
+func Bind(req *http.Request, obj interface{}) binding.Errors {
+	return binding.DecodeWith(req, obj, decode)
+}
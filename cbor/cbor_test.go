@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cbor
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitea.com/go-chi/binding"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type reading struct {
+	Device string  `json:"device" binding:"Required"`
+	Value  float64 `cbor:"v" binding:"Range(-40,125)"`
+}
+
+func request(t *testing.T, contentType string, v interface{}) *http.Request {
+	body, err := cbor.Marshal(v)
+	assert.NoError(t, err)
+	req, _ := http.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return req
+}
+
+func Test_Bind(t *testing.T) {
+	var r reading
+	errs := binding.Bind(request(t, "application/cbor", map[string]interface{}{"device": "th-1", "v": 21.5}), &r)
+	assert.Empty(t, errs)
+	assert.Equal(t, reading{Device: "th-1", Value: 21.5}, r)
+
+	r = reading{}
+	errs = binding.Bind(request(t, "application/cbor", map[string]interface{}{"v": 200}), &r)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, binding.ERR_REQUIRED, errs[0].Classification)
+		assert.Equal(t, []string{"Device"}, errs[0].FieldNames)
+		assert.Equal(t, binding.ERR_RANGE, errs[1].Classification)
+		assert.Equal(t, []string{"Value"}, errs[1].FieldNames)
+	}
+
+	req, _ := http.NewRequest("POST", "/", strings.NewReader("\xa1"))
+	req.Header.Set("Content-Type", "application/cbor")
+	errs = binding.Bind(req, &r)
+	if assert.NotEmpty(t, errs) {
+		assert.Equal(t, binding.ERR_DESERIALIZATION, errs[0].Classification)
+	}
+}
+
+func Test_BindExplicit(t *testing.T) {
+	var r reading
+	errs := Bind(request(t, "application/octet-stream", map[string]interface{}{"device": "th-1"}), &r)
+	assert.Empty(t, errs)
+	assert.Equal(t, "th-1", r.Device)
+}
@@ -0,0 +1,23 @@
+module gitea.com/go-chi/binding/cbor
+
+go 1.18
+
+require (
+	gitea.com/go-chi/binding v0.0.0-00010101000000-000000000000
+	github.com/fxamacker/cbor/v2 v2.5.0
+	github.com/stretchr/testify v1.8.4
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-chi/chi/v5 v5.0.4 // indirect
+	github.com/goccy/go-json v0.4.11 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/unknwon/com v0.0.0-20190804042917-757f69c95f3e // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The binding package is developed alongside this module.
+replace gitea.com/go-chi/binding => ../
@@ -0,0 +1,142 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package cel adds rules written as Google CEL expressions, for invariants
+// that do not fit the rules of binding tags. Import it for its side
+// effects:
+//
+//	import _ "gitea.com/go-chi/binding/cel"
+//
+//	type Booking struct {
+//		Start time.Time `json:"start"`
+//		End   time.Time `json:"end" cel:"self.end > self.start"`
+//		Items []Item    `json:"items" cel:"self.items.size() < 100"`
+//	}
+//
+// In expressions, self is the struct the tagged field belongs to, with
+// fields named as in their json tags. Expressions are compiled once per
+// struct type and must evaluate to a bool; failures are reported as
+// ERR_CEL for the tagged field.
+package cel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gitea.com/go-chi/binding"
+	"github.com/google/cel-go/cel"
+)
+
+const ERR_CEL = "CELError"
+
+func init() {
+	binding.AddExpressionTag("cel", ERR_CEL, compile)
+}
+
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+)
+
+func compile(typ reflect.Type, expr string) (binding.Expression, error) {
+	envOnce.Do(func() {
+		env, envErr = cel.NewEnv(cel.Variable("self", cel.DynType))
+	})
+	if envErr != nil {
+		return nil, envErr
+	}
+
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if t := ast.OutputType(); t != cel.BoolType && t != cel.DynType {
+		return nil, fmt.Errorf("expression must evaluate to bool, not %v", t)
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(obj interface{}) (bool, error) {
+		out, _, err := prg.Eval(map[string]interface{}{
+			"self": toCEL(reflect.ValueOf(obj)),
+		})
+		if err != nil {
+			return false, err
+		}
+		valid, ok := out.Value().(bool)
+		if !ok {
+			return false, fmt.Errorf("expression evaluated to %v instead of bool", out.Type())
+		}
+		return valid, nil
+	}, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// toCEL converts v into values understood by CEL, turning structs into
+// maps keyed by the json names of their fields.
+func toCEL(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return toCEL(v.Elem())
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v.Interface()
+		}
+		m := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			m[name] = toCEL(v.Field(i))
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes()
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = toCEL(v.Index(i))
+		}
+		return list
+	case reflect.Map:
+		m := make(map[interface{}]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[toCEL(iter.Key())] = toCEL(iter.Value())
+		}
+		return m
+	}
+	return v.Interface()
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cel
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gitea.com/go-chi/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type booking struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end" cel:"self.end > self.start"`
+	Items []item    `json:"items" cel:"self.items.size() <= 2 && self.items.all(i, i.quantity > 0)"`
+}
+
+func bindBooking(body string) binding.Errors {
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	var b booking
+	return binding.Bind(req, &b)
+}
+
+func Test_CEL(t *testing.T) {
+	errs := bindBooking(`{"start":"2021-01-01T10:00:00Z","end":"2021-01-02T10:00:00Z","items":[{"sku":"a","quantity":1}]}`)
+	assert.Empty(t, errs)
+
+	errs = bindBooking(`{"start":"2021-01-02T10:00:00Z","end":"2021-01-01T10:00:00Z","items":[{"sku":"a","quantity":0}]}`)
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, ERR_CEL, errs[0].Classification)
+	assert.EqualValues(t, []string{"End"}, errs[0].FieldNames)
+	assert.EqualValues(t, "self.end > self.start", errs[0].Message)
+	assert.EqualValues(t, []string{"Items"}, errs[1].FieldNames)
+
+	_, err := compile(nil, "self.end +")
+	assert.Error(t, err)
+	_, err = compile(nil, "1 + 2")
+	assert.Error(t, err)
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// CharsetDecoder converts s from a character encoding to UTF-8.
+type CharsetDecoder func(s string) (string, error)
+
+// charsets are the registered decoders by lower case charset name.
+var charsets = map[string]CharsetDecoder{
+	"iso-8859-1": decodeLatin1,
+	"iso_8859-1": decodeLatin1,
+	"latin1":     decodeLatin1,
+	"l1":         decodeLatin1,
+}
+
+// RegisterCharset registers decode for form bodies whose Content-Type has
+// a charset parameter with one of the given names, e.g.
+// "application/x-www-form-urlencoded; charset=Shift_JIS". The keys and
+// values of such forms, and the names of uploaded files, are converted to
+// UTF-8 before they are bound. Names are case-insensitive.
+//
+// UTF-8 and US-ASCII need no decoder, and ISO-8859-1 is built in. Import
+// gitea.com/go-chi/binding/charset for the encodings of the WHATWG
+// Encoding Standard that browsers submit legacy forms in.
+func RegisterCharset(decode CharsetDecoder, names ...string) {
+	for _, name := range names {
+		charsets[strings.ToLower(strings.TrimSpace(name))] = decode
+	}
+}
+
+func decodeLatin1(s string) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		sb.WriteRune(rune(s[i]))
+	}
+	return sb.String(), nil
+}
+
+// charsetDecoder returns the decoder for the charset of the body of req,
+// or nil if the body is UTF-8 or has no charset.
+func charsetDecoder(req *http.Request) (CharsetDecoder, Errors) {
+	var errors Errors
+	_, params := MediaType(req)
+	name := strings.ToLower(strings.TrimSpace(params["charset"]))
+	switch name {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return nil, errors
+	}
+	decode, ok := charsets[name]
+	if !ok {
+		errors.Add([]string{}, ERR_CONTENT_TYPE, "Unsupported charset")
+	}
+	return decode, errors
+}
+
+// markUTF8 sets the charset of the Content-Type of req to UTF-8 once its
+// form has been converted, so it is not converted again.
+func markUTF8(req *http.Request) {
+	mediaType, params := MediaType(req)
+	params["charset"] = "utf-8"
+	req.Header.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+}
+
+// transcodeValues returns the keys and values of form converted to UTF-8.
+func transcodeValues(form url.Values, decode CharsetDecoder) (url.Values, error) {
+	decoded := make(url.Values, len(form))
+	for key, vals := range form {
+		name, err := transcode(key, decode)
+		if err != nil {
+			return nil, err
+		}
+		for _, val := range vals {
+			s, err := transcode(val, decode)
+			if err != nil {
+				return nil, err
+			}
+			decoded[name] = append(decoded[name], s)
+		}
+	}
+	return decoded, nil
+}
+
+// transcode converts s to UTF-8 with decode. ASCII is left alone, as all
+// the charsets forms are submitted in encode it the same way.
+func transcode(s string, decode CharsetDecoder) (string, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return decode(s)
+		}
+	}
+	return s, nil
+}
+
+// transcodeForm converts the urlencoded body values of the parsed form of
+// req to UTF-8. The query string is left alone, as the charset parameter
+// only describes the body.
+func transcodeForm(req *http.Request) Errors {
+	decode, errors := charsetDecoder(req)
+	if decode == nil || len(req.PostForm) == 0 {
+		return errors
+	}
+	post, err := transcodeValues(req.PostForm, decode)
+	if err != nil {
+		errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+		return errors
+	}
+	// ParseForm puts the body values of a key before its query values.
+	for key, vals := range req.PostForm {
+		if query := req.Form[key]; len(query) > len(vals) {
+			req.Form[key] = query[len(vals):]
+		} else {
+			delete(req.Form, key)
+		}
+	}
+	for key, vals := range post {
+		req.Form[key] = append(vals[:len(vals):len(vals)], req.Form[key]...)
+	}
+	req.PostForm = post
+	markUTF8(req)
+	return errors
+}
+
+// transcodeMultipart converts the values and file names of form to UTF-8.
+func transcodeMultipart(form *multipart.Form, decode CharsetDecoder) error {
+	values, err := transcodeValues(form.Value, decode)
+	if err != nil {
+		return err
+	}
+	form.Value = values
+	for _, files := range form.File {
+		for _, fh := range files {
+			name, err := transcode(fh.Filename, decode)
+			if err != nil {
+				return err
+			}
+			fh.Filename = name
+		}
+	}
+	return nil
+}
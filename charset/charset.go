@@ -0,0 +1,74 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package charset adds the legacy character encodings of the WHATWG
+// Encoding Standard to form binding. Import it for its side effects:
+//
+//	import _ "gitea.com/go-chi/binding/charset"
+//
+// Urlencoded and multipart forms with a charset parameter such as
+// Shift_JIS, EUC-KR, GBK, Big5 or windows-1251 are then converted to UTF-8
+// with golang.org/x/text before they are bound. As in browsers,
+// ISO-8859-1 is decoded as windows-1252.
+package charset
+
+import (
+	"gitea.com/go-chi/binding"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// labels are the charset names registered, by the encoding they name.
+var labels = [][]string{
+	{"shift_jis", "sjis", "ms_kanji", "csshiftjis", "windows-31j", "x-sjis"},
+	{"euc-jp", "cseucpkdfmtjapanese", "x-euc-jp"},
+	{"iso-2022-jp", "csiso2022jp"},
+	{"euc-kr", "cseuckr", "ks_c_5601-1987", "windows-949"},
+	{"gbk", "gb2312", "chinese", "csgb2312", "x-gbk"},
+	{"gb18030"},
+	{"big5", "big5-hkscs", "cn-big5", "csbig5", "x-x-big5"},
+	{"iso-8859-1", "iso_8859-1", "latin1", "l1", "windows-1252", "cp1252"},
+	{"iso-8859-2", "latin2", "l2"},
+	{"iso-8859-5", "cyrillic"},
+	{"iso-8859-7", "greek"},
+	{"iso-8859-8", "hebrew"},
+	{"iso-8859-15", "iso8859-15", "l9"},
+	{"koi8-r", "koi8"},
+	{"koi8-u"},
+	{"windows-1250", "cp1250"},
+	{"windows-1251", "cp1251"},
+	{"windows-1253", "cp1253"},
+	{"windows-1254", "cp1254"},
+	{"windows-1255", "cp1255"},
+	{"windows-1256", "cp1256"},
+	{"windows-1257", "cp1257"},
+	{"windows-1258", "cp1258"},
+	{"windows-874", "tis-620"},
+}
+
+func init() {
+	for _, names := range labels {
+		enc, err := htmlindex.Get(names[0])
+		if err != nil {
+			panic(err)
+		}
+		binding.RegisterCharset(decoder(enc), names...)
+	}
+}
+
+func decoder(enc encoding.Encoding) binding.CharsetDecoder {
+	return func(s string) (string, error) {
+		return enc.NewDecoder().String(s)
+	}
+}
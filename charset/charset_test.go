@@ -0,0 +1,75 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package charset
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitea.com/go-chi/binding"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+type post struct {
+	Title string `form:"title" binding:"Required"`
+	Body  string `form:"body"`
+}
+
+func Test_Labels(t *testing.T) {
+	for _, names := range labels {
+		for _, name := range names {
+			enc, err := htmlindex.Get(name)
+			if assert.NoError(t, err, name) {
+				expected, _ := htmlindex.Get(names[0])
+				assert.Equal(t, expected, enc, name)
+			}
+		}
+	}
+}
+
+func Test_Form(t *testing.T) {
+	// "日本語" and "テスト" in Shift_JIS, and "€" in windows-1252.
+	for contentType, body := range map[string]string{
+		"application/x-www-form-urlencoded; charset=Shift_JIS":  "title=%93%FA%96%7B%8C%EA&body=%83e%83X%83g",
+		"application/x-www-form-urlencoded; charset=ISO-8859-1": "title=%93%FA%96%7B%8C%EA&body=%80",
+	} {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		var actual post
+		assert.Empty(t, binding.Bind(req, &actual), contentType)
+		if strings.HasSuffix(contentType, "Shift_JIS") {
+			assert.Equal(t, post{Title: "日本語", Body: "テスト"}, actual)
+		} else {
+			assert.Equal(t, "€", actual.Body)
+		}
+	}
+}
+
+func Test_MultipartForm(t *testing.T) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	w.WriteField("title", "\xc6\xfc\xcb\xdc\xb8\xec")
+	w.Close()
+	req, _ := http.NewRequest("POST", "/", body)
+	req.Header.Set("Content-Type", w.FormDataContentType()+"; charset=EUC-JP")
+
+	var actual post
+	assert.Empty(t, binding.Bind(req, &actual))
+	assert.Equal(t, "日本語", actual.Title)
+}
@@ -0,0 +1,22 @@
+module gitea.com/go-chi/binding/charset
+
+go 1.18
+
+require (
+	gitea.com/go-chi/binding v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.8.4
+	golang.org/x/text v0.13.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-chi/chi/v5 v5.0.4 // indirect
+	github.com/goccy/go-json v0.4.11 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/unknwon/com v0.0.0-20190804042917-757f69c95f3e // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The binding package is developed alongside this module.
+replace gitea.com/go-chi/binding => ../
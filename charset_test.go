@@ -0,0 +1,89 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FormCharset(t *testing.T) {
+	// "Café" and "Crème brûlée" in ISO-8859-1.
+	body := "title=Caf%E9+au+lait&content=Cr%E8me+br%FBl%E9e"
+	req, _ := http.NewRequest("POST", "/?content=%E9", strings.NewReader(body))
+	req.Header.Set("Content-Type", formContentType+"; charset=ISO-8859-1")
+	var post Post
+	errs := Bind(req, &post)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, Post{Title: "Café au lait", Content: "Crème brûlée"}, post)
+	assert.EqualValues(t, []string{"Crème brûlée", "\xe9"}, req.Form["content"])
+	assert.EqualValues(t, formContentType+"; charset=utf-8", req.Header.Get("Content-Type"))
+
+	// Binding again does not convert the form twice.
+	post = Post{}
+	assert.Empty(t, Form(req, &post))
+	assert.EqualValues(t, "Café au lait", post.Title)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader("title=Hello%2C+world"))
+	req.Header.Set("Content-Type", formContentType+"; charset=EBCDIC")
+	errs = Bind(req, &post)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_CONTENT_TYPE, errs[0].Classification)
+		assert.EqualValues(t, "Unsupported charset", errs[0].Message)
+	}
+}
+
+func Test_MultipartCharset(t *testing.T) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	w.WriteField("title", "Caf\xe9 au lait")
+	w.WriteField("content", "Cr\xe8me br\xfbl\xe9e")
+	part, _ := w.CreateFormFile("picture", "cr\xe8me.png")
+	part.Write([]byte("png"))
+	w.Close()
+	req, _ := http.NewRequest("POST", "/", body)
+	req.Header.Set("Content-Type", w.FormDataContentType()+"; charset=latin1")
+
+	var post struct {
+		Post
+		Pictures []*multipart.FileHeader `form:"picture"`
+	}
+	errs := Bind(req, &post)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "Café au lait", post.Title)
+	assert.EqualValues(t, "Crème brûlée", post.Content)
+	if assert.Len(t, post.Pictures, 1) {
+		assert.EqualValues(t, "crème.png", post.Pictures[0].Filename)
+	}
+}
+
+func Test_RegisterCharset(t *testing.T) {
+	defer func(saved map[string]CharsetDecoder) { charsets = saved }(charsets)
+	charsets = map[string]CharsetDecoder{}
+	RegisterCharset(func(s string) (string, error) {
+		return strings.Replace(s, "\xff", "ß", -1), nil
+	}, "X-Test")
+
+	req, _ := http.NewRequest("POST", "/", strings.NewReader("title=Gr%FF%FFe+an+alle"))
+	req.Header.Set("Content-Type", formContentType+"; charset=x-test")
+	var post Post
+	assert.Empty(t, Bind(req, &post))
+	assert.EqualValues(t, "Grßße an alle", post.Title)
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+type (
+	// BodyDecoder decodes a request body into obj, which is a pointer to
+	// the bound struct.
+	BodyDecoder func(body io.Reader, obj interface{}) error
+
+	// BinderFunc decodes req into obj, which is a pointer to the bound
+	// struct, and returns the errors of decoding it. Validation is left
+	// to the caller.
+	BinderFunc func(req *http.Request, obj interface{}) Errors
+)
+
+// binders are the registered binders by media type pattern.
+var binders = map[string]BinderFunc{}
+
+// RegisterBinder registers fn for request bodies whose Content-Type
+// matches contentType, so Bind can dispatch formats the package does not
+// decode itself, e.g. CSV or vendor media types. Structs decoded by fn go
+// through the hooks and validation of Bind like any other body.
+//
+// Registered binders are consulted before the built-in ones. The pattern
+// may be a media type such as "text/csv", a structured syntax suffix such
+// as "+json", a wildcard subtype such as "text/*", or "*/*"; when several
+// patterns match, the most specific one in that order is used. Media types
+// without wildcards are also listed in the Accept-Post and Accept-Patch
+// headers of unsupported Content-Type responses.
+func RegisterBinder(contentType string, fn BinderFunc) {
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	if _, ok := binders[contentType]; !ok && !strings.HasPrefix(contentType, "+") && !strings.Contains(contentType, "*") {
+		mediaTypes = append(mediaTypes, contentType)
+	}
+	binders[contentType] = fn
+}
+
+// AddBodyDecoder registers decode for request bodies of the given media
+// types with RegisterBinder. Decode errors are reported as
+// ERR_DESERIALIZATION.
+func AddBodyDecoder(decode BodyDecoder, types ...string) {
+	for _, typ := range types {
+		RegisterBinder(typ, func(req *http.Request, obj interface{}) Errors {
+			return decodeBody(req, obj, decode)
+		})
+	}
+}
+
+// registeredBinder returns the registered binder for contentType, if any.
+func registeredBinder(contentType string) decoder {
+	if len(binders) == 0 || contentType == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	slash := strings.IndexByte(mediaType, '/')
+	patterns := []string{mediaType}
+	if i := strings.LastIndexByte(mediaType, '+'); i > slash {
+		patterns = append(patterns, mediaType[i:])
+	}
+	if slash >= 0 {
+		patterns = append(patterns, mediaType[:slash]+"/*")
+	}
+	for _, pattern := range append(patterns, "*/*") {
+		if fn, ok := binders[pattern]; ok {
+			return decoder(fn)
+		}
+	}
+	return nil
+}
+
+// DecodeWith binds the body of req into obj with decode, and validates it.
+// Like JSON, it does not look at the Content-Type.
+func DecodeWith(req *http.Request, obj interface{}, decode BodyDecoder) Errors {
+	return bindWith(req, obj, func(req *http.Request, obj interface{}) Errors {
+		return decodeBody(req, obj, decode)
+	}, false)
+}
+
+func decodeBody(req *http.Request, obj interface{}, decode BodyDecoder) Errors {
+	var errors Errors
+	if req.Body == nil {
+		return errors
+	}
+	defer req.Body.Close()
+
+	if err := decode(req.Body, obj); err != nil && err != io.EOF {
+		errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+		binderFrom(req).trace(TraceEvent{Stage: TRACE_REJECT, Source: SOURCE_BODY, Errors: errors})
+	}
+	return errors
+}
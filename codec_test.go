@@ -0,0 +1,106 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeLine decodes the first line of body as the title of a Post.
+func decodeLine(body io.Reader, obj interface{}) error {
+	line, err := bufio.NewReader(body).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if line == "" {
+		return errors.New("empty body")
+	}
+	obj.(*Post).Title = strings.TrimSpace(line)
+	return nil
+}
+
+func Test_DecodeWith(t *testing.T) {
+	var post Post
+	req, _ := http.NewRequest("POST", "/", strings.NewReader("Hello, world\n"))
+	assert.Empty(t, DecodeWith(req, &post, decodeLine))
+	assert.EqualValues(t, "Hello, world", post.Title)
+
+	post = Post{}
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(""))
+	errs := DecodeWith(req, &post, decodeLine)
+	if assert.NotEmpty(t, errs) {
+		assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+		assert.EqualValues(t, "empty body", errs[0].Message)
+	}
+}
+
+func Test_RegisterBinder(t *testing.T) {
+	defer func(b map[string]BinderFunc, types []string) { binders, mediaTypes = b, types }(binders, mediaTypes)
+	binders = map[string]BinderFunc{}
+
+	bindAs := func(format string) BinderFunc {
+		return func(req *http.Request, obj interface{}) Errors {
+			obj.(*Post).Title = "Hello, " + format
+			return nil
+		}
+	}
+	RegisterBinder("text/csv", func(req *http.Request, obj interface{}) Errors {
+		return decodeBody(req, obj, decodeLine)
+	})
+	RegisterBinder("+json", bindAs("json suffix"))
+	RegisterBinder("application/vnd.api+json", bindAs("json api"))
+	RegisterBinder("text/*", bindAs("text"))
+
+	for contentType, title := range map[string]string{
+		"text/csv; charset=utf-8":           "Hello, world",
+		"application/vnd.api+json":          "Hello, json api",
+		"application/vnd.github.v3+json":    "Hello, json suffix",
+		"text/x-markdown":                   "Hello, text",
+		"application/json":                  "",
+		"application/x-www-form-urlencoded": "",
+	} {
+		var post Post
+		req, _ := http.NewRequest("POST", "/", strings.NewReader("Hello, world\n"))
+		req.Header.Set("Content-Type", contentType)
+		errs := Bind(req, &post)
+		if title == "" {
+			// Built-in binders are used for types nothing was registered for.
+			assert.NotEmpty(t, errs, contentType)
+			continue
+		}
+		assert.Empty(t, errs, contentType)
+		assert.EqualValues(t, title, post.Title, contentType)
+	}
+
+	// Registered binders are validated like any other body.
+	var post Post
+	req, _ := http.NewRequest("POST", "/", strings.NewReader("Short\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	errs := Bind(req, &post)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, "LengthError", errs[0].Classification)
+	}
+
+	assert.Contains(t, mediaTypes, "text/csv")
+	assert.Contains(t, mediaTypes, "application/vnd.api+json")
+	assert.NotContains(t, mediaTypes, "text/*")
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package compat eases migrating from macaron-binding by providing its
+// handler style on top of binding for net/http routers such as chi. The
+// routes of a project can be switched first:
+//
+//	m.Post("/user/login", binding.Bind(auth.SignInForm{}), user.SignInPost)
+//
+// becomes
+//
+//	r.With(compat.Bind(auth.SignInForm{})).Post("/user/login", user.SignInPost)
+//
+// with handlers retrieving the form with Get instead of having it
+// injected, and the errors with binding.ErrorsFrom.
+package compat
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"gitea.com/go-chi/binding"
+)
+
+// ErrorHandler can be implemented by the structs bound by Bind to write
+// the error response themselves, replacing binding.RespondErrors.
+type ErrorHandler interface {
+	Error(rw http.ResponseWriter, req *http.Request, errs binding.Errors)
+}
+
+type boundKey struct{}
+
+// Bind returns middleware binding each request into a new value of the
+// type of obj with binding.Bind. Like in macaron-binding, the error
+// response is written, and the next handler skipped, if binding fails.
+func Bind(obj interface{}) func(http.Handler) http.Handler {
+	return middleware(obj, binding.Bind, true)
+}
+
+// Form returns middleware binding each request into a new value of the
+// type of obj with binding.Form. The next handler is called even if
+// binding fails, and gets the errors with binding.ErrorsFrom.
+func Form(obj interface{}) func(http.Handler) http.Handler {
+	return middleware(obj, binding.Form, false)
+}
+
+// MultipartForm is like Form, using binding.MultipartForm.
+func MultipartForm(obj interface{}) func(http.Handler) http.Handler {
+	return middleware(obj, binding.MultipartForm, false)
+}
+
+// Json is like Form, using binding.JSON. It keeps the name used by
+// macaron-binding.
+func Json(obj interface{}) func(http.Handler) http.Handler {
+	return middleware(obj, binding.JSON, false)
+}
+
+func middleware(obj interface{}, bind func(*http.Request, interface{}) binding.Errors, bail bool) func(http.Handler) http.Handler {
+	typ := reflect.TypeOf(obj)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return func(next http.Handler) http.Handler {
+		// binding.Handler keeps the errors for binding.ErrorsFrom.
+		return binding.Handler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			v := reflect.New(typ)
+			errs := bind(req, v.Interface())
+			if bail && len(errs) > 0 {
+				if handler, ok := v.Interface().(ErrorHandler); ok {
+					handler.Error(rw, req, errs)
+				} else {
+					binding.RespondErrors(rw, req, errs)
+				}
+				return
+			}
+
+			bound := map[reflect.Type]reflect.Value{typ: v.Elem()}
+			if prev, ok := req.Context().Value(boundKey{}).(map[reflect.Type]reflect.Value); ok {
+				for t, v := range prev {
+					if t != typ {
+						bound[t] = v
+					}
+				}
+			}
+			next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), boundKey{}, bound)))
+		}))
+	}
+}
+
+// Get sets the value pointed to by ptr to the value of its type bound from
+// req by the middleware of this package, and reports whether there was
+// one. It replaces the injection of the bound struct into macaron
+// handlers:
+//
+//	func SignInPost(rw http.ResponseWriter, req *http.Request) {
+//		var form auth.SignInForm
+//		compat.Get(req, &form)
+//		...
+//	}
+func Get(req *http.Request, ptr interface{}) bool {
+	target := reflect.ValueOf(ptr).Elem()
+	bound, _ := req.Context().Value(boundKey{}).(map[reflect.Type]reflect.Value)
+	v, ok := bound[target.Type()]
+	if ok {
+		target.Set(v)
+	}
+	return ok
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package compat
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gitea.com/go-chi/binding"
+	chi "github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type signInForm struct {
+	UserName string `form:"user_name" binding:"Required"`
+	Password string `form:"password" binding:"Required"`
+}
+
+type customForm struct {
+	Name string `form:"name" binding:"Required"`
+}
+
+func (f *customForm) Error(rw http.ResponseWriter, req *http.Request, errs binding.Errors) {
+	http.Error(rw, "custom: "+errs[0].Message, http.StatusTeapot)
+}
+
+func post(h http.Handler, target string, values url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", target, strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+	return rw
+}
+
+func Test_Bind(t *testing.T) {
+	var (
+		form   signInForm
+		called bool
+	)
+	r := chi.NewRouter()
+	r.With(Bind(signInForm{})).Post("/login", func(rw http.ResponseWriter, req *http.Request) {
+		called = Get(req, &form)
+	})
+	r.With(Bind(&customForm{})).Post("/custom", func(rw http.ResponseWriter, req *http.Request) {})
+
+	rw := post(r, "/login", url.Values{"user_name": {"alice"}, "password": {"secret"}})
+	assert.True(t, called)
+	assert.EqualValues(t, signInForm{UserName: "alice", Password: "secret"}, form)
+	assert.EqualValues(t, http.StatusOK, rw.Code)
+
+	called = false
+	rw = post(r, "/login", url.Values{"user_name": {"alice"}})
+	assert.False(t, called)
+	assert.EqualValues(t, binding.STATUS_UNPROCESSABLE_ENTITY, rw.Code)
+	assert.Contains(t, rw.Body.String(), binding.ERR_REQUIRED)
+
+	rw = post(r, "/custom", url.Values{})
+	assert.EqualValues(t, http.StatusTeapot, rw.Code)
+	assert.EqualValues(t, "custom: Required\n", rw.Body.String())
+}
+
+func Test_Form(t *testing.T) {
+	var (
+		form   signInForm
+		custom customForm
+		errs   binding.Errors
+	)
+	r := chi.NewRouter()
+	r.With(Form(customForm{}), Form(signInForm{})).Post("/login", func(rw http.ResponseWriter, req *http.Request) {
+		Get(req, &form)
+		Get(req, &custom)
+		errs = binding.ErrorsFrom(req)
+	})
+
+	rw := post(r, "/login", url.Values{"user_name": {"alice"}, "name": {"n"}})
+	assert.EqualValues(t, http.StatusOK, rw.Code)
+	assert.EqualValues(t, "alice", form.UserName)
+	assert.EqualValues(t, "n", custom.Name)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, []string{"Password"}, errs[0].FieldNames)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	assert.False(t, Get(req, &form))
+}
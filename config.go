@@ -0,0 +1,218 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidationConfig holds validation settings for a single request, e.g.
+// the limits of a tenant's plan. Middleware earlier in the chain attaches
+// it to the request context with WithValidationConfig.
+type ValidationConfig struct {
+	// Limits overrides the parameters of rules, keyed by field name and
+	// rule name, e.g. {"Title.MaxSize": "200", "Tags.Range": "1,5"}.
+	Limits map[string]string
+	// Disabled lists the names of rules that are not applied, e.g. "Url".
+	Disabled map[string]bool
+	// Flags decides which rules are enabled for the request. If nil, the
+	// provider set with SetFlagProvider is used.
+	Flags FlagProvider
+	// Locale is the language in which messages for the request should be
+	// reported, using the catalog registered with AddCatalog.
+	Locale string
+}
+
+// locale returns the Locale of cfg, which may be nil.
+func (cfg *ValidationConfig) locale() string {
+	if cfg == nil {
+		return ""
+	}
+	return cfg.Locale
+}
+
+// FlagProvider enables or disables validation rules at runtime, e.g. to
+// roll out a stricter rule gradually. The rule is the name without
+// parameters, e.g. "MaxSize", and field is the name of the struct field.
+type FlagProvider interface {
+	RuleEnabled(field, rule string) bool
+}
+
+// FlagProviderFunc is an adapter to use a function as a FlagProvider.
+type FlagProviderFunc func(field, rule string) bool
+
+// RuleEnabled calls f(field, rule).
+func (f FlagProviderFunc) RuleEnabled(field, rule string) bool {
+	return f(field, rule)
+}
+
+var flagProvider FlagProvider
+
+// SetFlagProvider sets the provider deciding which rules are enabled.
+// Pass nil to enable all rules.
+func SetFlagProvider(p FlagProvider) {
+	flagProvider = p
+}
+
+type validationConfigKey struct{}
+
+// Check reports whether the Limits of cfg are valid parameters of their
+// rules, e.g. not {"Title.MaxSize": "ten"}. Configurations loaded at
+// runtime, such as those of tenants, should be checked when they are
+// loaded; invalid limits are otherwise reported as ERR_CONFIG errors by
+// each request validated with cfg.
+func (cfg *ValidationConfig) Check() error {
+	keys := make([]string, 0, len(cfg.Limits))
+	for key := range cfg.Limits {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		i := strings.IndexByte(key, '.')
+		if i < 0 {
+			return fmt.Errorf("limit %s: not of the form Field.Rule", key)
+		}
+		if err := checkRules(key[i+1:] + "(" + cfg.Limits[key] + ")"); err != nil {
+			return fmt.Errorf("limit %s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// WithValidationConfig returns a copy of ctx carrying cfg; see Check.
+func WithValidationConfig(ctx context.Context, cfg *ValidationConfig) context.Context {
+	return context.WithValue(ctx, validationConfigKey{}, cfg)
+}
+
+// ValidationConfigFrom returns the validation settings carried by ctx,
+// or nil if there are none.
+func ValidationConfigFrom(ctx context.Context) *ValidationConfig {
+	cfg, _ := ctx.Value(validationConfigKey{}).(*ValidationConfig)
+	return cfg
+}
+
+var ruleSets = map[string][]string{}
+
+// DefineRuleSet defines a named bundle of rules, which binding tags refer
+// to as "@name", e.g.
+//
+//	binding.DefineRuleSet("username", "Required;AlphaDash;MinSize(3);MaxSize(32)")
+//
+//	Name string `binding:"@username"`
+//
+// Rule sets may refer to rule sets defined before them.
+func DefineRuleSet(name, rules string) {
+	expanded, err := expandRuleSets(splitRules(rules))
+	if err != nil {
+		panic("binding: " + err.Error())
+	}
+	ruleSets[name] = expanded
+}
+
+// expandRuleSets replaces references to rule sets with their rules.
+func expandRuleSets(rules []string) ([]string, error) {
+	expanded := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if !strings.HasPrefix(rule, "@") {
+			expanded = append(expanded, rule)
+			continue
+		}
+		set, ok := ruleSets[rule[1:]]
+		if !ok {
+			return nil, fmt.Errorf("unknown rule set %s", rule)
+		}
+		expanded = append(expanded, set...)
+	}
+	return expanded, nil
+}
+
+// splitRules splits a binding tag into its rules. Rules are separated by
+// semicolons; a semicolon in a parameter, such as the pattern of Match, is
+// escaped with a backslash.
+func splitRules(tag string) []string {
+	if !strings.Contains(tag, `\;`) {
+		return strings.Split(tag, ";")
+	}
+	var rules []string
+	var rule strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ';':
+			rule.WriteByte(';')
+			i++
+		case tag[i] == ';':
+			rules = append(rules, rule.String())
+			rule.Reset()
+		default:
+			rule.WriteByte(tag[i])
+		}
+	}
+	return append(rules, rule.String())
+}
+
+// ruleName returns the name of a rule without its parameters.
+func ruleName(rule string) string {
+	if i := strings.IndexByte(rule, '('); i >= 0 {
+		return rule[:i]
+	}
+	return rule
+}
+
+// rules returns the validation rules of field of struct typ with the
+// settings of cfg applied. A nil cfg leaves the rules unchanged. Invalid
+// limits of cfg are reported as errors, as they come with the request
+// rather than with the code.
+func (cfg *ValidationConfig) rules(typ reflect.Type, field reflect.StructField) ([]string, error) {
+	tag := tagRules(typ, field)
+	rules := splitRules(tag)
+	if strings.Contains(tag, "@") {
+		var err error
+		if rules, err = expandRuleSets(rules); err != nil {
+			return nil, err
+		}
+	}
+	flags := flagProvider
+	if cfg != nil && cfg.Flags != nil {
+		flags = cfg.Flags
+	}
+	if cfg == nil && flags == nil {
+		return rules, nil
+	}
+
+	applied := rules[:0:0]
+	for _, rule := range rules {
+		name := ruleName(rule)
+		if flags != nil && name != "" && !flags.RuleEnabled(field.Name, name) {
+			continue
+		}
+		if cfg != nil {
+			if cfg.Disabled[name] {
+				continue
+			}
+			if limit, ok := cfg.Limits[field.Name+"."+name]; ok {
+				rule = name + "(" + limit + ")"
+				if err := checkRules(rule); err != nil {
+					return nil, fmt.Errorf("limit %s.%s: %v", field.Name, name, err)
+				}
+			}
+		}
+		applied = append(applied, rule)
+	}
+	return applied, nil
+}
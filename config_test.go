@@ -0,0 +1,162 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type planForm struct {
+	Title   string `form:"title" binding:"Required;MaxSize(10)"`
+	Website string `form:"website" binding:"Url"`
+}
+
+func Test_ValidationConfig(t *testing.T) {
+	newRequest := func(cfg *ValidationConfig) *http.Request {
+		req, _ := http.NewRequest("GET", "/?title=A+rather+long+title&website=example", nil)
+		if cfg != nil {
+			req = req.WithContext(WithValidationConfig(req.Context(), cfg))
+		}
+		return req
+	}
+
+	var form planForm
+	errs := Form(newRequest(nil), &form)
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, ERR_MAX_SIZE, errs[0].Classification)
+	assert.EqualValues(t, ERR_URL, errs[1].Classification)
+
+	cfg := &ValidationConfig{
+		Limits:   map[string]string{"Title.MaxSize": "50"},
+		Disabled: map[string]bool{"Url": true},
+	}
+	form = planForm{}
+	errs = Form(newRequest(cfg), &form)
+	assert.Empty(t, errs)
+	assert.Equal(t, cfg, ValidationConfigFrom(newRequest(cfg).Context()))
+
+	cfg = &ValidationConfig{Disabled: map[string]bool{"Required": true}}
+	req, _ := http.NewRequest("GET", "/", nil)
+	form = planForm{}
+	errs = Validate(req.WithContext(WithValidationConfig(req.Context(), cfg)), &form)
+	assert.Empty(t, errs)
+	errs = RawValidate(&form)
+	assert.Len(t, errs, 1)
+
+	// Invalid limits are found by Check, and reported by requests.
+	type cartForm struct {
+		Count int    `form:"count" binding:"Max(10)"`
+		Note  string `form:"note" binding:"MaxSize(3)"`
+	}
+	cfg = &ValidationConfig{Limits: map[string]string{"Count.Max": "ten"}}
+	if err := cfg.Check(); assert.Error(t, err) {
+		assert.EqualValues(t, `limit Count.Max: Max(ten): invalid number "ten"`, err.Error())
+	}
+	assert.Error(t, (&ValidationConfig{Limits: map[string]string{"Max": "10"}}).Check())
+	assert.NoError(t, (&ValidationConfig{Limits: map[string]string{"Count.Max": "20"}}).Check())
+	req, _ = http.NewRequest("GET", "/?count=5&note=long", nil)
+	var cart cartForm
+	errs = Form(req.WithContext(WithValidationConfig(req.Context(), cfg)), &cart)
+	if assert.Len(t, errs, 2) {
+		assert.EqualValues(t, ERR_CONFIG, errs[0].Classification)
+		assert.EqualValues(t, []string{"Count"}, errs[0].FieldNames)
+		assert.EqualValues(t, ERR_MAX_SIZE, errs[1].Classification)
+	}
+	rw := httptest.NewRecorder()
+	RespondErrors(rw, nil, errs)
+	assert.EqualValues(t, http.StatusInternalServerError, rw.Code)
+}
+
+func Test_FlagProvider(t *testing.T) {
+	strict := false
+	SetFlagProvider(FlagProviderFunc(func(field, rule string) bool {
+		return strict || !(field == "Title" && rule == "MaxSize")
+	}))
+	defer SetFlagProvider(nil)
+
+	req, _ := http.NewRequest("GET", "/?title=A+rather+long+title", nil)
+	var form planForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+
+	strict = true
+	form = planForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, ERR_MAX_SIZE, errs[0].Classification)
+
+	cfg := &ValidationConfig{Flags: FlagProviderFunc(func(field, rule string) bool {
+		return rule != "Required"
+	})}
+	req, _ = http.NewRequest("GET", "/", nil)
+	form = planForm{}
+	errs = Form(req.WithContext(WithValidationConfig(req.Context(), cfg)), &form)
+	assert.Empty(t, errs)
+}
+
+func Test_RuleSets(t *testing.T) {
+	DefineRuleSet("username", "Required;AlphaDash;MinSize(3);MaxSize(32)")
+	DefineRuleSet("handle", "@username;Exclude(admin)")
+	defer func() {
+		delete(ruleSets, "username")
+		delete(ruleSets, "handle")
+	}()
+
+	type signupForm struct {
+		Username string `form:"username" binding:"@username"`
+		Handle   string `form:"handle" binding:"@handle;Include(_)"`
+	}
+
+	req, _ := http.NewRequest("GET", "/?username=alice&handle=alice_x", nil)
+	var form signupForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+
+	req, _ = http.NewRequest("GET", "/?username=a!&handle=super_admin", nil)
+	form = signupForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, ERR_ALPHA_DASH, errs[0].Classification)
+	assert.EqualValues(t, ERR_EXCLUDE, errs[1].Classification)
+
+	req, _ = http.NewRequest("GET", "/?handle=bob", nil)
+	form = signupForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+	assert.EqualValues(t, ERR_INCLUDE, errs[1].Classification)
+
+	assert.Panics(t, func() { DefineRuleSet("broken", "@missing") })
+
+	// Unknown rule sets in tags fail on first use, whatever the values.
+	type brokenForm struct {
+		Name string `form:"name" binding:"@missing"`
+	}
+	const msg = "binding: invalid tags of binding.brokenForm.Name: unknown rule set @missing"
+	assert.PanicsWithValue(t, msg, func() { RawValidate(brokenForm{}) })
+	assert.PanicsWithValue(t, msg, func() { RawValidate(brokenForm{Name: "x"}) })
+	req, _ = http.NewRequest("GET", "/?name=x", nil)
+	assert.PanicsWithValue(t, msg, func() { Form(req, &brokenForm{}) })
+}
+
+func Test_SplitRules(t *testing.T) {
+	assert.EqualValues(t, []string{"Required", "MaxSize(5)"}, splitRules("Required;MaxSize(5)"))
+	assert.EqualValues(t, []string{"Match(^a;b$)", "Required"}, splitRules(`Match(^a\;b$);Required`))
+	assert.EqualValues(t, []string{`Match(^\d$)`}, splitRules(`Match(^\d$)`))
+}
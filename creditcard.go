@@ -0,0 +1,107 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cardBrand describes the card numbers of a brand: the ranges of their
+// leading digits and their lengths.
+type cardBrand struct {
+	prefixes [][2]int
+	lengths  [2]int
+}
+
+// cardBrands are the brands CreditCard may be restricted to, by the name
+// used in its parameters.
+var cardBrands = map[string]cardBrand{
+	"visa":       {[][2]int{{4, 4}}, [2]int{13, 19}},
+	"mastercard": {[][2]int{{51, 55}, {2221, 2720}}, [2]int{16, 16}},
+	"amex":       {[][2]int{{34, 34}, {37, 37}}, [2]int{15, 15}},
+	"discover":   {[][2]int{{6011, 6011}, {644, 649}, {65, 65}, {622126, 622925}}, [2]int{16, 19}},
+	"diners":     {[][2]int{{300, 305}, {36, 36}, {38, 39}}, [2]int{14, 19}},
+	"jcb":        {[][2]int{{3528, 3589}}, [2]int{16, 19}},
+	"unionpay":   {[][2]int{{62, 62}}, [2]int{16, 19}},
+}
+
+func init() {
+	ruleParamCheckers["CreditCard"] = func(brands string) error {
+		for _, name := range strings.Split(brands, ",") {
+			if _, ok := cardBrands[strings.ToLower(strings.TrimSpace(name))]; !ok {
+				return fmt.Errorf("unknown card brand %q", name)
+			}
+		}
+		return nil
+	}
+}
+
+// match reports whether number, a string of digits, is a card number of
+// the brand.
+func (b cardBrand) match(number string) bool {
+	if len(number) < b.lengths[0] || len(number) > b.lengths[1] {
+		return false
+	}
+	for _, r := range b.prefixes {
+		digits := len(strconv.Itoa(r[0]))
+		if prefix, _ := strconv.Atoi(number[:digits]); prefix >= r[0] && prefix <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// isCreditCard reports whether v is a payment card number of 12 to 19
+// digits, which may be grouped by spaces or hyphens, with a valid Luhn
+// check digit. If brands, the parameters of the rule such as
+// "visa,mastercard", is not empty, the number must also belong to one of
+// them. It panics on unknown brands, which checkRules reports for rules.
+func isCreditCard(v interface{}, brands string) bool {
+	number := strings.NewReplacer(" ", "", "-", "").Replace(ruleString(v))
+	if len(number) < 12 || len(number) > 19 || strings.Trim(number, "0123456789") != "" || !luhn(number) {
+		return false
+	}
+	if brands == "" {
+		return true
+	}
+	for _, name := range strings.Split(brands, ",") {
+		brand, ok := cardBrands[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			panic("binding: unknown card brand " + name)
+		}
+		if brand.match(number) {
+			return true
+		}
+	}
+	return false
+}
+
+// luhn reports whether the last digit of number, a string of digits, is
+// its Luhn check digit.
+func luhn(number string) bool {
+	sum := 0
+	for i := range number {
+		d := int(number[len(number)-1-i] - '0')
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
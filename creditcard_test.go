@@ -0,0 +1,41 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CardBrands(t *testing.T) {
+	for brand, number := range map[string]string{
+		"visa":       "4012888888881881",
+		"mastercard": "5105105105105100",
+		"amex":       "371449635398431",
+		"discover":   "6011000990139424",
+		"diners":     "30569309025904",
+		"jcb":        "3566002020360505",
+		"unionpay":   "6200000000000005",
+	} {
+		assert.True(t, isCreditCard(number, brand), brand)
+		for other := range cardBrands {
+			if other != brand {
+				assert.False(t, isCreditCard(number, other), brand+" as "+other)
+			}
+		}
+	}
+	assert.Panics(t, func() { isCreditCard("4111111111111111", "vsia") })
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"reflect"
+
+	"github.com/goccy/go-json"
+)
+
+// CursorCodec encodes and decodes opaque pagination cursors.
+type CursorCodec interface {
+	// Encode turns a payload into a token that can be handed to clients.
+	Encode(payload []byte) (string, error)
+	// Decode verifies a token and returns its payload.
+	Decode(token string) ([]byte, error)
+}
+
+// ErrInvalidCursor is returned by the default codec for malformed or
+// tampered cursors.
+var ErrInvalidCursor = errors.New("Invalid cursor")
+
+type hmacCursorCodec struct {
+	key []byte
+}
+
+// NewHMACCursorCodec returns a codec producing URL-safe base64 tokens
+// signed with HMAC-SHA256 using the given key.
+func NewHMACCursorCodec(key []byte) CursorCodec {
+	return &hmacCursorCodec{key: key}
+}
+
+func (c *hmacCursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func (c *hmacCursorCodec) Encode(payload []byte) (string, error) {
+	return base64.RawURLEncoding.EncodeToString(append(payload, c.sign(payload)...)), nil
+}
+
+func (c *hmacCursorCodec) Decode(token string) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) < sha256.Size {
+		return nil, ErrInvalidCursor
+	}
+	payload, sum := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if !hmac.Equal(sum, c.sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+	return payload, nil
+}
+
+// cursorCodec defaults to a codec with a random key, which means cursors
+// do not survive restarts and cannot be shared between instances.
+// Use SetCursorCodec to configure a stable key.
+var cursorCodec CursorCodec
+
+func init() {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	cursorCodec = NewHMACCursorCodec(key)
+}
+
+// SetCursorCodec sets the codec used to encode and decode cursors.
+func SetCursorCodec(codec CursorCodec) {
+	cursorCodec = codec
+}
+
+// Cursor is an opaque pagination token. When a Cursor field is bound
+// from a form, query or JSON value, the token is decoded and verified
+// with the configured CursorCodec, and a malformed or tampered token
+// results in an ERR_CURSOR validation error.
+type Cursor struct {
+	// Token is the token as sent by the client.
+	Token string
+	// Payload is the decoded payload of the token.
+	Payload []byte
+
+	err error
+}
+
+// EncodeCursor marshals v as JSON and encodes it into a cursor token.
+func EncodeCursor(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return cursorCodec.Encode(payload)
+}
+
+// Unmarshal unmarshals the JSON payload of the cursor into v.
+func (c Cursor) Unmarshal(v interface{}) error {
+	return json.Unmarshal(c.Payload, v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Invalid tokens are reported
+// during validation rather than as a deserialization error.
+func (c *Cursor) UnmarshalJSON(data []byte) error {
+	var token string
+	if err := json.Unmarshal(data, &token); err != nil {
+		return err
+	}
+	c.decode(token)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c Cursor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Token)
+}
+
+func (c *Cursor) decode(token string) {
+	*c = Cursor{Token: token}
+	if len(token) == 0 {
+		return
+	}
+	c.Payload, c.err = cursorCodec.Decode(token)
+}
+
+// cursorError returns the error of the token of the Cursor held by val,
+// which may also be a pointer to a Cursor or an Optional holding one.
+func cursorError(val reflect.Value) error {
+	for val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	if !val.IsValid() {
+		return nil
+	}
+	if isOptional(val.Type()) {
+		return cursorError(val.FieldByName("Value"))
+	}
+	if c, ok := val.Interface().(Cursor); ok {
+		return c.err
+	}
+	return nil
+}
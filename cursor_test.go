@@ -0,0 +1,109 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cursorForm struct {
+	After Cursor `form:"after" json:"after"`
+	Limit int    `form:"limit" json:"limit"`
+}
+
+type cursorPayload struct {
+	ID int `json:"id"`
+}
+
+func Test_Cursor(t *testing.T) {
+	token, err := EncodeCursor(cursorPayload{ID: 42})
+	assert.NoError(t, err)
+
+	t.Run("Form", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/?limit=10&after="+token, nil)
+		var form cursorForm
+		errs := Form(req, &form)
+		assert.Empty(t, errs)
+		assert.EqualValues(t, 10, form.Limit)
+
+		var payload cursorPayload
+		assert.NoError(t, form.After.Unmarshal(&payload))
+		assert.EqualValues(t, 42, payload.ID)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"after":"`+token+`"}`))
+		var form cursorForm
+		errs := JSON(req, &form)
+		assert.Empty(t, errs)
+		assert.EqualValues(t, token, form.After.Token)
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		tampered := token[:len(token)-2] + "AA"
+		req, _ := http.NewRequest("GET", "/?after="+tampered, nil)
+		var form cursorForm
+		errs := Form(req, &form)
+		assert.Len(t, errs, 1)
+		assert.True(t, errs.Has(ERR_CURSOR))
+
+		req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"after":"not-a-cursor"}`))
+		errs = JSON(req, &form)
+		assert.True(t, errs.Has(ERR_CURSOR))
+	})
+
+	t.Run("Pointer and Optional", func(t *testing.T) {
+		type pageForm struct {
+			After  *Cursor          `form:"after" json:"after"`
+			Before Optional[Cursor] `form:"before" json:"before"`
+		}
+		req, _ := http.NewRequest("GET", "/?after="+token+"&before="+token, nil)
+		var form pageForm
+		assert.Empty(t, Form(req, &form))
+		if assert.NotNil(t, form.After) {
+			assert.EqualValues(t, token, form.After.Token)
+		}
+		assert.EqualValues(t, token, form.Before.Value.Token)
+
+		req, _ = http.NewRequest("GET", "/?after=not-a-cursor&before=not-a-cursor", nil)
+		form = pageForm{}
+		errs := Form(req, &form)
+		if assert.Len(t, errs, 2) {
+			assert.EqualValues(t, []string{"After"}, errs[0].FieldNames)
+			assert.EqualValues(t, ERR_CURSOR, errs[0].Classification)
+			assert.EqualValues(t, []string{"Before"}, errs[1].FieldNames)
+			assert.EqualValues(t, ERR_CURSOR, errs[1].Classification)
+		}
+
+		req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"after":"not-a-cursor","before":"not-a-cursor"}`))
+		form = pageForm{}
+		errs = JSON(req, &form)
+		assert.Len(t, errs, 2)
+		assert.True(t, errs.Has(ERR_CURSOR))
+	})
+
+	t.Run("Custom codec", func(t *testing.T) {
+		defer SetCursorCodec(cursorCodec)
+		SetCursorCodec(NewHMACCursorCodec([]byte("secret")))
+		req, _ := http.NewRequest("GET", "/?after="+token, nil)
+		var form cursorForm
+		errs := Form(req, &form)
+		assert.True(t, errs.Has(ERR_CURSOR))
+	})
+}
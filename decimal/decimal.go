@@ -0,0 +1,118 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package decimal adds binding and validation support for
+// github.com/shopspring/decimal, so that monetary amounts never pass
+// through float64. Import it for its side effects:
+//
+//	import _ "gitea.com/go-chi/binding/decimal"
+//
+// Form values are parsed with decimal.NewFromString, and JSON bodies use
+// the decimal's own unmarshaler. The following rules are registered:
+//
+//	DecimalMin(0.01)  value must be greater than or equal to 0.01
+//	DecimalMax(100)   value must be less than or equal to 100
+//	DecimalScale(2)   value must have at most 2 digits after the decimal point
+package decimal
+
+import (
+	"strconv"
+	"strings"
+
+	"gitea.com/go-chi/binding"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ERR_DECIMAL_TYPE  = "DecimalTypeError"
+	ERR_DECIMAL_MIN   = "DecimalMinError"
+	ERR_DECIMAL_MAX   = "DecimalMaxError"
+	ERR_DECIMAL_SCALE = "DecimalScaleError"
+)
+
+func init() {
+	binding.AddConverter(decimal.Decimal{}, func(val string) (interface{}, error) {
+		if len(val) == 0 {
+			return decimal.Decimal{}, nil
+		}
+		d, err := decimal.NewFromString(val)
+		if err != nil {
+			return nil, binding.Error{Classification: ERR_DECIMAL_TYPE, Message: "Value could not be parsed as decimal"}
+		}
+		return d, nil
+	})
+
+	binding.AddParamRule(&binding.ParamRule{
+		IsMatch: func(rule string) bool {
+			return strings.HasPrefix(rule, "DecimalMin(")
+		},
+		IsValid: func(errs binding.Errors, rule, name string, v interface{}) (bool, binding.Errors) {
+			return check(errs, name, v, rule[11:len(rule)-1], ERR_DECIMAL_MIN, "DecimalMin", func(d, param decimal.Decimal) bool {
+				return d.GreaterThanOrEqual(param)
+			})
+		},
+	})
+	binding.AddParamRule(&binding.ParamRule{
+		IsMatch: func(rule string) bool {
+			return strings.HasPrefix(rule, "DecimalMax(")
+		},
+		IsValid: func(errs binding.Errors, rule, name string, v interface{}) (bool, binding.Errors) {
+			return check(errs, name, v, rule[11:len(rule)-1], ERR_DECIMAL_MAX, "DecimalMax", func(d, param decimal.Decimal) bool {
+				return d.LessThanOrEqual(param)
+			})
+		},
+	})
+	binding.AddParamRule(&binding.ParamRule{
+		IsMatch: func(rule string) bool {
+			return strings.HasPrefix(rule, "DecimalScale(")
+		},
+		IsValid: func(errs binding.Errors, rule, name string, v interface{}) (bool, binding.Errors) {
+			d, ok := value(v)
+			if !ok {
+				return true, errs
+			}
+			scale, _ := strconv.Atoi(rule[13 : len(rule)-1])
+			if !d.Equal(d.Truncate(int32(scale))) {
+				errs.Add([]string{name}, ERR_DECIMAL_SCALE, "DecimalScale")
+				return false, errs
+			}
+			return true, errs
+		},
+	})
+}
+
+func value(v interface{}) (decimal.Decimal, bool) {
+	switch d := v.(type) {
+	case decimal.Decimal:
+		return d, true
+	case *decimal.Decimal:
+		if d != nil {
+			return *d, true
+		}
+	}
+	return decimal.Decimal{}, false
+}
+
+func check(errs binding.Errors, name string, v interface{}, param, classification, message string, valid func(d, param decimal.Decimal) bool) (bool, binding.Errors) {
+	d, ok := value(v)
+	if !ok {
+		return true, errs
+	}
+	p, err := decimal.NewFromString(param)
+	if err != nil || !valid(d, p) {
+		errs.Add([]string{name}, classification, message)
+		return false, errs
+	}
+	return true, errs
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package decimal
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitea.com/go-chi/binding"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type payment struct {
+	Amount decimal.Decimal  `form:"amount" json:"amount" binding:"Required;DecimalMin(0.01);DecimalMax(1000);DecimalScale(2)"`
+	Fee    *decimal.Decimal `form:"fee" json:"fee" binding:"DecimalScale(2)"`
+}
+
+func Test_Form(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", strings.NewReader("amount=12.50"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var p payment
+	errs := binding.Form(req, &p)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "12.5", p.Amount.String())
+
+	for payload, classification := range map[string]string{
+		"amount=abc":     ERR_DECIMAL_TYPE,
+		"amount=0":       ERR_DECIMAL_MIN,
+		"amount=1000.01": ERR_DECIMAL_MAX,
+		"amount=1.005":   ERR_DECIMAL_SCALE,
+	} {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		var p payment
+		errs := binding.Form(req, &p)
+		assert.True(t, errs.Has(classification), payload)
+	}
+}
+
+func Test_JSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"amount":9007199254740993.25,"fee":"0.125"}`))
+	var p payment
+	errs := binding.JSON(req, &p)
+	assert.Len(t, errs, 2)
+	assert.True(t, errs.Has(ERR_DECIMAL_MAX))
+	assert.True(t, errs.Has(ERR_DECIMAL_SCALE))
+	assert.EqualValues(t, "9007199254740993.25", p.Amount.String())
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// MaxDecompressedSize is the maximum size in bytes of request bodies sent
+// with Content-Encoding gzip or deflate, after they are decompressed.
+// Larger bodies are rejected with ERR_LIMIT, so that small compressed
+// bodies cannot expand without bound. Zero means no limit.
+var MaxDecompressedSize int64 = 10 << 20
+
+// WithMaxDecompressedSize sets the maximum size of decompressed request
+// bodies; see MaxDecompressedSize.
+func WithMaxDecompressedSize(n int64) Option {
+	return func(b *Binder) {
+		b.maxDecompressedSize = n
+	}
+}
+
+var errDecompressedSize = errors.New("decompressed body too large")
+
+// inflatedBody reads a decompressed request body of at most limit bytes.
+type inflatedBody struct {
+	io.Reader
+	io.Closer
+	limit, n int64
+}
+
+func (b *inflatedBody) Read(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.Reader.Read(p)
+	}
+	if b.n > b.limit {
+		return 0, errDecompressedSize
+	}
+	// Read one byte past the limit to tell larger bodies apart.
+	if room := b.limit - b.n + 1; int64(len(p)) > room {
+		p = p[:room]
+	}
+	n, err := b.Reader.Read(p)
+	b.n += int64(n)
+	if b.n > b.limit {
+		return n - 1, errDecompressedSize
+	}
+	return n, err
+}
+
+// check returns an ERR_LIMIT error instead of errors if the body was
+// larger than allowed, as decoders report it as malformed.
+func (b *inflatedBody) check(errors Errors) Errors {
+	if b == nil || b.limit <= 0 || b.n <= b.limit {
+		return errors
+	}
+	var errs Errors
+	errs.Add([]string{}, ERR_LIMIT, fmt.Sprintf("Decompressed body is larger than %d bytes", b.limit))
+	return errs
+}
+
+// decompressBody replaces the body of req, if it has a Content-Encoding
+// of gzip or deflate, with its decompressed content, and removes the
+// Content-Encoding. The header is copied first, as it is shared with the
+// requests req was derived from. Other encodings are reported as
+// ERR_CONTENT_TYPE.
+func decompressBody(req *http.Request) (*inflatedBody, Errors) {
+	header := req.Header.Get("Content-Encoding")
+	if header == "" || req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	var errs Errors
+	codings := strings.Split(header, ",")
+	body := io.Reader(req.Body)
+	for i := len(codings) - 1; i >= 0; i-- {
+		var err error
+		switch strings.ToLower(strings.TrimSpace(codings[i])) {
+		case "identity", "":
+		case "gzip", "x-gzip":
+			body, err = gzip.NewReader(body)
+		case "deflate":
+			body, err = newDeflateReader(body)
+		default:
+			errs.Add([]string{}, ERR_CONTENT_TYPE, "Unsupported Content-Encoding")
+			return nil, errs
+		}
+		if err != nil {
+			errs.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+			return nil, errs
+		}
+	}
+
+	inflated := &inflatedBody{Reader: body, Closer: req.Body, limit: binderFrom(req).maxDecompressedSize}
+	req.Body = inflated
+	req.ContentLength = -1
+	req.Header = req.Header.Clone()
+	req.Header.Del("Content-Encoding")
+	return inflated, nil
+}
+
+// newDeflateReader reads deflate content, which should be wrapped in the
+// zlib format but is sent raw by some clients.
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
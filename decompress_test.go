@@ -0,0 +1,140 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func compressed(t *testing.T, encoding, body string) *http.Request {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+		encoding = "deflate"
+	}
+	_, err := w.Write([]byte(body))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	req, _ := http.NewRequest("POST", "/", &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", encoding)
+	return req
+}
+
+func Test_DecompressBody(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate", "raw deflate"} {
+		var post Post
+		req := compressed(t, encoding, `{"title":"Hello, world","content":"Compressed"}`)
+		errs := Bind(req, &post)
+		assert.Empty(t, errs, encoding)
+		assert.EqualValues(t, Post{Title: "Hello, world", Content: "Compressed"}, post, encoding)
+		assert.NotEmpty(t, req.Header.Get("Content-Encoding"))
+	}
+
+	// The header of the bound request is a copy, which leaves the header of
+	// the request it was derived from alone.
+	var post Post
+	outer := compressed(t, "gzip", `{"title":"Hello, world"}`)
+	req := withState(outer)
+	assert.Empty(t, Bind(req, &post))
+	assert.Empty(t, req.Header.Get("Content-Encoding"))
+	assert.EqualValues(t, "gzip", outer.Header.Get("Content-Encoding"))
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"title":"Hello, world"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "br")
+	errs := Bind(req, &post)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_CONTENT_TYPE, errs[0].Classification)
+		assert.EqualValues(t, "Unsupported Content-Encoding", errs[0].Message)
+	}
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"title":"Hello, world"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	errs = Bind(req, &post)
+	if assert.NotEmpty(t, errs) {
+		assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+	}
+}
+
+func Test_MaxDecompressedSize(t *testing.T) {
+	body := `{"title":"Hello, world","content":"` + strings.Repeat("x", 1000) + `"}`
+	b := New(WithMaxDecompressedSize(int64(len(body))))
+	var post Post
+	assert.Empty(t, b.Bind(compressed(t, "gzip", body), &post))
+
+	b = New(WithMaxDecompressedSize(int64(len(body) - 1)))
+	errs := b.Bind(compressed(t, "gzip", body), &post)
+	if assert.NotEmpty(t, errs) {
+		assert.EqualValues(t, ERR_LIMIT, errs[0].Classification)
+		assert.EqualValues(t, fmt.Sprintf("Decompressed body is larger than %d bytes", len(body)-1), errs[0].Message)
+	}
+
+	req := compressed(t, "gzip", strings.Repeat(`{"title":"Hello, world"}`+"\n", 100))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	var n int
+	errs, err := BindEach(b.attach(req), func() interface{} { return new(Post) }, func(int, interface{}, Errors) error {
+		n++
+		return nil
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_LIMIT, errs[0].Classification)
+	}
+	assert.EqualValues(t, (len(body)-1)/25, n)
+}
+
+func Test_DecompressSignedBody(t *testing.T) {
+	defer func(saved []BodyVerifier) { bodyVerifiers = saved }(bodyVerifiers)
+	secret := []byte("secret")
+	AddBodyVerifier(HMACVerifier("X-Signature", secret, sha256.New))
+
+	req := compressed(t, "gzip", `{"title":"Hello, world"}`)
+	wire, _ := ioutil.ReadAll(req.Body)
+	req.Body = ioutil.NopCloser(bytes.NewReader(wire))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(wire)
+	req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	req = withState(req)
+
+	var post Post
+	errs := New(WithRawBody(1<<10)).Bind(req, &post)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "Hello, world", post.Title)
+	raw, truncated := RawBody(req)
+	assert.False(t, truncated)
+	assert.EqualValues(t, wire, raw)
+}
@@ -0,0 +1,207 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+)
+
+// validation holds the state shared while validating a request.
+type validation struct {
+	// req is nil when validating without a request, see RawValidate.
+	req *http.Request
+	cfg *ValidationConfig
+	// presence is set if Required accepts present zero values, see
+	// RequirePresence.
+	presence bool
+	// b holds the settings of the request.
+	b *Binder
+	// provided holds the paths of the fields present in the request,
+	// relative to the struct being validated at prefix.
+	provided map[string]bool
+	// mask holds the field mask of the request, if any.
+	mask   *fieldMask
+	prefix string
+}
+
+// at returns the state for validating the nested struct name.
+func (vd *validation) at(name string) *validation {
+	if vd.provided == nil && vd.mask == nil {
+		return vd
+	}
+	nested := *vd
+	nested.prefix += name + "."
+	return &nested
+}
+
+// isProvided reports whether the field name was present in the request.
+func (vd *validation) isProvided(name string) bool {
+	return vd.provided[vd.prefix+name]
+}
+
+// isMasked reports whether the field name is validated under the field
+// mask of the request.
+func (vd *validation) isMasked(name string) bool {
+	return vd.mask == nil || vd.mask.covers(vd.prefix+name)
+}
+
+// splitDive splits the rules of a field at the Dive marker into the rules
+// of the field itself, the rules of its elements and, for maps, the rules
+// of its keys, which follow Dive between the Keys and EndKeys markers:
+//
+//	binding:"MaxSize(10);Dive;Keys;AlphaDash;EndKeys;MaxSize(100)"
+func splitDive(rules []string) (own, elem, keys []string) {
+	for i, rule := range rules {
+		if rule != "Dive" {
+			continue
+		}
+		own, elem = rules[:i], rules[i+1:]
+		if len(elem) > 0 && elem[0] == "Keys" {
+			for j, rule := range elem {
+				if rule == "EndKeys" {
+					keys, elem = elem[1:j], elem[j+1:]
+					break
+				}
+			}
+		}
+		return own, elem, keys
+	}
+	return rules, nil, nil
+}
+
+// validateDive applies rules to every element of the slice or array, or
+// to every value and key of the map held by fieldVal. Errors are reported
+// for the field name followed by the index or key in brackets, e.g.
+// "Emails[2]" or "Labels[priority]".
+func validateDive(errors Errors, vd *validation, elemRules, keyRules []string, field reflect.StructField, fieldVal reflect.Value) Errors {
+	fieldVal = reflect.Indirect(fieldVal)
+	switch fieldVal.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fieldVal.Len(); i++ {
+			errors = validateElement(errors, vd, elemRules, field, fmt.Sprintf("%s[%d]", field.Name, i), fieldVal.Index(i))
+		}
+		return errors
+	case reflect.Map:
+	default:
+		return errors
+	}
+
+	keys := fieldVal.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	for _, key := range keys {
+		name := fmt.Sprintf("%s[%v]", field.Name, key.Interface())
+		if keyRules != nil {
+			errors = validateElement(errors, vd, keyRules, field, name, key)
+		}
+		errors = validateElement(errors, vd, elemRules, field, name, fieldVal.MapIndex(key))
+	}
+	return errors
+}
+
+// validateElement applies rules to an element of field, reporting errors
+// under the given name.
+func validateElement(errors Errors, vd *validation, rules []string, field reflect.StructField, name string, val reflect.Value) Errors {
+	elem := field
+	elem.Name = name
+	elem.Type = val.Type()
+	return validateField(errors, vd, rules, reflect.Zero(val.Type()).Interface(), elem, val, val.Interface())
+}
+
+// validateElements validates the structs held by the slice, array or map
+// val, also inside pointers and nested collections, and runs their
+// Validators. Errors are reported with paths such as "Items[0].Name".
+func validateElements(errors Errors, vd *validation, name string, val reflect.Value) Errors {
+	if !holdsStructs(val.Type().Elem()) {
+		return errors
+	}
+	if val.Kind() == reflect.Map {
+		keys := val.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			errors = validateElementStruct(errors, vd, fmt.Sprintf("%s[%v]", name, key.Interface()), val.MapIndex(key))
+		}
+		return errors
+	}
+	for i := 0; i < val.Len(); i++ {
+		errors = validateElementStruct(errors, vd, fmt.Sprintf("%s[%d]", name, i), val.Index(i))
+	}
+	return errors
+}
+
+func validateElementStruct(errors Errors, vd *validation, path string, elem reflect.Value) Errors {
+	for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface {
+		if elem.IsNil() {
+			return errors
+		}
+		elem = elem.Elem()
+	}
+
+	switch elem.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return validateElements(errors, vd, path, elem)
+	case reflect.Struct:
+	default:
+		return errors
+	}
+
+	obj := elem.Interface()
+	if elem.CanAddr() {
+		obj = elem.Addr().Interface()
+	}
+	n := len(errors)
+	errors = validateStruct(errors, vd.at(path), obj)
+	if validator, ok := obj.(Validator); ok && vd.req != nil {
+		errors = validator.Validate(vd.req, errors)
+	}
+	prefixFieldNames(errors[n:], path)
+	return errors
+}
+
+// holdsStructs reports whether values of typ may contain structs.
+func holdsStructs(typ reflect.Type) bool {
+	for {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			typ = typ.Elem()
+		case reflect.Struct, reflect.Interface:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// prefixFieldNames prefixes the field names of errors with path, or sets
+// them to path if the errors have none.
+func prefixFieldNames(errors Errors, path string) {
+	for i := range errors {
+		if len(errors[i].FieldNames) == 0 {
+			errors[i].FieldNames = []string{path}
+			continue
+		}
+		names := make([]string, len(errors[i].FieldNames))
+		for j, name := range errors[i].FieldNames {
+			names[j] = path + "." + name
+		}
+		errors[i].FieldNames = names
+	}
+}
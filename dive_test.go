@@ -0,0 +1,131 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type labelsForm struct {
+	Labels map[string]string `json:"labels" binding:"MaxSize(3);Dive;Keys;AlphaDash;EndKeys;Required;MaxSize(5)"`
+	Limits map[string]int    `json:"limits" binding:"Dive;Range(1,10)"`
+}
+
+func Test_DiveMap(t *testing.T) {
+	errs := RawValidate(&labelsForm{
+		Labels: map[string]string{"team": "core", "tier": "gold"},
+		Limits: map[string]int{"cpu": 4},
+	})
+	assert.Empty(t, errs)
+
+	errs = RawValidate(&labelsForm{
+		Labels: map[string]string{"team!": "core", "tier": "platinum", "zone": ""},
+		Limits: map[string]int{"cpu": 4, "mem": 20},
+	})
+	assert.Len(t, errs, 4)
+	assert.EqualValues(t, []string{"Labels[team!]"}, errs[0].FieldNames)
+	assert.EqualValues(t, ERR_ALPHA_DASH, errs[0].Classification)
+	assert.EqualValues(t, []string{"Labels[tier]"}, errs[1].FieldNames)
+	assert.EqualValues(t, ERR_MAX_SIZE, errs[1].Classification)
+	assert.EqualValues(t, []string{"Labels[zone]"}, errs[2].FieldNames)
+	assert.EqualValues(t, ERR_REQUIRED, errs[2].Classification)
+	assert.EqualValues(t, []string{"Limits[mem]"}, errs[3].FieldNames)
+	assert.EqualValues(t, ERR_RANGE, errs[3].Classification)
+
+	errs = RawValidate(&labelsForm{Labels: map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"}})
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, []string{"Labels"}, errs[0].FieldNames)
+}
+
+type recipientsForm struct {
+	Emails []string  `form:"email" binding:"Required;MaxSize(3);Dive;Email"`
+	Scores [][]int   `binding:"Dive;MaxSize(2);Dive;Range(0,100)"`
+	Tags   [2]string `binding:"Dive;AlphaDash"`
+	CC     *[]string `binding:"Dive;Email"`
+}
+
+func Test_DiveSlice(t *testing.T) {
+	cc := []string{"carol@example.com"}
+	errs := RawValidate(&recipientsForm{
+		Emails: []string{"alice@example.com", "bob@example.com"},
+		Scores: [][]int{{1, 2}, {100}},
+		Tags:   [2]string{"a", "b"},
+		CC:     &cc,
+	})
+	assert.Empty(t, errs)
+
+	cc = []string{"carol"}
+	errs = RawValidate(&recipientsForm{
+		Emails: []string{"alice@example.com", "bob", "", "dave@example.com"},
+		Scores: [][]int{{1, 2, 3}, {101}},
+		Tags:   [2]string{"a", "b!"},
+		CC:     &cc,
+	})
+	assert.Len(t, errs, 6)
+	assert.EqualValues(t, []string{"Emails"}, errs[0].FieldNames)
+	assert.EqualValues(t, ERR_MAX_SIZE, errs[0].Classification)
+	assert.EqualValues(t, []string{"Emails[1]"}, errs[1].FieldNames)
+	assert.EqualValues(t, ERR_EMAIL, errs[1].Classification)
+	assert.EqualValues(t, []string{"Scores[0]"}, errs[2].FieldNames)
+	assert.EqualValues(t, ERR_MAX_SIZE, errs[2].Classification)
+	assert.EqualValues(t, []string{"Scores[1][0]"}, errs[3].FieldNames)
+	assert.EqualValues(t, ERR_RANGE, errs[3].Classification)
+	assert.EqualValues(t, []string{"Tags[1]"}, errs[4].FieldNames)
+	assert.EqualValues(t, []string{"CC[0]"}, errs[5].FieldNames)
+}
+
+type lineItem struct {
+	SKU      string `binding:"Required"`
+	Quantity int    `binding:"Range(1,99)"`
+}
+
+func (item *lineItem) Validate(req *http.Request, errs Errors) Errors {
+	if item.SKU == "discontinued" {
+		errs.Add([]string{"SKU"}, "DiscontinuedError", "Discontinued")
+	}
+	return errs
+}
+
+type orderForm struct {
+	Items    []*lineItem
+	Batches  [][]lineItem
+	BySeller map[string]lineItem
+}
+
+func Test_NestedElements(t *testing.T) {
+	order := &orderForm{
+		Items:    []*lineItem{{SKU: "a", Quantity: 1}, nil, {Quantity: 1}, {SKU: "discontinued", Quantity: 100}},
+		Batches:  [][]lineItem{{{SKU: "b", Quantity: 1}}, {{SKU: "c", Quantity: 1}, {Quantity: 1}}},
+		BySeller: map[string]lineItem{"acme": {SKU: "d", Quantity: 100}},
+	}
+	req, _ := http.NewRequest("POST", "/", nil)
+	errs := Validate(req, order)
+	assert.Len(t, errs, 5)
+	assert.EqualValues(t, []string{"Items[2].SKU"}, errs[0].FieldNames)
+	assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+	assert.EqualValues(t, []string{"Items[3].Quantity"}, errs[1].FieldNames)
+	assert.EqualValues(t, ERR_RANGE, errs[1].Classification)
+	assert.EqualValues(t, []string{"Items[3].SKU"}, errs[2].FieldNames)
+	assert.EqualValues(t, "DiscontinuedError", errs[2].Classification)
+	assert.EqualValues(t, []string{"Batches[1][1].SKU"}, errs[3].FieldNames)
+	assert.EqualValues(t, []string{"BySeller[acme].Quantity"}, errs[4].FieldNames)
+
+	// RawValidate does not run Validators.
+	errs = RawValidate(order)
+	assert.Len(t, errs, 4)
+}
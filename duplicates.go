@@ -0,0 +1,97 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// DisallowDuplicateKeys makes a key repeated within a JSON object an
+// ERR_DUPLICATE_KEY error, instead of silently keeping its last value.
+// This matters for security sensitive payloads such as permission sets,
+// where different parsers may disagree on which value wins.
+var DisallowDuplicateKeys = false
+
+// WithDisallowDuplicateKeys sets whether keys repeated within JSON objects
+// are errors; see DisallowDuplicateKeys.
+func WithDisallowDuplicateKeys(disallow bool) Option {
+	return func(b *Binder) {
+		b.disallowDuplicateKeys = disallow
+	}
+}
+
+// duplicateJSONKeys reports an ERR_DUPLICATE_KEY error for each key
+// repeated within an object of the JSON document data, named by its path,
+// e.g. "roles[1].name". Malformed documents are left to the decoder.
+func duplicateJSONKeys(data []byte) Errors {
+	var errs Errors
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if scanJSONKeys(dec, "", &errs) != nil {
+		return nil
+	}
+	return errs
+}
+
+// foldKey returns key in a form that is equal for all keys that match it
+// case-insensitively, including under Unicode case folding such as "K"
+// (Kelvin sign) and "k".
+func foldKey(key string) string {
+	return strings.ToLower(strings.ToUpper(key))
+}
+
+func scanJSONKeys(dec *json.Decoder, path string, errs *Errors) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		seen := make(map[string]bool)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := tok.(string)
+			name := key
+			if path != "" {
+				name = path + "." + key
+			}
+			// Keys are matched to struct fields case-insensitively, so
+			// "admin" and "ADMIN" set the same field.
+			folded := foldKey(key)
+			if seen[folded] {
+				errs.Add([]string{name}, ERR_DUPLICATE_KEY, "Duplicate key")
+			}
+			seen[folded] = true
+			if err := scanJSONKeys(dec, name, errs); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := scanJSONKeys(dec, path+"["+strconv.Itoa(i)+"]", errs); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	}
+	return err
+}
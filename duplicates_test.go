@@ -0,0 +1,76 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DisallowDuplicateKeys(t *testing.T) {
+	type role struct {
+		Name  string `json:"name"`
+		Admin bool   `json:"admin"`
+	}
+	type grant struct {
+		User  string `json:"user"`
+		Roles []role `json:"roles"`
+	}
+	bind := func(bind func(*http.Request, interface{}) Errors, body string) (grant, Errors) {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		var g grant
+		errs := bind(req, &g)
+		return g, errs
+	}
+
+	body := `{"user":"a","roles":[{"name":"r","admin":false},{"name":"s","admin":false,"admin":true}],"user":"b"}`
+	g, errs := bind(JSON, body)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "b", g.User)
+
+	_, errs = bind(New(WithDisallowDuplicateKeys(true)).JSON, body)
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, []string{"roles[1].admin"}, errs[0].FieldNames)
+	assert.EqualValues(t, ERR_DUPLICATE_KEY, errs[0].Classification)
+	assert.EqualValues(t, []string{"user"}, errs[1].FieldNames)
+
+	DisallowDuplicateKeys = true
+	defer func() { DisallowDuplicateKeys = false }()
+	_, errs = bind(JSON, `{"user":"a","roles":[{"name":"r"},{"name":"s"}]}`)
+	assert.Empty(t, errs)
+	_, errs = bind(JSON, `{"user":"a","user":`)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+
+	// Keys differing only in case set the same field.
+	for _, body := range []string{
+		`{"user":"a","roles":[{"admin":false,"ADMIN":true}]}`,
+		`{"user":"a","roles":[{"name":"r","Name":"s"}]}`,
+		`{"user":"a","USER":"b"}`,
+	} {
+		_, errs = bind(JSON, body)
+		if assert.Len(t, errs, 1, body) {
+			assert.EqualValues(t, ERR_DUPLICATE_KEY, errs[0].Classification)
+		}
+	}
+	_, errs = bind(JSON, `{"user":"a","roles":[{"admin":false,"ADMIN":true}]}`)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, []string{"roles[0].ADMIN"}, errs[0].FieldNames)
+	}
+}
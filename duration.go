@@ -0,0 +1,100 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Fields of type time.Duration are parsed with time.ParseDuration, e.g.
+// "30s" or "1h15m", from form values as well as from JSON strings; JSON
+// numbers are still taken as nanoseconds.
+func init() {
+	AddConverter(time.Duration(0), func(val string) (interface{}, error) {
+		if val == "" {
+			return time.Duration(0), nil
+		}
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, Error{Classification: ERR_DURATION, Message: "Value could not be parsed as duration"}
+		}
+		return d, nil
+	})
+	ruleParamCheckers["MinDuration"] = func(param string) error {
+		_, err := time.ParseDuration(param)
+		return err
+	}
+	ruleParamCheckers["MaxDuration"] = ruleParamCheckers["MinDuration"]
+}
+
+// durationValue returns the duration held by a time.Duration or
+// *time.Duration field value.
+func durationValue(v interface{}) (time.Duration, bool) {
+	switch v := v.(type) {
+	case time.Duration:
+		return v, true
+	case *time.Duration:
+		if v != nil {
+			return *v, true
+		}
+	}
+	return 0, false
+}
+
+// parseDurationParam parses the parameter of a duration rule, such as the
+// 1s of MinDuration(1s), which checkRules has checked.
+func parseDurationParam(rule string, prefix int) time.Duration {
+	d, _ := time.ParseDuration(rule[prefix : len(rule)-1])
+	return d
+}
+
+// durationTypes caches whether types hold durations, see holdsDuration.
+var durationTypes sync.Map
+
+// holdsDuration reports whether values of typ may contain durations bound
+// from JSON, so that their strings need to be rewritten.
+func holdsDuration(typ reflect.Type) bool {
+	if v, ok := durationTypes.Load(typ); ok {
+		return v.(bool)
+	}
+	holds := typeHoldsDuration(typ, map[reflect.Type]bool{})
+	durationTypes.Store(typ, holds)
+	return holds
+}
+
+func typeHoldsDuration(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	if typ == durationType {
+		return true
+	}
+	if seen[typ] {
+		return false
+	}
+	seen[typ] = true
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return typeHoldsDuration(typ.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if typeHoldsDuration(typ.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
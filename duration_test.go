@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jobForm struct {
+	Timeout time.Duration   `form:"timeout" json:"timeout" binding:"MinDuration(1s);MaxDuration(1h)"`
+	Retry   *time.Duration  `form:"retry" json:"retry"`
+	Backoff []time.Duration `form:"backoff" json:"backoff"`
+}
+
+func Test_DurationForm(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", strings.NewReader("timeout=1h&retry=1m30s&backoff=1s&backoff=2.5s"))
+	req.Header.Set("Content-Type", formContentType)
+	var form jobForm
+	errs := Bind(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, time.Hour, form.Timeout)
+	assert.EqualValues(t, 90*time.Second, *form.Retry)
+	assert.EqualValues(t, []time.Duration{time.Second, 2500 * time.Millisecond}, form.Backoff)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader("timeout=500ms&retry=soon"))
+	req.Header.Set("Content-Type", formContentType)
+	form = jobForm{}
+	errs = Bind(req, &form)
+	if assert.Len(t, errs, 2) {
+		assert.EqualValues(t, []string{"retry"}, errs[0].FieldNames)
+		assert.EqualValues(t, ERR_DURATION, errs[0].Classification)
+		assert.EqualValues(t, []string{"Timeout"}, errs[1].FieldNames)
+		assert.EqualValues(t, ERR_MIN_DURATION, errs[1].Classification)
+	}
+}
+
+func Test_DurationJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"timeout":"45m","retry":30000000000,"backoff":["1s",2000000000]}`))
+	req.Header.Set("Content-Type", "application/json")
+	var form jobForm
+	errs := Bind(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, 45*time.Minute, form.Timeout)
+	assert.EqualValues(t, 30*time.Second, *form.Retry)
+	assert.EqualValues(t, []time.Duration{time.Second, 2 * time.Second}, form.Backoff)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"timeout":"2h"}`))
+	req.Header.Set("Content-Type", "application/json")
+	form = jobForm{}
+	errs = Bind(req, &form)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_MAX_DURATION, errs[0].Classification)
+	}
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"timeout":"forever"}`))
+	req.Header.Set("Content-Type", "application/json")
+	form = jobForm{}
+	errs = Bind(req, &form)
+	if assert.NotEmpty(t, errs) {
+		assert.EqualValues(t, []string{"Timeout"}, errs[0].FieldNames)
+		assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+	}
+}
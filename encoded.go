@@ -0,0 +1,72 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// isBase64 reports whether v is padded base64 of the standard alphabet
+// of RFC 4648, without line breaks.
+func isBase64(v interface{}) bool {
+	s := ruleString(v)
+	if strings.ContainsAny(s, "\r\n") {
+		return false
+	}
+	_, err := base64.StdEncoding.Strict().DecodeString(s)
+	return err == nil
+}
+
+// isBase64URL reports whether v is base64 of the URL and file name safe
+// alphabet of RFC 4648, with or without padding.
+func isBase64URL(v interface{}) bool {
+	s := ruleString(v)
+	if strings.ContainsAny(s, "\r\n") {
+		return false
+	}
+	enc := base64.RawURLEncoding
+	if strings.HasSuffix(s, "=") {
+		enc = base64.URLEncoding
+	}
+	_, err := enc.Strict().DecodeString(s)
+	return err == nil
+}
+
+// isHexadecimal reports whether v is a hexadecimal number, such as "ff"
+// or "0x1F", of any length.
+func isHexadecimal(v interface{}) bool {
+	s := ruleString(v)
+	if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+	}
+	return s != "" && isHexDigits(s)
+}
+
+// isHexColor reports whether v is a CSS hex color, #RGB or #RRGGBB.
+func isHexColor(v interface{}) bool {
+	s := ruleString(v)
+	return (len(s) == 4 || len(s) == 7) && s[0] == '#' && isHexDigits(s[1:])
+}
+
+func isHexDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
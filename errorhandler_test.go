@@ -19,6 +19,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -60,6 +61,48 @@ var errorTestCases = []errorTestCase{
 			body:        `[{"classification":"ContentTypeError","message":"Empty Content-Type"}]`,
 		},
 	},
+	{
+		description: "Limit error",
+		errors: Errors{
+			{
+				Classification: ERR_LIMIT,
+				Message:        "Too many keys, at most 3 are allowed",
+			},
+		},
+		expected: errorTestResult{
+			statusCode:  http.StatusBadRequest,
+			contentType: _JSON_CONTENT_TYPE,
+			body:        `[{"classification":"LimitError","message":"Too many keys, at most 3 are allowed"}]`,
+		},
+	},
+	{
+		description: "Unexpected body error",
+		errors: Errors{
+			{
+				Classification: ERR_UNEXPECTED_BODY,
+				Message:        "Unexpected body for GET request",
+			},
+		},
+		expected: errorTestResult{
+			statusCode:  http.StatusBadRequest,
+			contentType: _JSON_CONTENT_TYPE,
+			body:        `[{"classification":"UnexpectedBodyError","message":"Unexpected body for GET request"}]`,
+		},
+	},
+	{
+		description: "Signature error",
+		errors: Errors{
+			{
+				Classification: ERR_SIGNATURE,
+				Message:        "Invalid signature",
+			},
+		},
+		expected: errorTestResult{
+			statusCode:  http.StatusUnauthorized,
+			contentType: _JSON_CONTENT_TYPE,
+			body:        `[{"classification":"SignatureError","message":"Invalid signature"}]`,
+		},
+	},
 	{
 		description: "Requirement error",
 		errors: Errors{
@@ -158,3 +201,25 @@ type (
 		body        string
 	}
 )
+
+func Test_AcceptHeader(t *testing.T) {
+	var post Post
+	for method, header := range map[string]string{"POST": "Accept-Post", "PATCH": "Accept-Patch"} {
+		req, _ := http.NewRequest(method, "/", strings.NewReader(`Hello`))
+		req.Header.Set("Content-Type", "text/plain")
+		errs := Bind(req, &post)
+
+		rw := httptest.NewRecorder()
+		errorResponder(rw, req, errs)
+		assert.EqualValues(t, http.StatusUnsupportedMediaType, rw.Code)
+		assert.EqualValues(t, "application/json, application/xml, application/yaml, application/x-www-form-urlencoded, multipart/form-data", rw.Header().Get(header))
+	}
+
+	// Other errors do not list media types.
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	rw := httptest.NewRecorder()
+	errorResponder(rw, req, Bind(req, &post))
+	assert.EqualValues(t, STATUS_UNPROCESSABLE_ENTITY, rw.Code)
+	assert.Empty(t, rw.Header().Get("Accept-Post"))
+}
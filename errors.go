@@ -22,6 +22,20 @@ const (
 	ERR_INTERGER_TYPE   = "IntegerTypeError"
 	ERR_BOOLEAN_TYPE    = "BooleanTypeError"
 	ERR_FLOAT_TYPE      = "FloatTypeError"
+	ERR_OVERFLOW        = "OverflowError"
+	ERR_UUID            = "UUIDError"
+	ERR_IP              = "IPError"
+	ERR_CIDR            = "CIDRError"
+	ERR_VERSION         = "VersionError"
+	ERR_SIGNATURE       = "SignatureError"
+	ERR_LIMIT           = "LimitError"
+	ERR_UNEXPECTED_BODY = "UnexpectedBodyError"
+	ERR_FIELD_MASK      = "FieldMaskError"
+	ERR_TIME            = "TimeError"
+	ERR_DURATION        = "DurationError"
+
+	// Server errors.
+	ERR_CONFIG = "ConfigError"
 
 	// Validation errors.
 	ERR_REQUIRED       = "RequiredError"
@@ -33,11 +47,41 @@ const (
 	ERR_RANGE          = "RangeError"
 	ERR_EMAIL          = "EmailError"
 	ERR_URL            = "UrlError"
+	ERR_SCHEME         = "SchemeError"
 	ERR_IN             = "InError"
 	ERR_NOT_INT        = "NotInError"
 	ERR_INCLUDE        = "IncludeError"
 	ERR_EXCLUDE        = "ExcludeError"
 	ERR_DEFAULT        = "DefaultError"
+	ERR_SEARCH_QUERY   = "SearchQueryError"
+	ERR_CURSOR         = "CursorError"
+	ERR_MAX_BITS       = "MaxBitsError"
+	ERR_EXPRESSION     = "ExpressionError"
+	ERR_DUPLICATE_KEY  = "DuplicateKeyError"
+	ERR_FILE_SIZE      = "FileSizeError"
+	ERR_FILE_TYPE      = "FileTypeError"
+	ERR_MIN_DURATION   = "MinDurationError"
+	ERR_MAX_DURATION   = "MaxDurationError"
+	ERR_MATCH          = "MatchError"
+	ERR_ALPHA          = "AlphaError"
+	ERR_ALPHA_NUMERIC  = "AlphaNumericError"
+	ERR_ALPHA_UNICODE  = "AlphaUnicodeError"
+	ERR_MIN            = "MinError"
+	ERR_MAX            = "MaxError"
+	ERR_BETWEEN        = "BetweenError"
+	ERR_MAC            = "MACError"
+	ERR_HOSTNAME       = "HostnameError"
+	ERR_FQDN           = "FQDNError"
+	ERR_DNS_LABEL      = "DNSLabelError"
+	ERR_PORT           = "PortError"
+	ERR_HOST_PORT      = "HostPortError"
+	ERR_JSON           = "JsonError"
+	ERR_BASE64         = "Base64Error"
+	ERR_BASE64_URL     = "Base64URLError"
+	ERR_HEXADECIMAL    = "HexadecimalError"
+	ERR_HEX_COLOR      = "HexColorError"
+	ERR_CREDIT_CARD    = "CreditCardError"
+	ERR_PHONE          = "PhoneError"
 )
 
 type (
@@ -66,9 +110,27 @@ type (
 		// an error in the 41st object. The message should help the
 		// end user find and fix the error with their request.
 		Message string `json:"message,omitempty"`
+
+		// For values that could not be converted, ExpectedType is the
+		// type the value should have been convertible to, Value is the
+		// submitted value (omitted for fields marked Sensitive) and
+		// Source is the part of the request the value was read from.
+		ExpectedType string `json:"expectedType,omitempty"`
+		Value        string `json:"value,omitempty"`
+		Source       string `json:"source,omitempty"`
 	}
 )
 
+// Sources of submitted values reported in Error.Source.
+const (
+	SOURCE_QUERY  = "query"
+	SOURCE_FORM   = "form"
+	SOURCE_BODY   = "body"
+	SOURCE_PATH   = "path"
+	SOURCE_HEADER = "header"
+	SOURCE_COOKIE = "cookie"
+)
+
 // Add adds an error associated with the fields indicated
 // by fieldNames, with the given classification and message.
 func (e *Errors) Add(fieldNames []string, classification, message string) {
@@ -0,0 +1,119 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+type (
+	// Report describes how a request was bound, see Explain.
+	Report struct {
+		// Fields holds the fields that were bound or validated, in the
+		// order they were first seen.
+		Fields []FieldReport `json:"fields"`
+		// Errors are all errors of the binding, as returned by Bind.
+		Errors Errors `json:"errors,omitempty"`
+	}
+
+	// FieldReport describes how a field was bound and validated.
+	FieldReport struct {
+		// Field is the path of the field, e.g. "Address.City".
+		Field string `json:"field"`
+		// Source is the part of the request the field was bound from,
+		// or empty if it was not in the request.
+		Source string `json:"source,omitempty"`
+		// Value is the value of the field after binding.
+		Value interface{} `json:"value,omitempty"`
+		// Rules are the validation rules evaluated for the field.
+		Rules []string `json:"rules,omitempty"`
+		// Passed is false if binding or validating the field failed.
+		Passed bool `json:"passed"`
+		// Errors are the errors raised for the field.
+		Errors Errors `json:"errors,omitempty"`
+	}
+)
+
+// Explain binds req into obj like Bind and reports, for each field, where
+// it was bound from, its value, the rules evaluated and whether it passed.
+// It is meant for diagnostics endpoints and tests asserting which rules
+// apply to a model.
+func Explain(req *http.Request, obj interface{}) Report {
+	var report Report
+	index := make(map[string]int)
+	field := func(path string) *FieldReport {
+		i, ok := index[path]
+		if !ok {
+			i = len(report.Fields)
+			index[path] = i
+			report.Fields = append(report.Fields, FieldReport{Field: path, Passed: true})
+		}
+		return &report.Fields[i]
+	}
+
+	b := *binderFrom(req)
+	next, done := b.tracer, false
+	b.tracer = TracerFunc(func(ev TraceEvent) {
+		if next != nil {
+			next.Trace(ev)
+		}
+		if done || ev.Field == "" {
+			return
+		}
+		f := field(ev.Field)
+		switch ev.Stage {
+		case TRACE_BIND, TRACE_REJECT:
+			f.Source = ev.Source
+		case TRACE_VALIDATE:
+			f.Rules = append(f.Rules, ev.Rules...)
+		}
+		if len(ev.Errors) > 0 {
+			f.Passed = false
+			f.Errors = append(f.Errors, ev.Errors...)
+		}
+	})
+
+	report.Errors = b.Bind(req, obj)
+	done = true
+	for i := range report.Fields {
+		if v, ok := fieldByPath(reflect.ValueOf(obj), report.Fields[i].Field); ok {
+			report.Fields[i].Value = v.Interface()
+		}
+	}
+	return report
+}
+
+// fieldByPath returns the field of the struct v at path, e.g.
+// "Address.City". Paths of elements, such as "Tags[0]", are not resolved.
+func fieldByPath(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return v, false
+		}
+		v = v.FieldByName(name)
+		if !v.IsValid() || !v.CanInterface() {
+			return v, false
+		}
+	}
+	return v, true
+}
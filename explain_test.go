@@ -0,0 +1,47 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Explain(t *testing.T) {
+	type signupForm struct {
+		User  string `form:"user" binding:"Required;AlphaDash"`
+		Email string `form:"email" binding:"Email"`
+		Age   int    `form:"age" binding:"Range(18,99)"`
+		Note  string `form:"note"`
+	}
+
+	req, _ := http.NewRequest("GET", "/?user=jane&email=nope&age=x", nil)
+	var form signupForm
+	report := Explain(req, &form)
+	assert.Len(t, report.Errors, 2)
+	assert.EqualValues(t, []FieldReport{
+		{Field: "User", Source: SOURCE_QUERY, Value: "jane", Rules: []string{"Required", "AlphaDash"}, Passed: true},
+		{Field: "Email", Source: SOURCE_QUERY, Value: "nope", Rules: []string{"Email"}, Errors: report.Errors[1:], Passed: false},
+		{Field: "Age", Source: SOURCE_QUERY, Value: 0, Rules: []string{"Range(18,99)"}, Errors: report.Errors[:1], Passed: false},
+	}, report.Fields)
+	assert.EqualValues(t, ERR_INTERGER_TYPE, report.Errors[0].Classification)
+	assert.EqualValues(t, ERR_EMAIL, report.Errors[1].Classification)
+
+	// Later bindings of the request are not recorded.
+	Form(req, &form)
+	assert.Len(t, report.Fields, 3)
+}
@@ -0,0 +1,218 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Expression reports whether the struct obj satisfies a compiled
+	// expression.
+	Expression func(obj interface{}) (bool, error)
+
+	// ExpressionCompiler compiles the expression of a struct tag for the
+	// struct type typ.
+	ExpressionCompiler func(typ reflect.Type, expr string) (Expression, error)
+)
+
+type expressionTag struct {
+	name           string
+	classification string
+	compile        ExpressionCompiler
+}
+
+var (
+	expressionTags = []expressionTag{{"expr", ERR_EXPRESSION, compileComparison}}
+	// compiledExpressions caches an Expression per struct type, field
+	// index and tag, so each expression is only compiled once.
+	compiledExpressions sync.Map
+)
+
+type expressionKey struct {
+	typ   reflect.Type
+	field int
+	tag   string
+}
+
+// AddExpressionTag registers an expression language for struct fields
+// tagged with the given name. The expression is compiled once per struct
+// type and evaluated against the whole struct after the field rules have
+// been checked, so it can express invariants spanning several fields.
+// Failures are reported for the tagged field with the given classification
+// and the expression as message; tag a blank field (_) for failures that
+// should not point to a particular field. Invalid expressions panic on
+// first use.
+func AddExpressionTag(name, classification string, compile ExpressionCompiler) {
+	expressionTags = append(expressionTags, expressionTag{name, classification, compile})
+}
+
+// validateExpressions evaluates the expressions of field i of struct val.
+func validateExpressions(errors Errors, val reflect.Value, i int) Errors {
+	typ := val.Type()
+	field := typ.Field(i)
+	for _, tag := range expressionTags {
+		expr, ok := field.Tag.Lookup(tag.name)
+		if !ok {
+			continue
+		}
+
+		key := expressionKey{typ, i, tag.name}
+		compiled, ok := compiledExpressions.Load(key)
+		if !ok {
+			fn, err := tag.compile(typ, expr)
+			if err != nil {
+				panic(fmt.Sprintf("binding: invalid %s expression of %s.%s: %v", tag.name, typ, field.Name, err))
+			}
+			compiled, _ = compiledExpressions.LoadOrStore(key, fn)
+		}
+
+		var fieldNames []string
+		if field.Name != "_" {
+			fieldNames = []string{field.Name}
+		}
+		valid, err := compiled.(Expression)(val.Interface())
+		if err != nil {
+			errors.Add(fieldNames, tag.classification, err.Error())
+		} else if !valid {
+			errors.Add(fieldNames, tag.classification, expr)
+		}
+	}
+	return errors
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	comparisons = map[string]func(int) bool{
+		"==":     func(c int) bool { return c == 0 },
+		"!=":     func(c int) bool { return c != 0 },
+		"<":      func(c int) bool { return c < 0 },
+		"<=":     func(c int) bool { return c <= 0 },
+		">":      func(c int) bool { return c > 0 },
+		">=":     func(c int) bool { return c >= 0 },
+		"before": func(c int) bool { return c < 0 },
+		"after":  func(c int) bool { return c > 0 },
+	}
+)
+
+// compileComparison compiles the expressions of the built-in expr tag,
+// which compare two sibling fields, e.g. "EndDate after StartDate" or
+// "MaxPrice >= MinPrice". The operators are ==, !=, <, <=, >, >=, and
+// before and after as aliases of < and >. Fields may be numbers, strings
+// or time.Time, or pointers to them. The comparison is skipped when
+// either field has its zero value or is promoted through a nil embedded
+// pointer; use Required to demand a value.
+func compileComparison(typ reflect.Type, expr string) (Expression, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != 3 {
+		return nil, errors.New("expected <field> <operator> <field>")
+	}
+	compare, ok := comparisons[parts[1]]
+	if !ok {
+		return nil, fmt.Errorf("unknown operator %q", parts[1])
+	}
+	var index [2][]int
+	var kinds [2]reflect.Type
+	for i, name := range []string{parts[0], parts[2]} {
+		field, ok := typ.FieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %s", name)
+		}
+		index[i] = field.Index
+		kinds[i] = field.Type
+		if kinds[i].Kind() == reflect.Ptr {
+			kinds[i] = kinds[i].Elem()
+		}
+	}
+	if orderOf(kinds[0]) == 0 || orderOf(kinds[0]) != orderOf(kinds[1]) {
+		return nil, fmt.Errorf("cannot compare %s with %s", kinds[0], kinds[1])
+	}
+
+	return func(obj interface{}) (bool, error) {
+		v := reflect.ValueOf(obj)
+		// Fields promoted through a nil embedded pointer are missing.
+		a, err := v.FieldByIndexErr(index[0])
+		if err != nil {
+			return true, nil
+		}
+		b, err := v.FieldByIndexErr(index[1])
+		if err != nil {
+			return true, nil
+		}
+		a, b = reflect.Indirect(a), reflect.Indirect(b)
+		if !a.IsValid() || !b.IsValid() || a.IsZero() || b.IsZero() {
+			return true, nil
+		}
+		return compare(compareValues(a, b)), nil
+	}, nil
+}
+
+const (
+	orderInt = iota + 1
+	orderUint
+	orderFloat
+	orderString
+	orderTime
+)
+
+// orderOf returns the ordering used to compare values of typ, or 0 if
+// values of typ are not comparable by compileComparison.
+func orderOf(typ reflect.Type) int {
+	if typ == timeType {
+		return orderTime
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return orderInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return orderUint
+	case reflect.Float32, reflect.Float64:
+		return orderFloat
+	case reflect.String:
+		return orderString
+	}
+	return 0
+}
+
+// compareValues returns -1, 0 or 1 as a is less than, equal to or greater
+// than b, which have the same ordering.
+func compareValues(a, b reflect.Value) int {
+	less, greater := false, false
+	switch orderOf(a.Type()) {
+	case orderInt:
+		less, greater = a.Int() < b.Int(), a.Int() > b.Int()
+	case orderUint:
+		less, greater = a.Uint() < b.Uint(), a.Uint() > b.Uint()
+	case orderFloat:
+		less, greater = a.Float() < b.Float(), a.Float() > b.Float()
+	case orderString:
+		less, greater = a.String() < b.String(), a.String() > b.String()
+	case orderTime:
+		ta, tb := a.Interface().(time.Time), b.Interface().(time.Time)
+		less, greater = ta.Before(tb), ta.After(tb)
+	}
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
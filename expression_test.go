@@ -0,0 +1,121 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// compileNonEmpty compiles expressions of the form "A|B", which require
+// at least one of the named string fields to be non-empty.
+func compileNonEmpty(typ reflect.Type, expr string) (Expression, error) {
+	names := strings.Split(expr, "|")
+	for _, name := range names {
+		if _, ok := typ.FieldByName(name); !ok {
+			return nil, errors.New("unknown field " + name)
+		}
+	}
+	return func(obj interface{}) (bool, error) {
+		v := reflect.ValueOf(obj)
+		for _, name := range names {
+			if v.FieldByName(name).String() != "" {
+				return true, nil
+			}
+		}
+		return false, nil
+	}, nil
+}
+
+func Test_ExpressionTag(t *testing.T) {
+	defer func(tags []expressionTag) { expressionTags = tags }(expressionTags)
+	AddExpressionTag("oneof", "OneOfError", compileNonEmpty)
+
+	type contactForm struct {
+		Email string   `form:"email" binding:"Email"`
+		Phone string   `form:"phone"`
+		_     struct{} `oneof:"Email|Phone"`
+	}
+	type brokenForm struct {
+		Email string `form:"email" oneof:"Mail"`
+	}
+
+	req, _ := http.NewRequest("GET", "/?phone=555", nil)
+	var form contactForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+
+	req, _ = http.NewRequest("GET", "/", nil)
+	form = contactForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, "OneOfError", errs[0].Classification)
+	assert.EqualValues(t, "Email|Phone", errs[0].Message)
+	assert.Empty(t, errs[0].FieldNames)
+
+	assert.Panics(t, func() { RawValidate(&brokenForm{}) })
+}
+
+type tripForm struct {
+	StartDate time.Time  `form:"start_date"`
+	EndDate   *time.Time `form:"end_date" expr:"EndDate after StartDate"`
+	MinPrice  int        `form:"min_price"`
+	MaxPrice  int        `form:"max_price" expr:"MaxPrice >= MinPrice"`
+}
+
+func Test_ComparisonExpression(t *testing.T) {
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+
+	errs := RawValidate(&tripForm{StartDate: start, EndDate: &end, MinPrice: 10, MaxPrice: 10})
+	assert.Empty(t, errs)
+	errs = RawValidate(&tripForm{StartDate: start, MaxPrice: 10})
+	assert.Empty(t, errs)
+
+	errs = RawValidate(&tripForm{StartDate: end, EndDate: &start, MinPrice: 20, MaxPrice: 10})
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, ERR_EXPRESSION, errs[0].Classification)
+	assert.EqualValues(t, []string{"EndDate"}, errs[0].FieldNames)
+	assert.EqualValues(t, "EndDate after StartDate", errs[0].Message)
+	assert.EqualValues(t, []string{"MaxPrice"}, errs[1].FieldNames)
+
+	type priceRange struct {
+		MinPrice int
+	}
+	type offerForm struct {
+		*priceRange
+		MaxPrice int
+	}
+	compare, err := compileComparison(reflect.TypeOf(offerForm{}), "MaxPrice >= MinPrice")
+	if assert.NoError(t, err) {
+		ok, err := compare(offerForm{MaxPrice: 10})
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = compare(offerForm{priceRange: &priceRange{MinPrice: 20}, MaxPrice: 10})
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	}
+
+	for _, expr := range []string{"EndDate after", "EndDate around StartDate", "EndDate after Missing", "EndDate > MinPrice"} {
+		_, err := compileComparison(reflect.TypeOf(tripForm{}), expr)
+		assert.Error(t, err, expr)
+	}
+}
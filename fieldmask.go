@@ -0,0 +1,228 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// FieldMaskParam is the query parameter holding a field mask, such as
+// "?fields=name,email", or empty to ignore field masks. Only the fields
+// named in the mask, by their form or JSON name, are bound and validated;
+// other fields keep their values and are not reported by Provided. Fields
+// of nested structs are named with dots, e.g. "address.city".
+var FieldMaskParam = ""
+
+// StrictFieldMask makes submitting a field outside the field mask an
+// ERR_FIELD_MASK error instead of ignoring it, so the mask also lists the
+// fields a request is allowed to change.
+var StrictFieldMask = false
+
+// WithFieldMask sets the query parameter holding a field mask; see
+// FieldMaskParam.
+func WithFieldMask(param string) Option {
+	return func(b *Binder) {
+		b.fieldMaskParam = param
+	}
+}
+
+// WithStrictFieldMask sets whether fields outside the field mask are
+// rejected; see StrictFieldMask.
+func WithStrictFieldMask(strict bool) Option {
+	return func(b *Binder) {
+		b.strictFieldMask = strict
+	}
+}
+
+// fieldMask holds the field mask of the request bound into obj. paths maps
+// the paths of the masked fields to true, and the paths of the structs
+// holding them to false.
+type fieldMask struct {
+	obj   interface{}
+	paths map[string]bool
+	list  []string
+}
+
+// covers reports whether the field at path is masked, itself or as part
+// of a masked struct or collection. Fields of elements, such as
+// "Items[0].Sku", are masked as the field of the collection, "Items.Sku".
+func (m *fieldMask) covers(path string) bool {
+	path = trimIndices(path)
+	if _, ok := m.paths[path]; ok {
+		return true
+	}
+	for i := strings.LastIndexByte(path, '.'); i >= 0; i = strings.LastIndexByte(path, '.') {
+		path = path[:i]
+		if m.paths[path] {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldMask returns the paths of the fields named by the field mask of
+// the request last bound from req, e.g. ["Name", "Address.City"], so that
+// handlers can update only those. It returns nil if req has no field mask,
+// and needs Handler.
+func FieldMask(req *http.Request) []string {
+	m := fieldMaskFrom(req)
+	if m == nil {
+		return nil
+	}
+	return m.list
+}
+
+// trimIndices removes the indices and keys of elements from path, e.g.
+// "Items[0].Sku" becomes "Items.Sku".
+func trimIndices(path string) string {
+	for {
+		i := strings.IndexByte(path, '[')
+		if i < 0 {
+			return path
+		}
+		j := strings.IndexByte(path[i:], ']')
+		if j < 0 {
+			return path
+		}
+		path = path[:i] + path[i+j+1:]
+	}
+}
+
+// fieldMaskFrom returns the field mask of the request last bound from
+// req, or nil.
+func fieldMaskFrom(req *http.Request) *fieldMask {
+	if s := stateOf(req); s != nil {
+		return s.fieldMask
+	}
+	return nil
+}
+
+// fieldMaskOf returns the field mask applying to obj bound from req, if
+// any.
+func fieldMaskOf(req *http.Request, obj interface{}) *fieldMask {
+	m := fieldMaskFrom(req)
+	if m == nil || m.obj != obj {
+		return nil
+	}
+	return m
+}
+
+// withFieldMask returns decode restricted to the fields named by the field
+// mask of req, or decode itself if req has none. Names in the mask that
+// do not match a field of obj are reported as ERR_FIELD_MASK.
+func withFieldMask(req *http.Request, obj interface{}, decode decoder) (decoder, Errors) {
+	b := binderFrom(req)
+	if b.fieldMaskParam == "" {
+		return decode, nil
+	}
+	values, ok := req.URL.Query()[b.fieldMaskParam]
+	if !ok {
+		return decode, nil
+	}
+
+	var errors Errors
+	m := &fieldMask{obj: obj, paths: make(map[string]bool)}
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			path, ok := resolveMaskPath(reflect.TypeOf(obj), name)
+			if !ok {
+				errors.Add([]string{name}, ERR_FIELD_MASK, "Unknown field")
+				continue
+			}
+			if !m.paths[path] {
+				m.list = append(m.list, path)
+			}
+			m.paths[path] = true
+			for i := strings.LastIndexByte(path, '.'); i >= 0; i = strings.LastIndexByte(path, '.') {
+				path = path[:i]
+				if _, ok := m.paths[path]; !ok {
+					m.paths[path] = false
+				}
+			}
+		}
+	}
+	if len(errors) > 0 {
+		return nil, errors
+	}
+	if s := stateOf(req); s != nil {
+		s.fieldMask = m
+	}
+
+	return func(req *http.Request, obj interface{}) Errors {
+		target := reflect.ValueOf(obj).Elem()
+		patch := reflect.New(target.Type())
+		errors := decode(req, patch.Interface())
+
+		present := newFieldPaths()
+		rejected := ""
+		for _, path := range Provided(req) {
+			if m.covers(path) {
+				present.add(path)
+			} else if b.strictFieldMask && (rejected == "" || !strings.HasPrefix(path, rejected+".")) {
+				// Fields of a rejected struct are not reported again.
+				errors.Add([]string{path}, ERR_FIELD_MASK, "Field not in field mask")
+				rejected = path
+			}
+		}
+		for _, path := range m.list {
+			copyPath(target, patch.Elem(), strings.Split(path, "."))
+		}
+		setProvided(req, obj, present)
+		return errors
+	}, nil
+}
+
+// resolveMaskPath returns the path of the field of the struct typ named
+// by name, which is made of form names, JSON names or field names joined
+// with dots.
+func resolveMaskPath(typ reflect.Type, name string) (string, bool) {
+	var path []string
+	for _, part := range strings.Split(name, ".") {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			return "", false
+		}
+		field, ok := maskField(typ, part)
+		if !ok {
+			return "", false
+		}
+		path = append(path, field.Name)
+		typ = field.Type
+	}
+	return strings.Join(path, "."), true
+}
+
+func maskField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" || field.Tag.Get("form") == "-" {
+			continue
+		}
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if strings.EqualFold(name, FormName(field)) || strings.EqualFold(name, jsonName) ||
+			strings.EqualFold(name, field.Name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FieldMask(t *testing.T) {
+	b := New(WithFieldMask("fields"))
+	newRequest := func(target, body string) *http.Request {
+		req, _ := http.NewRequest("PATCH", target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return withState(req)
+	}
+	body := `{"count":3,"note":"n","limits":{"max":2}}`
+
+	// Fields outside the mask are neither bound nor validated.
+	req := newRequest("/?fields=count,limits.max", body)
+	form := stockForm{Note: "kept"}
+	assert.Empty(t, b.Bind(req, &form))
+	assert.EqualValues(t, 3, form.Count)
+	assert.EqualValues(t, "kept", form.Note)
+	assert.EqualValues(t, 2, form.Limits.Max)
+	assert.EqualValues(t, []string{"Count", "Limits", "Limits.Max"}, Provided(req))
+	assert.EqualValues(t, []string{"Count", "Limits.Max"}, FieldMask(req))
+
+	// Masked fields are validated, and cleared if they are not submitted.
+	req = newRequest("/?fields=Note,enabled", body)
+	form = stockForm{Enabled: true}
+	errs := b.Bind(req, &form)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, []string{"Enabled"}, errs[0].FieldNames)
+	assert.EqualValues(t, "n", form.Note)
+	assert.EqualValues(t, 0, form.Count)
+
+	// Masking a struct covers its fields.
+	req = newRequest("/?fields=limits", body)
+	form = stockForm{}
+	assert.Empty(t, b.Bind(req, &form))
+	assert.EqualValues(t, 2, form.Limits.Max)
+	assert.EqualValues(t, []string{"Limits", "Limits.Max"}, Provided(req))
+
+	req = newRequest("/?fields=count,owner", body)
+	errs = b.Bind(req, &form)
+	assert.EqualValues(t, ERR_FIELD_MASK, errs[0].Classification)
+	assert.EqualValues(t, []string{"owner"}, errs[0].FieldNames)
+	assert.EqualValues(t, "Unknown field", errs[0].Message)
+
+	// Without a mask, all fields are bound.
+	req = newRequest("/", body)
+	form = stockForm{}
+	errs = b.Bind(req, &form)
+	assert.Len(t, errs, 1)
+	assert.Nil(t, FieldMask(req))
+	assert.EqualValues(t, "n", form.Note)
+
+	// Strict masks reject fields outside of them.
+	strict := New(WithFieldMask("fields"), WithStrictFieldMask(true))
+	req = newRequest("/?fields=count,note", body)
+	form = stockForm{}
+	errs = strict.Bind(req, &form)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, ERR_FIELD_MASK, errs[0].Classification)
+	assert.EqualValues(t, []string{"Limits"}, errs[0].FieldNames)
+	assert.EqualValues(t, "Field not in field mask", errs[0].Message)
+}
+
+func Test_FieldMaskSlice(t *testing.T) {
+	type item struct {
+		Sku string `json:"sku" binding:"Required"`
+	}
+	type orderForm struct {
+		Name  string `json:"name" binding:"Required"`
+		Items []item `json:"items"`
+	}
+
+	b := New(WithFieldMask("fields"))
+	body := `{"name":"n","items":[{"sku":"a"},{"sku":""}]}`
+	for _, target := range []string{"/?fields=items", "/?fields=name,items", "/"} {
+		req, _ := http.NewRequest("PATCH", target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		var form orderForm
+		errs := b.Bind(req, &form)
+		if assert.Len(t, errs, 1, target) {
+			assert.EqualValues(t, []string{"Items[1].Sku"}, errs[0].FieldNames)
+			assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+		}
+	}
+
+	// Elements of collections outside the mask are not validated.
+	req, _ := http.NewRequest("PATCH", "/?fields=name", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	var form orderForm
+	assert.Empty(t, b.Bind(req, &form))
+	assert.Empty(t, form.Items)
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	ruleParamCheckers["MaxFileSize"] = func(size string) error {
+		_, err := parseByteSize(size)
+		return err
+	}
+}
+
+// uploadedFiles returns the files held by a *multipart.FileHeader or
+// []*multipart.FileHeader field value.
+func uploadedFiles(v interface{}) []*multipart.FileHeader {
+	switch v := v.(type) {
+	case *multipart.FileHeader:
+		if v != nil {
+			return []*multipart.FileHeader{v}
+		}
+	case []*multipart.FileHeader:
+		return v
+	}
+	return nil
+}
+
+// parseByteSize parses sizes such as "512", "64KB" or "5MB", with binary
+// multiples.
+func parseByteSize(s string) (int64, error) {
+	num := strings.ToUpper(strings.TrimSpace(s))
+	shift := uint(0)
+	for i, unit := range []string{"KB", "MB", "GB"} {
+		if strings.HasSuffix(num, unit) {
+			num, shift = num[:len(num)-2], uint(10*(i+1))
+			break
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(num), 10, 64)
+	if err != nil || n < 0 || n > math.MaxInt64>>shift {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	return n << shift, nil
+}
+
+// isFileType reports whether the content of the uploaded file is of one of
+// types, which may end in a wildcard subtype like "image/*". The type is
+// sniffed from the content with http.DetectContentType rather than taken
+// from the Content-Type sent by the client, so only the types it
+// recognizes can be matched, such as image/png, application/pdf or
+// application/zip; text files are text/plain.
+func isFileType(file *multipart.FileHeader, types []string) bool {
+	f, err := file.Open()
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if err != nil {
+		return false
+	}
+	for _, typ := range types {
+		typ = strings.ToLower(strings.TrimSpace(typ))
+		if typ == mediaType || strings.HasSuffix(typ, "/*") && strings.HasPrefix(mediaType, typ[:len(typ)-1]) {
+			return true
+		}
+	}
+	return false
+}
@@ -298,3 +298,161 @@ func Test_Default(t *testing.T) {
 
 	m.ServeHTTP(resp, req)
 }
+
+func Test_EmptyAsNil(t *testing.T) {
+	type profileForm struct {
+		Nickname *string `form:"nickname,omitempty"`
+		Bio      *string `form:"bio"`
+		Age      *int    `form:"age"`
+	}
+
+	req, _ := http.NewRequest("POST", "/", strings.NewReader("nickname=&bio=&age="))
+	req.Header.Set("Content-Type", formContentType)
+	req = withState(req)
+	var form profileForm
+	errs := Form(req, &form)
+	assert.Len(t, errs, 0)
+	assert.Nil(t, form.Nickname)
+	assert.NotNil(t, form.Bio)
+	assert.EqualValues(t, "", *form.Bio)
+	assert.NotNil(t, form.Age)
+	assert.EqualValues(t, []string{"Bio", "Age"}, Provided(req))
+
+	EmptyAsNil = true
+	defer func() { EmptyAsNil = false }()
+	req, _ = http.NewRequest("POST", "/", strings.NewReader("nickname=nick&bio=&age="))
+	req.Header.Set("Content-Type", formContentType)
+	req = withState(req)
+	form = profileForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 0)
+	assert.EqualValues(t, "nick", *form.Nickname)
+	assert.Nil(t, form.Bio)
+	assert.Nil(t, form.Age)
+	assert.EqualValues(t, []string{"Nickname"}, Provided(req))
+}
+
+func Test_FormNestedKeys(t *testing.T) {
+	type address struct {
+		City string `form:"city" binding:"Required"`
+		Zip  string `form:"zip"`
+	}
+	type author struct {
+		Name    string  `form:"name" binding:"Required"`
+		Address address `form:"address"`
+	}
+	type postForm struct {
+		Title  string `form:"title"`
+		Author author `form:"author"`
+		Editor author `form:"editor"`
+	}
+
+	req, _ := http.NewRequest("POST", "/?editor.address.city=Paris", strings.NewReader(
+		"title=Hello&author.name=Alice&author[address][city]=Berlin&author[address].zip=10115&editor[name]=Bob"))
+	req.Header.Set("Content-Type", formContentType)
+	req = withState(req)
+	var form postForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, postForm{
+		Title:  "Hello",
+		Author: author{Name: "Alice", Address: address{City: "Berlin", Zip: "10115"}},
+		Editor: author{Name: "Bob", Address: address{City: "Paris"}},
+	}, form)
+	assert.EqualValues(t, []string{"Title", "Author", "Author.Name", "Author.Address", "Author.Address.City", "Author.Address.Zip",
+		"Editor", "Editor.Name", "Editor.Address", "Editor.Address.City"}, Provided(req))
+
+	// Flat keys still bind nested fields, unless a nested key is given.
+	req, _ = http.NewRequest("POST", "/", strings.NewReader("name=Carol&city=Rome&editor.city=Milan"))
+	req.Header.Set("Content-Type", formContentType)
+	form = postForm{}
+	errs = Form(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, author{Name: "Carol", Address: address{City: "Rome"}}, form.Author)
+	assert.EqualValues(t, author{Name: "Carol", Address: address{City: "Milan"}}, form.Editor)
+}
+
+func Test_FormIndexedKeys(t *testing.T) {
+	type item struct {
+		SKU string `form:"sku" binding:"Required;AlphaDash"`
+		Qty int    `form:"qty" binding:"Range(1,99)"`
+	}
+	type orderForm struct {
+		Customer string  `form:"customer"`
+		Items    []item  `form:"items"`
+		Extras   []*item `form:"extras"`
+	}
+
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(
+		"customer=Alice&items[1].sku=B-2&items[0][sku]=A-1&items[0][qty]=3&items[1].qty=1&items[10].sku=C-3&items[10].qty=2&extras[0].sku=X&extras[0].qty=1"))
+	req.Header.Set("Content-Type", formContentType)
+	req = withState(req)
+	var form orderForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, []item{{"A-1", 3}, {"B-2", 1}, {"C-3", 2}}, form.Items)
+	assert.EqualValues(t, []*item{{"X", 1}}, form.Extras)
+	assert.EqualValues(t, []string{"Customer", "Items", "Extras"}, Provided(req))
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader("items[0].sku=A-1&items[0].qty=3&items[1].qty=100&items[2].sku=C&items[2].qty=x"))
+	req.Header.Set("Content-Type", formContentType)
+	form = orderForm{}
+	errs = Form(req, &form)
+	if assert.Len(t, errs, 3) {
+		assert.EqualValues(t, []string{"items[2].qty"}, errs[0].FieldNames)
+		assert.EqualValues(t, ERR_INTERGER_TYPE, errs[0].Classification)
+		assert.EqualValues(t, []string{"Items[1].SKU"}, errs[1].FieldNames)
+		assert.EqualValues(t, ERR_REQUIRED, errs[1].Classification)
+		assert.EqualValues(t, []string{"Items[1].Qty"}, errs[2].FieldNames)
+		assert.EqualValues(t, ERR_RANGE, errs[2].Classification)
+	}
+}
+
+func Test_FormMapKeys(t *testing.T) {
+	type address struct {
+		City string `form:"city"`
+	}
+	type listingForm struct {
+		Title   string              `form:"title"`
+		Meta    map[string]string   `form:"meta"`
+		Labels  map[string][]string `form:"labels"`
+		Address address             `form:"address"`
+		Rest    map[string][]string `form:"*"`
+	}
+
+	req, _ := http.NewRequest("POST", "/?utm_source=mail", strings.NewReader(
+		"title=Chair&meta[color]=red&meta[size]=L&labels[tag]=a&labels[tag]=b&address.city=Oslo&city=Bergen&ref=home"))
+	req.Header.Set("Content-Type", formContentType)
+	req = withState(req)
+	var form listingForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, map[string]string{"color": "red", "size": "L"}, form.Meta)
+	assert.EqualValues(t, map[string][]string{"tag": {"a", "b"}}, form.Labels)
+	assert.EqualValues(t, "Oslo", form.Address.City)
+	assert.EqualValues(t, map[string][]string{"utm_source": {"mail"}, "ref": {"home"}}, form.Rest)
+	assert.EqualValues(t, []string{"Title", "Meta", "Labels", "Address", "Address.City", "Rest"}, Provided(req))
+
+	// Keys under a nested struct are not matched against later fields.
+	type shippingForm struct {
+		Address address             `form:"address"`
+		Zip     string              `form:"zip"`
+		Rest    map[string][]string `form:"*"`
+	}
+	req, _ = http.NewRequest("POST", "/", strings.NewReader("address.zip=0150&zip=5003"))
+	req.Header.Set("Content-Type", formContentType)
+	var shipping shippingForm
+	assert.Empty(t, Form(req, &shipping))
+	assert.EqualValues(t, "5003", shipping.Zip)
+	assert.EqualValues(t, map[string][]string{"address.zip": {"0150"}}, shipping.Rest)
+
+	type searchQuery struct {
+		Q       string            `query:"q"`
+		Filters map[string]string `query:"*"`
+	}
+	req, _ = http.NewRequest("GET", "/?q=go&lang=en&sort=stars", nil)
+	var query searchQuery
+	errs = Query(req, &query)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, searchQuery{Q: "go", Filters: map[string]string{"lang": "en", "sort": "stars"}}, query)
+}
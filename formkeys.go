@@ -0,0 +1,270 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"mime/multipart"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// nestedForm returns the form of the fields of a nested struct bound
+// under name, in which keys such as "author.name" and "author[name]" are
+// available as "name". If flat is set, the other keys of form remain
+// available too, for compatibility, but nested keys take precedence over
+// them. sourceOf is adapted to the nested keys.
+func nestedForm(name string, form map[string][]string, formfile map[string][]*multipart.FileHeader,
+	sourceOf func(string) string, flat bool) (map[string][]string, map[string][]*multipart.FileHeader, func(string) string) {
+
+	if name == "" {
+		return form, formfile, sourceOf
+	}
+	outer := map[string]string{}
+	for key := range form {
+		if inner, ok := nestedKey(name, key); ok {
+			outer[inner] = key
+		}
+	}
+	for key := range formfile {
+		if inner, ok := nestedKey(name, key); ok {
+			outer[inner] = key
+		}
+	}
+	if len(outer) == 0 && flat {
+		return form, formfile, sourceOf
+	}
+
+	nestedValues := make(map[string][]string, len(outer))
+	var nestedFiles map[string][]*multipart.FileHeader
+	if formfile != nil {
+		nestedFiles = make(map[string][]*multipart.FileHeader, len(outer))
+	}
+	if flat {
+		for key, values := range form {
+			nestedValues[key] = values
+		}
+		for key, files := range formfile {
+			nestedFiles[key] = files
+		}
+	}
+	for inner, key := range outer {
+		if values, ok := form[key]; ok {
+			nestedValues[inner] = values
+			if nestedFiles != nil {
+				delete(nestedFiles, inner)
+			}
+		} else {
+			nestedFiles[inner] = formfile[key]
+			delete(nestedValues, inner)
+		}
+	}
+	nestedSource := sourceOf
+	if sourceOf != nil {
+		nestedSource = func(key string) string {
+			if outerKey, ok := outer[key]; ok {
+				key = outerKey
+			}
+			return sourceOf(key)
+		}
+	}
+	return nestedValues, nestedFiles, nestedSource
+}
+
+// nestedKey returns the key of a nested struct field bound under name
+// for key, e.g. "city" for "address.city" or "address[city]", and
+// "geo[lat]" for "address[geo][lat]".
+func nestedKey(name, key string) (string, bool) {
+	if len(key) <= len(name)+1 || !strings.HasPrefix(key, name) {
+		return "", false
+	}
+	rest := key[len(name):]
+	switch rest[0] {
+	case '.':
+		return rest[1:], true
+	case '[':
+		end := strings.IndexByte(rest, ']')
+		if end < 2 {
+			return "", false
+		}
+		return rest[1:end] + rest[end+1:], true
+	}
+	return "", false
+}
+
+// formIndices returns the indices of the elements of a slice of structs
+// bound under name from keys such as "items[0].sku" or "items[0][sku]",
+// in ascending order.
+func formIndices(name string, form map[string][]string, formfile map[string][]*multipart.FileHeader) []string {
+	seen := map[string]int{}
+	add := func(key string) {
+		if !strings.HasPrefix(key, name+"[") {
+			return
+		}
+		rest := key[len(name)+1:]
+		end := strings.IndexByte(rest, ']')
+		if end < 1 || end+1 == len(rest) {
+			return
+		}
+		if i, err := strconv.Atoi(rest[:end]); err == nil && i >= 0 {
+			seen[rest[:end]] = i
+		}
+	}
+	for key := range form {
+		add(key)
+	}
+	for key := range formfile {
+		add(key)
+	}
+
+	indices := make([]string, 0, len(seen))
+	for index := range seen {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return seen[indices[i]] < seen[indices[j]]
+	})
+	return indices
+}
+
+// structSlice reports whether fields of type typ are slices of nested
+// structs, or of pointers to them.
+func structSlice(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Slice {
+		return false
+	}
+	elem := typ.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return isNestedStruct(elem)
+}
+
+// mapFormSlice binds the elements of the slice of structs field from
+// keys such as "items[0].sku" under name. Elements are bound in ascending
+// index order, without gaps, and replace any elements field held before.
+// The fields present in them are recorded in present, the paths of the
+// fields of field, as those of its elements, e.g. "Items[0].Sku".
+// It reports false if there are no such keys.
+func mapFormSlice(field reflect.Value, name string, form map[string][]string, formfile map[string][]*multipart.FileHeader,
+	tag string, sourceOf func(string) string, present fieldPaths, b *Binder, errors Errors) (Errors, bool) {
+
+	indices := formIndices(name, form, formfile)
+	if len(indices) == 0 {
+		return errors, false
+	}
+	slice := reflect.MakeSlice(field.Type(), len(indices), len(indices))
+	for i, index := range indices {
+		elem := slice.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(elem.Type().Elem()))
+			elem = elem.Elem()
+		}
+		path := name + "[" + index + "]"
+		elemValues, elemFiles, elemSource := nestedForm(path, form, formfile, sourceOf, false)
+		n := len(errors)
+		errors = mapForm(elem, elemValues, elemFiles, tag, elemSource, present.index(i), b, errors)
+		prefixFieldNames(errors[n:], path)
+	}
+	field.Set(slice)
+	return errors, true
+}
+
+// formRest is the form name of the field collecting the form keys not
+// bound to any other field of its struct, as in `form:"*"`.
+const formRest = "*"
+
+var stringSliceType = reflect.TypeOf([]string(nil))
+
+// isFormMap reports whether fields of type typ are maps bound from keyed
+// form keys such as "meta[color]": map[string]string or
+// map[string][]string.
+func isFormMap(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String &&
+		(typ.Elem().Kind() == reflect.String || typ.Elem() == stringSliceType)
+}
+
+// setFormMap adds the values of the keys for which key returns a map key
+// to the map field, allocating it if needed. It reports whether there
+// were any.
+func setFormMap(field reflect.Value, form map[string][]string, key func(string) (string, bool)) bool {
+	typ := field.Type()
+	found := false
+	for formKey, values := range form {
+		k, ok := key(formKey)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(typ))
+		}
+		var v reflect.Value
+		if typ.Elem() == stringSliceType {
+			v = reflect.ValueOf(values)
+		} else {
+			v = reflect.ValueOf(values[0])
+		}
+		field.SetMapIndex(reflect.ValueOf(k).Convert(typ.Key()), v.Convert(typ.Elem()))
+		found = true
+	}
+	return found
+}
+
+// mapKey returns the map key for form keys such as "meta[color]" of a
+// map bound under name.
+func mapKey(name, key string) (string, bool) {
+	if len(key) < len(name)+3 || !strings.HasPrefix(key, name+"[") || key[len(key)-1] != ']' {
+		return "", false
+	}
+	k := key[len(name)+1 : len(key)-1]
+	return k, !strings.ContainsAny(k, "[]")
+}
+
+// formKeyBound reports whether the form key is bound to a field of the
+// struct type typ, whose fields are named by their tag called tag.
+func formKeyBound(typ reflect.Type, tag, key string) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Type.Kind() == reflect.Ptr && field.Anonymous {
+			if formKeyBound(field.Type, tag, key) {
+				return true
+			}
+			continue
+		}
+		name := inputName(field, tag)
+		switch {
+		case name == "-" || name == formRest:
+		case isNestedStruct(field.Type) && !hasFormOption(field.Tag.Get("form"), "json"):
+			inner := key
+			if nested, ok := nestedKey(name, key); ok {
+				inner = nested
+			}
+			if formKeyBound(field.Type, tag, inner) {
+				return true
+			}
+		case structSlice(field.Type) || isFormMap(field.Type):
+			if strings.HasPrefix(key, name+"[") {
+				return true
+			}
+		case key == name:
+			return true
+		}
+	}
+	return false
+}
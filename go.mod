@@ -1,10 +1,17 @@
 module gitea.com/go-chi/binding
 
-go 1.13
+go 1.18
 
 require (
 	github.com/go-chi/chi/v5 v5.0.4
 	github.com/goccy/go-json v0.4.11
+	github.com/google/uuid v1.3.0
 	github.com/stretchr/testify v1.3.0
 	github.com/unknwon/com v0.0.0-20190804042917-757f69c95f3e
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 )
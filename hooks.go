@@ -0,0 +1,167 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+type (
+	// BeforeBinder is implemented by structs that need to prepare
+	// themselves, e.g. fill in defaults, before the request is decoded.
+	// Decoding is skipped if BeforeBind returns errors.
+	BeforeBinder interface {
+		BeforeBind(*http.Request, Errors) Errors
+	}
+
+	// AfterBinder is implemented by structs that need to act on a
+	// successfully bound request, e.g. compute derived fields or record
+	// an audit trail. AfterBind is only called when decoding and
+	// validation reported no errors.
+	AfterBinder interface {
+		AfterBind(*http.Request, Errors) Errors
+	}
+
+	// Normalizer is implemented by types that canonicalize themselves,
+	// e.g. lowercase an email address, after binding. Normalize is called
+	// before validation, so the canonical form is what gets validated.
+	Normalizer interface {
+		Normalize()
+	}
+
+	// Hook is a lifecycle hook registered for all bound objects.
+	Hook func(req *http.Request, obj interface{}, errs Errors) Errors
+)
+
+var beforeBindHooks, afterBindHooks []Hook
+
+// AddBeforeBind registers a hook that runs before every request is decoded,
+// ahead of the BeforeBind method of the bound object.
+func AddBeforeBind(hook Hook) {
+	beforeBindHooks = append(beforeBindHooks, hook)
+}
+
+// AddAfterBind registers a hook that runs after every successful binding,
+// following the AfterBind method of the bound object.
+func AddAfterBind(hook Hook) {
+	afterBindHooks = append(afterBindHooks, hook)
+}
+
+// beforeBind runs the hooks that precede decoding of obj.
+func beforeBind(req *http.Request, obj interface{}) Errors {
+	var errs Errors
+	for _, hook := range beforeBindHooks {
+		errs = hook(req, obj, errs)
+	}
+	if binder, ok := obj.(BeforeBinder); ok {
+		errs = binder.BeforeBind(req, errs)
+	}
+	return errs
+}
+
+// finishBind validates the decoded obj and, if no errors occurred so far,
+// runs the hooks that follow a successful binding.
+func finishBind(req *http.Request, obj interface{}, errs Errors) Errors {
+	normalize(reflect.ValueOf(obj))
+	errs = append(errs, Validate(req, obj)...)
+	if len(errs) > 0 {
+		return errs
+	}
+	if binder, ok := obj.(AfterBinder); ok {
+		errs = binder.AfterBind(req, errs)
+	}
+	for _, hook := range afterBindHooks {
+		errs = hook(req, obj, errs)
+	}
+	return errs
+}
+
+// normalize calls Normalize on v and every exported field, element and
+// pointee reachable from it, innermost values first. Values of types that
+// cannot hold a Normalizer are not walked.
+func normalize(v reflect.Value) {
+	if !v.IsValid() || v.Kind() != reflect.Interface && !holdsNormalizer(v.Type()) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		normalize(v.Elem())
+		return
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				normalize(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			normalize(v.Index(i))
+		}
+	case reflect.Map:
+		// Map values are not addressable and cannot be normalized in place.
+		return
+	}
+
+	if v.CanAddr() && v.Addr().CanInterface() {
+		if n, ok := v.Addr().Interface().(Normalizer); ok {
+			n.Normalize()
+		}
+	}
+}
+
+var normalizerType = reflect.TypeOf((*Normalizer)(nil)).Elem()
+
+// normalizerTypes caches holdsNormalizer by type.
+var normalizerTypes sync.Map
+
+// holdsNormalizer reports whether normalize may find a Normalizer in
+// values of type typ.
+func holdsNormalizer(typ reflect.Type) bool {
+	if v, ok := normalizerTypes.Load(typ); ok {
+		return v.(bool)
+	}
+	holds := typeHoldsNormalizer(typ, map[reflect.Type]bool{})
+	normalizerTypes.Store(typ, holds)
+	return holds
+}
+
+func typeHoldsNormalizer(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	if reflect.PtrTo(typ).Implements(normalizerType) {
+		return true
+	}
+	if seen[typ] {
+		return false
+	}
+	seen[typ] = true
+	switch typ.Kind() {
+	case reflect.Interface:
+		// The dynamic value is only known when walking.
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return typeHoldsNormalizer(typ.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if field := typ.Field(i); field.PkgPath == "" && typeHoldsNormalizer(field.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hookedForm struct {
+	Title string `form:"title" binding:"Required"`
+	Lang  string `form:"lang"`
+	Slug  string `form:"-"`
+	calls []string
+}
+
+func (f *hookedForm) BeforeBind(req *http.Request, errs Errors) Errors {
+	f.calls = append(f.calls, "before")
+	f.Lang = "en"
+	if req.Header.Get("X-Reject") != "" {
+		errs.Add([]string{}, "RejectedError", "Rejected")
+	}
+	return errs
+}
+
+func (f *hookedForm) AfterBind(req *http.Request, errs Errors) Errors {
+	f.calls = append(f.calls, "after")
+	f.Slug = strings.ToLower(strings.ReplaceAll(f.Title, " ", "-"))
+	return errs
+}
+
+func Test_BindHooks(t *testing.T) {
+	AddBeforeBind(func(req *http.Request, obj interface{}, errs Errors) Errors {
+		if f, ok := obj.(*hookedForm); ok {
+			f.calls = append(f.calls, "global before")
+		}
+		return errs
+	})
+	AddAfterBind(func(req *http.Request, obj interface{}, errs Errors) Errors {
+		if f, ok := obj.(*hookedForm); ok {
+			f.calls = append(f.calls, "global after")
+		}
+		return errs
+	})
+	defer func() {
+		beforeBindHooks, afterBindHooks = nil, nil
+	}()
+
+	req, _ := http.NewRequest("GET", "/?title=Hello+World", nil)
+	var form hookedForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "en", form.Lang)
+	assert.EqualValues(t, "hello-world", form.Slug)
+	assert.EqualValues(t, []string{"global before", "before", "after", "global after"}, form.calls)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"Lang":"de"}`))
+	req.Header.Set("Content-Type", "application/json")
+	form = hookedForm{}
+	errs = Bind(req, &form)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+	assert.EqualValues(t, "de", form.Lang)
+	assert.Empty(t, form.Slug)
+	assert.EqualValues(t, []string{"global before", "before"}, form.calls)
+
+	req, _ = http.NewRequest("GET", "/?title=Hello", nil)
+	req.Header.Set("X-Reject", "1")
+	form = hookedForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, "RejectedError", errs[0].Classification)
+	assert.Empty(t, form.Title)
+}
+
+type phoneNumber string
+
+func (p *phoneNumber) Normalize() {
+	*p = phoneNumber(strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' || r == '+' {
+			return r
+		}
+		return -1
+	}, string(*p)))
+}
+
+type contact struct {
+	Email  string        `form:"email" binding:"Required;Email"`
+	Phone  phoneNumber   `form:"phone" binding:"MaxSize(12)"`
+	Others []phoneNumber `form:"others"`
+	Backup *phoneNumber  `form:"backup"`
+}
+
+func (c *contact) Normalize() {
+	c.Email = strings.ToLower(strings.TrimSpace(c.Email))
+}
+
+func Test_Normalizer(t *testing.T) {
+	query := "email=+Alice@Example.COM+&phone=%2B1+(555)+010-0199&others=555.0100&backup=(0)+123"
+	req, _ := http.NewRequest("GET", "/?"+query, nil)
+	var form contact
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "alice@example.com", form.Email)
+	assert.EqualValues(t, "+15550100199", form.Phone)
+	assert.EqualValues(t, []phoneNumber{"5550100"}, form.Others)
+	assert.EqualValues(t, "0123", *form.Backup)
+
+	type node struct {
+		Children []node
+		Contact  *contact
+	}
+	assert.True(t, holdsNormalizer(reflect.TypeOf(node{})))
+	assert.True(t, holdsNormalizer(reflect.TypeOf([]phoneNumber{})))
+	assert.False(t, holdsNormalizer(reflect.TypeOf(&Post{})))
+	assert.False(t, holdsNormalizer(reflect.TypeOf(map[string]contact{})))
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// isDNSLabel reports whether s is a DNS label as allowed in host names by
+// RFC 1123: 1 to 63 letters, digits and hyphens, not starting or ending
+// with a hyphen.
+func isDNSLabel(s string) bool {
+	if len(s) == 0 || len(s) > 63 || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// isHostname reports whether v is a host name as defined by RFC 1123,
+// e.g. "localhost" or "www.example.com", of at most 253 characters.
+func isHostname(v interface{}) bool {
+	s := ruleString(v)
+	if len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if !isDNSLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// isFQDN reports whether v is a fully qualified domain name, a host name
+// of at least two labels, e.g. "www.example.com" or "example.com.". The
+// trailing dot of the root is optional, and the top-level domain must not
+// be numeric, so IPv4 addresses are not mistaken for domain names.
+func isFQDN(v interface{}) bool {
+	s := strings.TrimSuffix(ruleString(v), ".")
+	i := strings.LastIndexByte(s, '.')
+	if i < 0 || !isHostname(s) {
+		return false
+	}
+	return strings.Trim(s[i+1:], "0123456789") != ""
+}
+
+// isPort reports whether v, a string or an integer, is a TCP or UDP port
+// number from 1 to 65535.
+func isPort(v interface{}) bool {
+	s := ruleString(v)
+	if len(s) == 0 || strings.Trim(s, "0123456789") != "" {
+		return false
+	}
+	port, err := strconv.Atoi(s)
+	return err == nil && port >= 1 && port <= 65535
+}
+
+// isHostPort reports whether v is a host and port such as "example.com:443",
+// "10.0.0.1:8080" or "[2001:db8::1]:53". The host is a host name or an IP
+// address; see isHostname.
+func isHostPort(v interface{}) bool {
+	host, port, err := net.SplitHostPort(ruleString(v))
+	if err != nil || !isPort(port) {
+		return false
+	}
+	return net.ParseIP(host) != nil || isHostname(host)
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net"
+	"strings"
+)
+
+func init() {
+	AddConverter(net.IP{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return net.IP(nil), nil
+		}
+		ip := net.ParseIP(val)
+		if ip == nil {
+			return nil, Error{Classification: ERR_IP, Message: "Value could not be parsed as IP address"}
+		}
+		return ip, nil
+	})
+}
+
+// isIP reports whether v, a string, net.IP or netip.Addr, is an IP address
+// of the given version: 4 for dotted IPv4 addresses, 6 for IPv6 addresses,
+// including IPv4-mapped ones, or 0 for either.
+func isIP(v interface{}, version int) bool {
+	s := ruleString(v)
+	if net.ParseIP(s) == nil {
+		return false
+	}
+	switch version {
+	case 4:
+		return !strings.Contains(s, ":")
+	case 6:
+		return strings.Contains(s, ":")
+	}
+	return true
+}
+
+// isCIDR reports whether v, a string or netip.Prefix, is an IP address
+// prefix in CIDR notation, e.g. "192.168.0.0/16" or "2001:db8::/32".
+func isCIDR(v interface{}) bool {
+	_, _, err := net.ParseCIDR(ruleString(v))
+	return err == nil
+}
+
+// isMAC reports whether v, a string or net.HardwareAddr, is a MAC address
+// in one of the notations of net.ParseMAC, e.g. "00:00:5e:00:53:01",
+// "00-00-5E-00-53-01" or "0000.5e00.5301".
+func isMAC(v interface{}) bool {
+	_, err := net.ParseMAC(ruleString(v))
+	return err == nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hostForm struct {
+	Address net.IP   `form:"address" json:"address" binding:"Required;IPv4"`
+	Gateway *net.IP  `form:"gateway" json:"gateway"`
+	DNS     []net.IP `form:"dns" json:"dns"`
+}
+
+func Test_NetIPBinding(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/?address=10.0.0.2&gateway=10.0.0.1&dns=1.1.1.1&dns=2606:4700::1111", nil)
+	var form hostForm
+	assert.Empty(t, Form(req, &form))
+	assert.EqualValues(t, "10.0.0.2", form.Address.String())
+	if assert.NotNil(t, form.Gateway) {
+		assert.EqualValues(t, "10.0.0.1", form.Gateway.String())
+	}
+	if assert.Len(t, form.DNS, 2) {
+		assert.EqualValues(t, "2606:4700::1111", form.DNS[1].String())
+	}
+
+	req, _ = http.NewRequest("GET", "/?address=10.0.0.256", nil)
+	form = hostForm{}
+	errs := Form(req, &form)
+	if assert.NotEmpty(t, errs) {
+		assert.EqualValues(t, ERR_IP, errs[0].Classification)
+		assert.EqualValues(t, "net.IP", errs[0].ExpectedType)
+	}
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"address":"2001:db8::1"}`))
+	form = hostForm{}
+	errs = JSON(req, &form)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, []string{"Address"}, errs[0].FieldNames)
+		assert.EqualValues(t, "IPv4", errs[0].Message)
+	}
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"address":"nope"}`))
+	errs = JSON(req, &form)
+	if assert.NotEmpty(t, errs) {
+		assert.EqualValues(t, []string{"Address"}, errs[0].FieldNames)
+		assert.EqualValues(t, ERR_IP, errs[0].Classification)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	// The decoder of github.com/goccy/go-json accepts trailing data, so
+	// validity is checked with the standard library.
+	"encoding/json"
+	"reflect"
+)
+
+// isJSON reports whether v, a string or a byte slice such as
+// json.RawMessage, or a pointer to one, holds a single syntactically valid
+// JSON value.
+func isJSON(v interface{}) bool {
+	if val := reflect.Indirect(reflect.ValueOf(v)); val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Uint8 {
+		return json.Valid(val.Bytes())
+	}
+	return json.Valid([]byte(ruleString(v)))
+}
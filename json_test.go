@@ -26,6 +26,7 @@ import (
 	"testing"
 
 	chi "github.com/go-chi/chi/v5"
+	"github.com/goccy/go-json"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -265,3 +266,72 @@ type (
 		expected            interface{}
 	}
 )
+
+func Test_UseNumber(t *testing.T) {
+	type event struct {
+		ID    int64                  `json:"id"`
+		Attrs map[string]interface{} `json:"attrs"`
+		Value interface{}            `json:"value"`
+	}
+	body := `{"id":9007199254740993,"attrs":{"big":9007199254740993,"price":0.1},"value":12345678901234567890}`
+	bind := func(bind func(*http.Request, interface{}) Errors) event {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		var e event
+		assert.Empty(t, bind(req, &e))
+		return e
+	}
+
+	e := bind(JSON)
+	assert.EqualValues(t, 9007199254740993, e.ID)
+	assert.IsType(t, float64(0), e.Attrs["big"])
+
+	e = bind(New(WithUseNumber(true)).JSON)
+	assert.EqualValues(t, 9007199254740993, e.ID)
+	assert.EqualValues(t, json.Number("9007199254740993"), e.Attrs["big"])
+	assert.EqualValues(t, json.Number("0.1"), e.Attrs["price"])
+	assert.EqualValues(t, json.Number("12345678901234567890"), e.Value)
+}
+
+func Test_StringIntegers(t *testing.T) {
+	type account struct {
+		ID      int64    `json:"id"`
+		Owner   uint64   `json:"owner"`
+		Friends []int64  `json:"friends"`
+		Parent  *int64   `json:"parent"`
+		Legacy  int64    `json:"legacy,string"`
+		Name    string   `json:"name"`
+		Tags    []string `json:"tags"`
+	}
+	body := `{"id":"9223372036854775807","owner":"18446744073709551615","friends":["1",2],"parent":"3","legacy":"4","name":"42","tags":["5"]}`
+	newRequest := func() *http.Request {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	var a account
+	errs := JSON(newRequest(), &a)
+	assert.NotEmpty(t, errs)
+	assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+
+	a = account{}
+	errs = New(WithStringIntegers(true)).JSON(newRequest(), &a)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, int64(9223372036854775807), a.ID)
+	assert.EqualValues(t, uint64(18446744073709551615), a.Owner)
+	assert.EqualValues(t, []int64{1, 2}, a.Friends)
+	if assert.NotNil(t, a.Parent) {
+		assert.EqualValues(t, 3, *a.Parent)
+	}
+	assert.EqualValues(t, 4, a.Legacy)
+	assert.EqualValues(t, "42", a.Name)
+	assert.EqualValues(t, []string{"5"}, a.Tags)
+
+	a = account{}
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"id":"twelve"}`))
+	req.Header.Set("Content-Type", "application/json")
+	errs = New(WithStringIntegers(true)).JSON(req, &a)
+	assert.NotEmpty(t, errs)
+	assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+}
@@ -0,0 +1,305 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+// convertedJSONValue is a scalar JSON value bound into a field whose type
+// the decoder cannot decode it into, but which has a converter, such as
+// sql.NullInt64 or url.URL; see isJSONConverted.
+type convertedJSONValue struct {
+	steps     []jsonStep
+	path      string
+	val       string
+	sensitive bool
+}
+
+// jsonStep leads from a value to one it holds: the struct field at index
+// field, the element at index, or the map element at key.
+type jsonStep struct {
+	field int
+	index int
+	key   string
+	kind  reflect.Kind
+}
+
+// isJSONConverted reports whether values of type typ, or of the type it
+// points to, are converted from JSON strings, numbers and booleans with
+// their converter: structs without an unmarshaler of their own.
+func isJSONConverted(typ reflect.Type) bool {
+	base := typ
+	if base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+	if base.Kind() != reflect.Struct {
+		return false
+	}
+	if ptr := reflect.PtrTo(base); ptr.Implements(jsonUnmarshalerType) || ptr.Implements(textUnmarshalerType) {
+		return false
+	}
+	if _, ok := converters[typ]; ok {
+		return true
+	}
+	_, ok := converters[base]
+	return ok
+}
+
+// jsonConvertedTypes caches holdsJSONConverted by type.
+var jsonConvertedTypes sync.Map
+
+// holdsJSONConverted reports whether values of type typ may hold values
+// converted as described by isJSONConverted.
+func holdsJSONConverted(typ reflect.Type) bool {
+	if v, ok := jsonConvertedTypes.Load(typ); ok {
+		return v.(bool)
+	}
+	holds := typeHoldsJSONConverted(typ, map[reflect.Type]bool{})
+	jsonConvertedTypes.Store(typ, holds)
+	return holds
+}
+
+func typeHoldsJSONConverted(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	if isJSONConverted(typ) {
+		return true
+	}
+	if seen[typ] {
+		return false
+	}
+	seen[typ] = true
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return typeHoldsJSONConverted(typ.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if typeHoldsJSONConverted(typ.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractConvertedJSON replaces the values of data, decoded into a value
+// of type typ, that are converted as described by isJSONConverted with
+// null, so that the decoder leaves them to setConvertedJSON. It returns
+// data unchanged if there are none or if data is malformed, which is left
+// to the decoder to report.
+func extractConvertedJSON(data []byte, typ reflect.Type) ([]byte, []convertedJSONValue) {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if dec.Decode(&doc) != nil {
+		return data, nil
+	}
+	var found []convertedJSONValue
+	doc = extractConvertedValue(typ, doc, nil, "", false, &found)
+	if len(found) == 0 {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	writeJSONValue(&buf, doc)
+	return buf.Bytes(), found
+}
+
+func extractConvertedValue(typ reflect.Type, doc interface{}, steps []jsonStep, path string, sensitive bool, found *[]convertedJSONValue) interface{} {
+	if isJSONConverted(typ) {
+		var val string
+		switch v := doc.(type) {
+		case string:
+			val = v
+		case json.Number:
+			val = v.String()
+		case bool:
+			val = strconv.FormatBool(v)
+		default:
+			return doc
+		}
+		*found = append(*found, convertedJSONValue{append([]jsonStep(nil), steps...), path, val, sensitive})
+		return nil
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if ptr := reflect.PtrTo(typ); ptr.Implements(jsonUnmarshalerType) || ptr.Implements(textUnmarshalerType) {
+		return doc
+	}
+
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		elems, _ := doc.([]interface{})
+		for i := range elems {
+			step := jsonStep{index: i, kind: reflect.Slice}
+			elems[i] = extractConvertedValue(typ.Elem(), elems[i], append(steps, step), path+"["+strconv.Itoa(i)+"]", sensitive, found)
+		}
+	case reflect.Map:
+		// Map elements are not addressable, so only maps holding the
+		// converted values themselves are supported.
+		obj, _ := doc.(map[string]interface{})
+		if typ.Key().Kind() != reflect.String || !isJSONConverted(typ.Elem()) {
+			return doc
+		}
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			step := jsonStep{key: key, kind: reflect.Map}
+			obj[key] = extractConvertedValue(typ.Elem(), obj[key], append(steps, step), path+"["+key+"]", sensitive, found)
+		}
+	case reflect.Struct:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return doc
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			step := jsonStep{field: i, kind: reflect.Struct}
+			if name == "" && field.Anonymous {
+				extractConvertedValue(field.Type, obj, append(steps, step), path, sensitive, found)
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			key, ok := jsonMemberKey(obj, name)
+			if !ok {
+				continue
+			}
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+			obj[key] = extractConvertedValue(field.Type, obj[key], append(steps, step), fieldPath, sensitive || isSensitive(field), found)
+		}
+	}
+	return doc
+}
+
+// jsonMemberKey returns the key of the member of obj decoded into the
+// field called name: the exact key if present, or else the first to
+// match case-insensitively, as the decoder does.
+func jsonMemberKey(obj map[string]interface{}, name string) (string, bool) {
+	if _, ok := obj[name]; ok {
+		return name, true
+	}
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		if strings.EqualFold(key, name) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return "", false
+	}
+	sort.Strings(keys)
+	return keys[0], true
+}
+
+// setConvertedJSON sets the values extracted by extractConvertedJSON in
+// obj, now decoded, with the converters of their types. Values they
+// reject are reported on the path of their field, e.g. "Links[1]".
+func setConvertedJSON(obj interface{}, values []convertedJSONValue, errors Errors) Errors {
+	for _, c := range values {
+		v := reflect.ValueOf(obj)
+		var m, key reflect.Value
+		found := true
+		for _, step := range c.steps {
+			for v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					v.Set(reflect.New(v.Type().Elem()))
+				}
+				v = v.Elem()
+			}
+			switch step.kind {
+			case reflect.Struct:
+				v = v.Field(step.field)
+			case reflect.Slice:
+				if found = step.index < v.Len(); found {
+					v = v.Index(step.index)
+				}
+			case reflect.Map:
+				if found = !v.IsNil(); found {
+					m, key = v, reflect.ValueOf(step.key).Convert(v.Type().Key())
+				}
+			}
+			if !found {
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		typ := v.Type()
+		if m.IsValid() {
+			typ = m.Type().Elem()
+		}
+		converted, err := convertJSONValue(typ, c.val)
+		if err != nil {
+			n := len(errors)
+			errors = addConversionError(errors, c.path, err)
+			annotateErrors(errors[n:], typ, c.val, SOURCE_BODY, c.sensitive)
+			continue
+		}
+		if m.IsValid() {
+			m.SetMapIndex(key, converted)
+		} else {
+			v.Set(converted)
+		}
+	}
+	return errors
+}
+
+// convertJSONValue converts val into a value of type typ with the
+// converter of typ, or of the type typ points to.
+func convertJSONValue(typ reflect.Type, val string) (reflect.Value, error) {
+	convert, ok := converters[typ]
+	base := typ
+	if !ok {
+		base = typ.Elem()
+		convert = converters[base]
+	}
+	v, err := convert(val)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if v == nil {
+		return reflect.Zero(typ), nil
+	}
+	converted := reflect.ValueOf(v)
+	if base == typ {
+		return converted, nil
+	}
+	ptr := reflect.New(base)
+	ptr.Elem().Set(converted)
+	return ptr, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// rejectedJSONValue is a value of a JSON document that the unmarshaler of
+// its field type rejected.
+type rejectedJSONValue struct {
+	path      string
+	typ       reflect.Type
+	raw       json.RawMessage
+	sensitive bool
+}
+
+// unmarshalerErrors reports err, returned by the unmarshaler of a field
+// type, on the field holding the value it rejected, which the decoder does
+// not name. The error is classified the way the converter of the field
+// type classifies the value, e.g. as ERR_UUID for uuid.UUID fields; see
+// AddConverter.
+func unmarshalerErrors(data []byte, obj interface{}, err error) Errors {
+	var errors Errors
+	rejected, ok := findRejectedJSONValue(data, reflect.TypeOf(obj), "", false)
+	if !ok {
+		errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+		return errors
+	}
+	val := string(rejected.raw)
+	var str string
+	if json.Unmarshal(rejected.raw, &str) == nil {
+		val = str
+	}
+	classification, message := ERR_DESERIALIZATION, err.Error()
+	if convert, ok := converters[rejected.typ]; ok {
+		if _, convErr := convert(val); convErr != nil {
+			if e, ok := convErr.(Error); ok {
+				classification, message = e.Classification, e.Message
+			}
+		}
+	}
+	errors.Add([]string{rejected.path}, classification, message)
+	annotateErrors(errors, rejected.typ, val, SOURCE_BODY, rejected.sensitive)
+	return errors
+}
+
+// findRejectedJSONValue walks data, decoded into a value of type typ, to
+// the first value that the unmarshaler of its type fails to decode. Paths
+// name struct fields as in Provided, with indexes and map keys in
+// brackets, e.g. "Items[2].ID".
+func findRejectedJSONValue(data []byte, typ reflect.Type, path string, sensitive bool) (rejectedJSONValue, bool) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if string(data) == "null" {
+		return rejectedJSONValue{}, false
+	}
+	if ptr := reflect.PtrTo(typ); ptr.Implements(jsonUnmarshalerType) || ptr.Implements(textUnmarshalerType) {
+		if json.Unmarshal(data, reflect.New(typ).Interface()) != nil {
+			return rejectedJSONValue{path, typ, data, sensitive}, true
+		}
+		return rejectedJSONValue{}, false
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		var members map[string]json.RawMessage
+		if json.Unmarshal(data, &members) == nil {
+			return findRejectedJSONMember(members, typ, path)
+		}
+	case reflect.Slice, reflect.Array:
+		var elems []json.RawMessage
+		if json.Unmarshal(data, &elems) == nil {
+			for i, elem := range elems {
+				if rejected, ok := findRejectedJSONValue(elem, typ.Elem(), path+"["+strconv.Itoa(i)+"]", sensitive); ok {
+					return rejected, true
+				}
+			}
+		}
+	case reflect.Map:
+		var elems map[string]json.RawMessage
+		if json.Unmarshal(data, &elems) == nil {
+			keys := make([]string, 0, len(elems))
+			for key := range elems {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if rejected, ok := findRejectedJSONValue(elems[key], typ.Elem(), path+"["+key+"]", sensitive); ok {
+					return rejected, true
+				}
+			}
+		}
+	}
+	return rejectedJSONValue{}, false
+}
+
+// findRejectedJSONMember looks for the rejected value among the members of
+// a JSON object decoded into a struct of type typ. Members are matched to
+// fields case-insensitively, as the decoder does, and the fields of
+// embedded structs are promoted.
+func findRejectedJSONMember(members map[string]json.RawMessage, typ reflect.Type, path string) (rejectedJSONValue, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if rejected, ok := findRejectedJSONMember(members, embedded, path); ok {
+					return rejected, true
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		for key, raw := range members {
+			if !strings.EqualFold(key, name) {
+				continue
+			}
+			if rejected, ok := findRejectedJSONValue(raw, field.Type, fieldPath, isSensitive(field)); ok {
+				return rejected, true
+			}
+		}
+	}
+	return rejectedJSONValue{}, false
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"fmt"
+	"mime/multipart"
+	"strings"
+)
+
+// FormLimits caps the shape of form and query input, to protect against
+// pathological requests. Zero values mean no limit.
+type FormLimits struct {
+	// MaxKeys is the maximum number of distinct keys.
+	MaxKeys int
+	// MaxKeyLength is the maximum length of a key in bytes.
+	MaxKeyLength int
+	// MaxDepth is the maximum number of brackets in a key, e.g. 2 for
+	// "user[address][city]".
+	MaxDepth int
+}
+
+// DefaultFormLimits are the limits applied to forms and query strings
+// bound without a Binder configured with WithFormLimits. There are no
+// limits by default.
+var DefaultFormLimits FormLimits
+
+// WithFormLimits sets the limits applied to forms and query strings.
+func WithFormLimits(limits FormLimits) Option {
+	return func(b *Binder) {
+		b.formLimits = limits
+	}
+}
+
+// MaxJSONDepth is the maximum nesting depth of objects and arrays in JSON
+// bodies bound without a Binder configured with WithMaxJSONDepth, e.g. 2
+// for {"a":[1]}. Deeper documents are rejected with ERR_LIMIT before they
+// are decoded. Zero, the default, means no limit.
+var MaxJSONDepth = 0
+
+// WithMaxJSONDepth sets the maximum nesting depth of JSON bodies; see
+// MaxJSONDepth.
+func WithMaxJSONDepth(n int) Option {
+	return func(b *Binder) {
+		b.maxJSONDepth = n
+	}
+}
+
+// checkJSONDepth reports an ERR_LIMIT error if objects and arrays in data
+// are nested deeper than max.
+func checkJSONDepth(data []byte, max int) Errors {
+	if max <= 0 {
+		return nil
+	}
+	depth, inString := 0, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+			if depth > max {
+				var errs Errors
+				errs.Add([]string{}, ERR_LIMIT, fmt.Sprintf("JSON is nested deeper than %d levels", max))
+				return errs
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return nil
+}
+
+// check reports an ERR_LIMIT error if the keys of form and files exceed
+// the limits.
+func (l FormLimits) check(form map[string][]string, files map[string][]*multipart.FileHeader) Errors {
+	var errs Errors
+	if l.MaxKeys > 0 && len(form)+len(files) > l.MaxKeys {
+		errs.Add([]string{}, ERR_LIMIT, fmt.Sprintf("Too many keys, at most %d are allowed", l.MaxKeys))
+		return errs
+	}
+	if l.MaxKeyLength <= 0 && l.MaxDepth <= 0 {
+		return nil
+	}
+	checkKey := func(key string) bool {
+		if l.MaxKeyLength > 0 && len(key) > l.MaxKeyLength {
+			errs.Add([]string{}, ERR_LIMIT, fmt.Sprintf("Key is longer than %d bytes", l.MaxKeyLength))
+			return false
+		}
+		if l.MaxDepth > 0 && strings.Count(key, "[") > l.MaxDepth {
+			errs.Add([]string{}, ERR_LIMIT, fmt.Sprintf("Key %q is nested deeper than %d levels", key, l.MaxDepth))
+			return false
+		}
+		return true
+	}
+	for key := range form {
+		if !checkKey(key) {
+			return errs
+		}
+	}
+	for key := range files {
+		if !checkKey(key) {
+			return errs
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FormLimits(t *testing.T) {
+	b := New(WithFormLimits(FormLimits{MaxKeys: 3, MaxKeyLength: 10, MaxDepth: 1}))
+	bind := func(bind func(*http.Request, interface{}) Errors, query, body string) Errors {
+		req, _ := http.NewRequest("POST", "/?"+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", formContentType)
+		var post Post
+		return bind(req, &post)
+	}
+
+	assert.Empty(t, bind(b.Form, "title=Hello,+world&a[b]=1", "content=c"))
+
+	// The input is not bound if it exceeds the limits.
+	errs := bind(b.Form, "title=Hello,+world&a=1", "b=1&c=1")
+	assert.EqualValues(t, ERR_LIMIT, errs[0].Classification)
+	assert.EqualValues(t, "Too many keys, at most 3 are allowed", errs[0].Message)
+
+	errs = bind(b.Query, "title=Hello,+world&abcdefghijk=1", "")
+	assert.EqualValues(t, "Key is longer than 10 bytes", errs[0].Message)
+
+	errs = bind(b.Form, "title=Hello,+world", "a[b][c]=1")
+	assert.EqualValues(t, `Key "a[b][c]" is nested deeper than 1 levels`, errs[0].Message)
+
+	// No limits apply by default.
+	assert.Empty(t, bind(Form, "title=Hello,+world&a=1", "b=1&c[d][e]=1&abcdefghijk=1"))
+}
+
+func Test_MaxJSONDepth(t *testing.T) {
+	bind := func(bind func(*http.Request, interface{}) Errors, body string) Errors {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		var doc struct {
+			Title  string      `json:"title"`
+			Author Person      `json:"author"`
+			X      interface{} `json:"x"`
+		}
+		return bind(req, &doc)
+	}
+	b := New(WithMaxJSONDepth(2))
+
+	errs := bind(b.JSON, `{"title":"Hello, world","author":{"name":"[{[{"},"x":[1]}`)
+	assert.Empty(t, errs)
+
+	errs = bind(b.JSON, `{"title":"Hello, world","author":{"name":"\"{["},"x":[[1]]}`)
+	assert.EqualValues(t, ERR_LIMIT, errs[0].Classification)
+	assert.EqualValues(t, "JSON is nested deeper than 2 levels", errs[0].Message)
+
+	deep := strings.Repeat("[", 10000) + strings.Repeat("]", 10000)
+	errs = bind(b.Bind, `{"x":`+deep+`}`)
+	assert.EqualValues(t, ERR_LIMIT, errs[0].Classification)
+
+	MaxJSONDepth = 3
+	defer func() { MaxJSONDepth = 0 }()
+	errs = bind(JSON, `{"author":{"name":"A"},"x":[[1]]}`)
+	assert.Empty(t, errs)
+	errs = bind(JSON, `{"x":[[[1]]]}`)
+	assert.EqualValues(t, ERR_LIMIT, errs[0].Classification)
+}
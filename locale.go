@@ -0,0 +1,125 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"reflect"
+	"strings"
+)
+
+// NumberLocale describes how numbers are written in a locale.
+type NumberLocale struct {
+	// DecimalSeparator separates the integer part from the fraction.
+	DecimalSeparator string
+	// GroupSeparators are the thousands separators accepted in input.
+	GroupSeparators []string
+}
+
+var (
+	numberLocales = map[string]NumberLocale{
+		"en": {DecimalSeparator: ".", GroupSeparators: []string{","}},
+		"de": {DecimalSeparator: ",", GroupSeparators: []string{"."}},
+		"fr": {DecimalSeparator: ",", GroupSeparators: []string{" ", "\u00a0", "\u202f"}},
+		"ch": {DecimalSeparator: ".", GroupSeparators: []string{"'", "\u2019"}},
+	}
+	numberLocale string
+)
+
+// AddNumberLocale registers a number locale under the given name.
+func AddNumberLocale(name string, locale NumberLocale) {
+	numberLocales[name] = locale
+}
+
+// SetNumberLocale sets the locale used to parse float fields from form
+// values, e.g. "de" to accept "1.234,5". Fields can override it with a
+// `locale:"name"` tag. An empty name, the default, only accepts plain
+// Go float syntax.
+func SetNumberLocale(name string) {
+	numberLocale = name
+}
+
+// WithNumberLocale sets the locale used to parse float fields from form
+// values; see SetNumberLocale.
+func WithNumberLocale(name string) Option {
+	return func(b *Binder) {
+		b.numberLocale = name
+	}
+}
+
+// localizeFloat rewrites a float written in the locale of the field, or
+// else in defaultLocale, into Go syntax. Values of non-float fields are
+// returned unchanged. ok is false if group separators are used anywhere
+// but between groups of three digits left of the decimal separator, e.g.
+// for "1.5" in the "de" locale, so the value is rejected rather than read
+// as 15.
+func localizeFloat(field reflect.StructField, kind reflect.Kind, val, defaultLocale string) (_ string, ok bool) {
+	if kind != reflect.Float32 && kind != reflect.Float64 {
+		return val, true
+	}
+	name := defaultLocale
+	if tag, ok := field.Tag.Lookup("locale"); ok {
+		name = tag
+	}
+	locale, ok := numberLocales[name]
+	if !ok {
+		return val, true
+	}
+
+	var sign string
+	if strings.HasPrefix(val, "-") || strings.HasPrefix(val, "+") {
+		sign, val = val[:1], val[1:]
+	}
+	integer, point, fraction := val, "", ""
+	if i := strings.Index(val, locale.DecimalSeparator); locale.DecimalSeparator != "" && i >= 0 {
+		integer, point, fraction = val[:i], ".", val[i+len(locale.DecimalSeparator):]
+	}
+	for _, sep := range locale.GroupSeparators {
+		if sep != "" && strings.Contains(fraction, sep) {
+			return "", false
+		}
+	}
+	integer, ok = ungroupDigits(integer, locale.GroupSeparators)
+	return sign + integer + point + fraction, ok
+}
+
+// ungroupDigits removes the group separators seps from s, which must then
+// consist of groups of digits separated by one of seps, the first of 1 to
+// 3 digits and the others of exactly 3.
+func ungroupDigits(s string, seps []string) (string, bool) {
+	var groups []string
+	for {
+		i, n := -1, 0
+		for _, sep := range seps {
+			if j := strings.Index(s, sep); sep != "" && j >= 0 && (i < 0 || j < i) {
+				i, n = j, len(sep)
+			}
+		}
+		if i < 0 {
+			groups = append(groups, s)
+			break
+		}
+		groups = append(groups, s[:i])
+		s = s[i+n:]
+	}
+	if len(groups) == 1 {
+		return groups[0], true
+	}
+	for i, group := range groups {
+		if strings.Trim(group, "0123456789") != "" || i == 0 && (len(group) == 0 || len(group) > 3) || i > 0 && len(group) != 3 {
+			return "", false
+		}
+	}
+	return strings.Join(groups, ""), true
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/mail"
+)
+
+// Fields of type mail.Address and *mail.Address are parsed as RFC 5322
+// addresses, which may include a display name, e.g. "Alice <alice@example.com>",
+// from form values as well as JSON strings.
+func init() {
+	parseAddress := func(val string) (*mail.Address, error) {
+		addr, err := mail.ParseAddress(val)
+		if err != nil {
+			return nil, Error{Classification: ERR_EMAIL, Message: "Value could not be parsed as email address"}
+		}
+		return addr, nil
+	}
+	AddConverter(&mail.Address{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return (*mail.Address)(nil), nil
+		}
+		return parseAddress(val)
+	})
+	AddConverter(mail.Address{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return mail.Address{}, nil
+		}
+		addr, err := parseAddress(val)
+		if err != nil {
+			return nil, err
+		}
+		return *addr, nil
+	})
+}
+
+// emailAddress returns the address part of mail.Address values, and the
+// formatted value of anything else, following pointers.
+func emailAddress(v interface{}) string {
+	switch v := v.(type) {
+	case mail.Address:
+		return v.Address
+	case *mail.Address:
+		if v == nil {
+			return ""
+		}
+		return v.Address
+	}
+	return ruleString(v)
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type inviteForm struct {
+	To *mail.Address  `form:"to" binding:"Required;Email"`
+	Cc []mail.Address `form:"cc"`
+}
+
+func Test_MailAddress(t *testing.T) {
+	query := url.Values{
+		"to": {`"Alice Liddell" <alice@example.com>`},
+		"cc": {"bob@example.com", "Carol <carol@example.com>"},
+	}
+	req, _ := http.NewRequest("GET", "/?"+query.Encode(), nil)
+	var form inviteForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, &mail.Address{Name: "Alice Liddell", Address: "alice@example.com"}, form.To)
+	assert.EqualValues(t, []mail.Address{{Address: "bob@example.com"}, {Name: "Carol", Address: "carol@example.com"}}, form.Cc)
+
+	req, _ = http.NewRequest("GET", "/?to=Alice", nil)
+	form = inviteForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, ERR_EMAIL, errs[0].Classification)
+	assert.EqualValues(t, ERR_REQUIRED, errs[1].Classification)
+}
+
+func Test_MailAddressJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"to":"Bob <bob@example.com>","cc":["carol@example.com"]}`))
+	var form inviteForm
+	errs := JSON(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, &mail.Address{Name: "Bob", Address: "bob@example.com"}, form.To)
+	assert.EqualValues(t, []mail.Address{{Address: "carol@example.com"}}, form.Cc)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"to":"Bob <bob@example.com>","cc":["carol"]}`))
+	form = inviteForm{}
+	errs = JSON(req, &form)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_EMAIL, errs[0].Classification)
+		assert.EqualValues(t, []string{"Cc[0]"}, errs[0].FieldNames)
+	}
+	assert.NotNil(t, form.To)
+}
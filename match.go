@@ -0,0 +1,58 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// matchPatterns caches the compiled patterns of Match rules, so each
+// pattern is only compiled once.
+var matchPatterns sync.Map
+
+func init() {
+	ruleParamCheckers["Match"] = func(pattern string) error {
+		re, err := regexp.Compile(pattern)
+		if err == nil {
+			matchPatterns.LoadOrStore(pattern, re)
+		}
+		return err
+	}
+}
+
+// matchPattern returns the compiled pattern of a Match rule, e.g.
+// `^[a-z0-9_]+$` for Match(^[a-z0-9_]+$). The pattern is everything
+// between the first opening and the last closing parenthesis of the rule,
+// so it may contain parentheses itself; semicolons must be written as \;
+// to keep them from ending the rule. As struct tag values are quoted
+// strings, backslashes are doubled there:
+//
+//	Code string `binding:"Match(^[A-Z]{2}-\\d+(\\;\\d+)*$)"`
+//
+// Invalid patterns panic when the rules of the struct type are checked,
+// before any value is matched; see checkTypeRules.
+func matchPattern(pattern string) *regexp.Regexp {
+	if re, ok := matchPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("binding: invalid Match pattern %q: %v", pattern, err))
+	}
+	compiled, _ := matchPatterns.LoadOrStore(pattern, re)
+	return compiled.(*regexp.Regexp)
+}
@@ -0,0 +1,212 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/goccy/go-json"
+)
+
+// MessageData is the data message templates are executed with.
+type MessageData struct {
+	// Field is the name of the field, e.g. "Tags[0]" for an element.
+	Field string
+	// Rule is the name of the rule that failed, e.g. "Range". It is empty
+	// for errors of custom rules whose message is not the rule name.
+	Rule string
+	// Params are the parameters of the rule, e.g. ["1", "5"] for
+	// Range(1,5).
+	Params []string
+	// Min and Max are the bounds of Range, Between, Min, Max, MinSize,
+	// MaxSize and Size.
+	Min, Max string
+	// Count is the bound of MinSize, MaxSize and Size as a number, which
+	// selects the plural form of catalog messages.
+	Count int
+	// Value is the value of the field.
+	Value interface{}
+	// Message is the message the error would have otherwise.
+	Message string
+}
+
+var messageTemplates = map[string]*template.Template{}
+
+// SetMessageTemplate sets the text/template used to render the messages
+// of validation errors with the given classification, e.g.
+//
+//	binding.SetMessageTemplate(binding.ERR_RANGE,
+//		"{{.Field}} must be between {{.Min}} and {{.Max}}")
+//
+// The template is executed with a MessageData. It panics if the template
+// cannot be parsed.
+func SetMessageTemplate(classification, text string) {
+	messageTemplates[classification] = template.Must(template.New(classification).Parse(text))
+}
+
+// messageData returns the data for the message of err, raised by one of
+// rules for field.
+func messageData(err Error, rules []string, field string, value interface{}) MessageData {
+	data := MessageData{Field: field, Value: value, Message: err.Message}
+	for _, rule := range rules {
+		if ruleName(rule) != err.Message {
+			continue
+		}
+		data.Rule = err.Message
+		if i := strings.IndexByte(rule, '('); i >= 0 && strings.HasSuffix(rule, ")") {
+			data.Params = strings.Split(rule[i+1:len(rule)-1], ",")
+			if data.Rule == "Match" {
+				// Commas are part of the pattern.
+				data.Params = []string{rule[i+1 : len(rule)-1]}
+			}
+		}
+		break
+	}
+
+	switch {
+	case (data.Rule == "Range" || data.Rule == "Between") && len(data.Params) == 2:
+		data.Min, data.Max = data.Params[0], data.Params[1]
+	case (data.Rule == "MinSize" || data.Rule == "MinDuration" || data.Rule == "Min") && len(data.Params) == 1:
+		data.Min = data.Params[0]
+	case (data.Rule == "MaxSize" || data.Rule == "MaxDuration" || data.Rule == "Max") && len(data.Params) == 1:
+		data.Max = data.Params[0]
+	case data.Rule == "Size" && len(data.Params) == 1:
+		data.Min, data.Max = data.Params[0], data.Params[0]
+	}
+	if data.Rule != "Range" && len(data.Params) == 1 {
+		data.Count, _ = strconv.Atoi(data.Params[0])
+	}
+	return data
+}
+
+type (
+	// Catalog holds the messages of a locale, keyed by classification.
+	Catalog map[string]Message
+
+	// Message holds the text/template of a message for each plural
+	// category, e.g. {"one": "... 1 item", "other": "... {{.Count}} items"}.
+	// The categories are those of CLDR: "zero", "one", "two", "few",
+	// "many" and "other", which is used when a category is missing.
+	Message map[string]string
+
+	// PluralRule returns the plural category of the count n.
+	PluralRule func(n int) string
+)
+
+// UnmarshalJSON implements json.Unmarshaler, accepting a plain string
+// as the message for all counts.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var text string
+	if json.Unmarshal(data, &text) == nil {
+		*m = Message{"other": text}
+		return nil
+	}
+	var forms map[string]string
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return err
+	}
+	*m = forms
+	return nil
+}
+
+var (
+	catalogs    = map[string]map[string]map[string]*template.Template{}
+	pluralRules = map[string]PluralRule{
+		"fr": func(n int) string {
+			if n == 0 || n == 1 {
+				return "one"
+			}
+			return "other"
+		},
+	}
+)
+
+// AddCatalog registers the messages of a locale, e.g. "de" or "pt-BR",
+// which are used for requests whose ValidationConfig has that Locale.
+// Messages of a locale such as "de-CH" fall back to those of "de", and
+// then to the templates set with SetMessageTemplate. It panics if a
+// template cannot be parsed.
+func AddCatalog(locale string, catalog Catalog) {
+	messages := catalogs[locale]
+	if messages == nil {
+		messages = make(map[string]map[string]*template.Template)
+		catalogs[locale] = messages
+	}
+	for classification, forms := range catalog {
+		parsed := make(map[string]*template.Template, len(forms))
+		for category, text := range forms {
+			parsed[category] = template.Must(template.New(classification + "." + category).Parse(text))
+		}
+		messages[classification] = parsed
+	}
+}
+
+// AddPluralRule sets the plural rule of a locale. Locales without a rule
+// use "one" for 1 and "other" for all other counts, as English does.
+func AddPluralRule(locale string, rule PluralRule) {
+	pluralRules[locale] = rule
+}
+
+// baseLocale returns the language of locale, e.g. "de" for "de-CH".
+func baseLocale(locale string) string {
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+// messageTemplate returns the template for errors with the given
+// classification and count in locale.
+func messageTemplate(locale, classification string, count int) *template.Template {
+	for _, name := range []string{locale, baseLocale(locale)} {
+		forms, ok := catalogs[name][classification]
+		if !ok {
+			continue
+		}
+		rule, ok := pluralRules[name]
+		if !ok {
+			rule = pluralRules[baseLocale(name)]
+		}
+		category := "other"
+		if rule != nil {
+			category = rule(count)
+		} else if count == 1 {
+			category = "one"
+		}
+		if tmpl, ok := forms[category]; ok {
+			return tmpl
+		}
+		return forms["other"]
+	}
+	return messageTemplates[classification]
+}
+
+// renderMessages replaces the messages of errs, raised by rules for field,
+// using the templates of their classification in locale.
+func renderMessages(errs Errors, locale string, rules []string, field string, value interface{}) {
+	for i := range errs {
+		data := messageData(errs[i], rules, field, value)
+		tmpl := messageTemplate(locale, errs[i].Classification, data.Count)
+		if tmpl == nil {
+			continue
+		}
+		var msg strings.Builder
+		if tmpl.Execute(&msg, data) == nil {
+			errs[i].Message = msg.String()
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"testing"
+	"text/template"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MessageTemplates(t *testing.T) {
+	type ticketForm struct {
+		Title    string   `binding:"Required;MaxSize(5)"`
+		Priority int      `binding:"Range(1,5)"`
+		Labels   []string `binding:"Dive;AlphaDash"`
+	}
+
+	defer func() { messageTemplates = map[string]*template.Template{} }()
+	SetMessageTemplate(ERR_RANGE, "{{.Field}} must be between {{.Min}} and {{.Max}}, not {{.Value}}")
+	SetMessageTemplate(ERR_MAX_SIZE, "{{.Field}} must be at most {{.Max}} characters long")
+	SetMessageTemplate(ERR_ALPHA_DASH, "{{.Field}}: {{.Message}} ({{.Rule}})")
+
+	errs := RawValidate(ticketForm{Title: "Too long", Priority: 9, Labels: []string{"ok", "not ok"}})
+	assert.Len(t, errs, 3)
+	assert.EqualValues(t, "Title must be at most 5 characters long", errs[0].Message)
+	assert.EqualValues(t, "Priority must be between 1 and 5, not 9", errs[1].Message)
+	assert.EqualValues(t, "Labels[1]: AlphaDash (AlphaDash)", errs[2].Message)
+
+	errs = RawValidate(ticketForm{Priority: 1})
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, "Required", errs[0].Message)
+
+	assert.Panics(t, func() { SetMessageTemplate(ERR_REQUIRED, "{{.Field") })
+}
+
+func Test_MessageCatalog(t *testing.T) {
+	type listForm struct {
+		Tags  []string `binding:"MinSize(2)"`
+		Names []string `binding:"MaxSize(1)"`
+	}
+
+	defer func() { catalogs = map[string]map[string]map[string]*template.Template{} }()
+	AddCatalog("en", Catalog{
+		ERR_MIN_SIZE: {"one": "{{.Field}} must contain at least 1 item", "other": "{{.Field}} must contain at least {{.Count}} items"},
+		ERR_MAX_SIZE: {"one": "{{.Field}} must contain at most 1 item", "other": "{{.Field}} must contain at most {{.Count}} items"},
+	})
+	AddCatalog("fr", Catalog{
+		ERR_MAX_SIZE: {"one": "{{.Field}} doit contenir au plus {{.Count}} élément", "other": "{{.Field}} doit contenir au plus {{.Count}} éléments"},
+	})
+
+	validate := func(locale string, form listForm) Errors {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req = req.WithContext(WithValidationConfig(req.Context(), &ValidationConfig{Locale: locale}))
+		return Validate(req, &form)
+	}
+
+	errs := validate("en-US", listForm{Tags: []string{"a"}, Names: []string{"a", "b"}})
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, "Tags must contain at least 2 items", errs[0].Message)
+	assert.EqualValues(t, "Names must contain at most 1 item", errs[1].Message)
+
+	errs = validate("fr", listForm{Names: []string{"a", "b"}})
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, "Names doit contenir au plus 1 élément", errs[0].Message)
+
+	errs = validate("de", listForm{Names: []string{"a", "b"}})
+	assert.EqualValues(t, "MaxSize", errs[0].Message)
+
+	var catalog Catalog
+	assert.NoError(t, json.Unmarshal([]byte(`{"RequiredError":"Pflichtfeld","SizeError":{"one":"1 Zeichen"}}`), &catalog))
+	assert.EqualValues(t, Catalog{ERR_REQUIRED: {"other": "Pflichtfeld"}, ERR_SIZE: {"one": "1 Zeichen"}}, catalog)
+}
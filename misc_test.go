@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -119,3 +120,125 @@ type Everything struct {
 	Fl64_2     float64 `form:"fl64_2"`
 	Str        string  `form:"str"`
 }
+
+func Test_Overflow(t *testing.T) {
+	type overflowForm struct {
+		Small    int8    `form:"small" json:"small"`
+		Unsigned uint16  `form:"unsigned" json:"unsigned"`
+		Float    float32 `form:"float"`
+	}
+
+	req, _ := http.NewRequest("GET", "/?small=300&unsigned=-1&float=1e50", nil)
+	var form overflowForm
+	errs := Form(req, &form)
+	assert.Len(t, errs, 3)
+	for _, err := range errs {
+		assert.EqualValues(t, ERR_OVERFLOW, err.Classification)
+	}
+	assert.EqualValues(t, []string{"small"}, errs[0].FieldNames)
+	assert.EqualValues(t, "Value of field small overflows int8", errs[0].Message)
+	assert.EqualValues(t, 0, form.Small)
+
+	req, _ = http.NewRequest("GET", "/?small=abc", nil)
+	errs = Form(req, &form)
+	assert.True(t, errs.Has(ERR_INTERGER_TYPE))
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"small":-300}`))
+	errs = JSON(req, &form)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, ERR_OVERFLOW, errs[0].Classification)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"small":"abc"}`))
+	errs = JSON(req, &form)
+	assert.True(t, errs.Has(ERR_DESERIALIZATION))
+}
+
+func Test_TypeErrorMetadata(t *testing.T) {
+	type metadataForm struct {
+		Age  int    `form:"age" json:"age"`
+		Pin  int    `form:"pin" binding:"Sensitive"`
+		Tags []uint `form:"tag"`
+	}
+
+	req, _ := http.NewRequest("POST", "/?age=old&tag=1&tag=x", strings.NewReader("pin=secret"))
+	req.Header.Set("Content-Type", formContentType)
+	var form metadataForm
+	errs := Form(req, &form)
+	assert.EqualValues(t, Errors{
+		{FieldNames: []string{"age"}, Classification: ERR_INTERGER_TYPE, Message: "Value could not be parsed as integer", ExpectedType: "int", Value: "old", Source: SOURCE_QUERY},
+		{FieldNames: []string{"pin"}, Classification: ERR_INTERGER_TYPE, Message: "Value could not be parsed as integer", ExpectedType: "int", Source: SOURCE_FORM},
+		{FieldNames: []string{"tag"}, Classification: ERR_INTERGER_TYPE, Message: "Value could not be parsed as unsigned integer", ExpectedType: "uint", Value: "x", Source: SOURCE_QUERY},
+	}, errs)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"age":"old"}`))
+	errs = JSON(req, &form)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, []string{"Age"}, errs[0].FieldNames)
+	assert.EqualValues(t, "int", errs[0].ExpectedType)
+	assert.EqualValues(t, "old", errs[0].Value)
+	assert.EqualValues(t, SOURCE_BODY, errs[0].Source)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"Pin":"secret"}`))
+	errs = JSON(req, &form)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, []string{"Pin"}, errs[0].FieldNames)
+		assert.Empty(t, errs[0].Value)
+	}
+
+	type limits struct {
+		Max int `json:"max"`
+	}
+	var nested struct {
+		Limits limits `json:"limits"`
+	}
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"limits": {"max": [1, 2]}}`))
+	errs = JSON(req, &nested)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, "[1, 2]", errs[0].Value)
+	}
+}
+
+func Test_NumberLocale(t *testing.T) {
+	type priceForm struct {
+		Price    float64   `form:"price"`
+		Weight   float32   `form:"weight" locale:"fr"`
+		Discount []float64 `form:"discount" locale:""`
+		Quantity int       `form:"quantity"`
+	}
+
+	SetNumberLocale("de")
+	defer SetNumberLocale("")
+
+	req, _ := http.NewRequest("GET", "/?price=1.234,56&weight=1+000,5&discount=0.5&quantity=3", nil)
+	var form priceForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, 1234.56, form.Price)
+	assert.EqualValues(t, 1000.5, form.Weight)
+	assert.EqualValues(t, []float64{0.5}, form.Discount)
+	assert.EqualValues(t, 3, form.Quantity)
+
+	req, _ = http.NewRequest("GET", "/?price=1,2,3", nil)
+	errs = Form(req, &form)
+	assert.True(t, errs.Has(ERR_FLOAT_TYPE))
+	assert.EqualValues(t, "1,2,3", errs[0].Value)
+
+	for val, expected := range map[string]float64{"1.234,5": 1234.5, "-1.234.567": -1234567, "1,5": 1.5, "12.345,": 12345} {
+		req, _ = http.NewRequest("GET", "/?price="+url.QueryEscape(val), nil)
+		form = priceForm{}
+		errs = Form(req, &form)
+		assert.Empty(t, errs, val)
+		assert.EqualValues(t, expected, form.Price, val)
+	}
+	// Group separators outside of groups of three digits are errors, not
+	// silently dropped.
+	for _, val := range []string{"1.5", "1.50", "1.2345", ".123", "1..234", "1,234.5"} {
+		req, _ = http.NewRequest("GET", "/?price="+url.QueryEscape(val), nil)
+		form = priceForm{}
+		errs = Form(req, &form)
+		if assert.Len(t, errs, 1, val) {
+			assert.EqualValues(t, ERR_FLOAT_TYPE, errs[0].Classification, val)
+			assert.EqualValues(t, val, errs[0].Value, val)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+)
+
+// Part is a document bound from one part of a multipart/mixed request,
+// see BindMixed.
+type Part struct {
+	// Obj is the struct the part was bound into.
+	Obj interface{}
+	// ContentType is the Content-Type of the part.
+	ContentType string
+	// Errors are the errors of binding the part.
+	Errors Errors
+}
+
+// BindMixed binds each part of a multipart/mixed request, as sent to batch
+// APIs, into a new struct returned by newObj. Each part is decoded
+// according to its own Content-Type and validated on its own, so that the
+// parts can be processed or rejected individually. The returned Errors
+// only hold the errors of the request as a whole, e.g. a malformed body.
+func BindMixed(req *http.Request, newObj func() interface{}) ([]Part, Errors) {
+	var errors Errors
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" || params["boundary"] == "" {
+		errors.Add([]string{}, ERR_CONTENT_TYPE, "Unsupported Content-Type")
+		return nil, errors
+	}
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+
+	var parts []Part
+	reader := multipart.NewReader(req.Body, params["boundary"])
+	for {
+		p, err := reader.NextPart()
+		if err != nil {
+			if err != io.EOF {
+				errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+			}
+			return parts, errors
+		}
+		part := Part{Obj: newObj(), ContentType: p.Header.Get("Content-Type")}
+		part.Errors = Bind(partRequest(req, p), part.Obj)
+		parts = append(parts, part)
+	}
+}
+
+// partRequest returns a request for binding the part p of req on its own.
+func partRequest(req *http.Request, p *multipart.Part) *http.Request {
+	sub := new(http.Request)
+	*sub = *req
+	sub.Header = http.Header(p.Header)
+	sub.Body = p
+	sub.ContentLength = -1
+	sub.Form, sub.PostForm, sub.MultipartForm = nil, nil, nil
+	u := *req.URL
+	u.RawQuery = ""
+	sub.URL = &u
+	if sub.Method != "PUT" && sub.Method != "PATCH" {
+		sub.Method = "POST"
+	}
+	return sub
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BindMixed(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, body string }{
+		{"application/json", `{"title":"Hello, world"}`},
+		{"application/x-www-form-urlencoded", "title=Hello,+form&content=c"},
+		{"application/json", `{"title":"Short"}`},
+		{"text/plain", "Hello"},
+	} {
+		pw, _ := w.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		pw.Write([]byte(part.body))
+	}
+	w.Close()
+
+	req, _ := http.NewRequest("POST", "/batch?title=Hello,+query", &body)
+	req.Header.Set("Content-Type", "multipart/mixed; boundary="+w.Boundary())
+	parts, errs := BindMixed(req, func() interface{} { return new(Post) })
+	assert.Empty(t, errs)
+	if assert.Len(t, parts, 4) {
+		assert.Empty(t, parts[0].Errors)
+		assert.EqualValues(t, "Hello, world", parts[0].Obj.(*Post).Title)
+		assert.EqualValues(t, "application/json", parts[0].ContentType)
+
+		assert.Empty(t, parts[1].Errors)
+		assert.EqualValues(t, "Hello, form", parts[1].Obj.(*Post).Title)
+		assert.EqualValues(t, "c", parts[1].Obj.(*Post).Content)
+
+		assert.EqualValues(t, "LengthError", parts[2].Errors[0].Classification)
+		assert.EqualValues(t, ERR_CONTENT_TYPE, parts[3].Errors[0].Classification)
+	}
+
+	req, _ = http.NewRequest("POST", "/batch", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	parts, errs = BindMixed(req, func() interface{} { return new(Post) })
+	assert.Nil(t, parts)
+	assert.EqualValues(t, ERR_CONTENT_TYPE, errs[0].Classification)
+
+	req, _ = http.NewRequest("POST", "/batch", strings.NewReader("--x\r\nContent-Type: application/json\r\n\r\n{}"))
+	req.Header.Set("Content-Type", "multipart/mixed; boundary=x")
+	_, errs = BindMixed(req, func() interface{} { return new(Post) })
+	assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package msgpack adds MessagePack bodies to binding.Bind. Import it for
+// its side effects:
+//
+//	import _ "gitea.com/go-chi/binding/msgpack"
+//
+// Bodies with Content-Type application/msgpack or application/x-msgpack
+// are then decoded with github.com/vmihailenco/msgpack, with fields named
+// by their msgpack tags, and validated like JSON bodies.
+package msgpack
+
+import (
+	"io"
+	"net/http"
+
+	"gitea.com/go-chi/binding"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func init() {
+	binding.AddBodyDecoder(decode, "application/msgpack", "application/x-msgpack")
+}
+
+func decode(body io.Reader, obj interface{}) error {
+	return msgpack.NewDecoder(body).Decode(obj)
+}
+
+// Bind binds the MessagePack body of req into obj and validates it.
+// Unlike binding.Bind it does not look at the Content-Type.
+func Bind(req *http.Request, obj interface{}) binding.Errors {
+	return binding.DecodeWith(req, obj, decode)
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package msgpack
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitea.com/go-chi/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type post struct {
+	Title string   `msgpack:"title" binding:"Required;MinSize(5)"`
+	Tags  []string `msgpack:"tags"`
+}
+
+func request(t *testing.T, contentType string, v interface{}) *http.Request {
+	body, err := msgpack.Marshal(v)
+	assert.NoError(t, err)
+	req, _ := http.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return req
+}
+
+func Test_Bind(t *testing.T) {
+	for _, contentType := range []string{"application/msgpack", "application/x-msgpack"} {
+		var p post
+		errs := binding.Bind(request(t, contentType, map[string]interface{}{"title": "Hello", "tags": []string{"a", "b"}}), &p)
+		assert.Empty(t, errs)
+		assert.Equal(t, post{Title: "Hello", Tags: []string{"a", "b"}}, p)
+	}
+
+	var p post
+	errs := binding.Bind(request(t, "application/msgpack", map[string]interface{}{"title": "Hi"}), &p)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, binding.ERR_MIN_SIZE, errs[0].Classification)
+		assert.Equal(t, []string{"Title"}, errs[0].FieldNames)
+	}
+
+	req, _ := http.NewRequest("POST", "/", strings.NewReader("\xc1"))
+	req.Header.Set("Content-Type", "application/msgpack")
+	errs = binding.Bind(req, &p)
+	if assert.NotEmpty(t, errs) {
+		assert.Equal(t, binding.ERR_DESERIALIZATION, errs[0].Classification)
+	}
+}
+
+func Test_BindExplicit(t *testing.T) {
+	var p post
+	errs := Bind(request(t, "application/octet-stream", map[string]interface{}{"title": "Hello"}), &p)
+	assert.Empty(t, errs)
+	assert.Equal(t, "Hello", p.Title)
+}
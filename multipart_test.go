@@ -21,8 +21,11 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
 	"strconv"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	chi "github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -121,6 +124,202 @@ func performMultipartFormTest(t *testing.T, binder handlerFunc, testCase multipa
 	}
 }
 
+func Test_MultipartFormInterleaved(t *testing.T) {
+	type upload struct {
+		Title       string                  `form:"title" binding:"Required"`
+		Tags        []string                `form:"tag"`
+		Attachment  *multipart.FileHeader   `form:"attachment"`
+		Attachments []*multipart.FileHeader `form:"attachments"`
+	}
+
+	defer func(max int64) { MaxMemory = max }(MaxMemory)
+	MaxMemory = 1024
+	large := strings.Repeat("x", 64*1024)
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	w.WriteField("tag", "a")
+	part, _ := w.CreateFormFile("attachments", "one.txt")
+	part.Write([]byte(large))
+	w.WriteField("tag", "b")
+	part, _ = w.CreateFormFile("attachment", "single.txt")
+	part.Write([]byte("single"))
+	part, _ = w.CreateFormFile("attachments", "two.txt")
+	part.Write([]byte(large))
+	w.WriteField("title", "Late title")
+	w.Close()
+
+	// Deliver the body one byte at a time so that part boundaries are
+	// split across reads.
+	req, _ := http.NewRequest("POST", "/", iotest.OneByteReader(bytes.NewReader(body.Bytes())))
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	var actual upload
+	errs := MultipartForm(req, &actual)
+	defer req.MultipartForm.RemoveAll()
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "Late title", actual.Title)
+	assert.EqualValues(t, []string{"a", "b"}, actual.Tags)
+	assert.EqualValues(t, "single.txt", actual.Attachment.Filename)
+	assert.EqualValues(t, "single", unpackFileHeaderData(actual.Attachment))
+	assert.Len(t, actual.Attachments, 2)
+	assert.EqualValues(t, "one.txt", actual.Attachments[0].Filename)
+	assert.EqualValues(t, "two.txt", actual.Attachments[1].Filename)
+	assert.EqualValues(t, large, unpackFileHeaderData(actual.Attachments[1]))
+	assert.EqualValues(t, []string{"Late title"}, req.Form["title"])
+}
+
+func Test_MultipartFileRules(t *testing.T) {
+	type upload struct {
+		Avatar *multipart.FileHeader   `form:"avatar" binding:"Required;MaxFileSize(1KB);FileType(image/png,image/jpeg)"`
+		Scans  []*multipart.FileHeader `form:"scan" binding:"MaxSize(2);FileType(image/*,application/pdf)"`
+	}
+
+	type file struct{ field, contentType, data string }
+	newRequest := func(files ...file) *http.Request {
+		body := &bytes.Buffer{}
+		w := multipart.NewWriter(body)
+		for i, f := range files {
+			h := make(textproto.MIMEHeader)
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="file%d"`, f.field, i))
+			h.Set("Content-Type", f.contentType)
+			part, _ := w.CreatePart(h)
+			part.Write([]byte(f.data))
+		}
+		w.Close()
+		req, _ := http.NewRequest("POST", "/", body)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		return req
+	}
+
+	const (
+		png  = "\x89PNG\r\n\x1a\n"
+		jpeg = "\xff\xd8\xff\xe0"
+		pdf  = "%PDF-1.7\n"
+		gif  = "GIF89a"
+	)
+
+	var actual upload
+	errs := Bind(newRequest(file{"avatar", "image/png", png}, file{"scan", "image/jpeg", jpeg}, file{"scan", "application/pdf", pdf}), &actual)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, len(png), actual.Avatar.Size)
+	assert.EqualValues(t, "image/png", actual.Avatar.Header.Get("Content-Type"))
+	assert.Len(t, actual.Scans, 2)
+
+	// The type is sniffed from the content, whatever the client declares.
+	actual = upload{}
+	assert.Empty(t, Bind(newRequest(file{"avatar", "application/octet-stream", png}), &actual))
+
+	for _, test := range []struct {
+		files          []file
+		field          string
+		classification string
+	}{
+		{[]file{{"scan", "application/pdf", pdf}}, "Avatar", ERR_REQUIRED},
+		{[]file{{"avatar", "image/png", png + strings.Repeat("x", 1025)}}, "Avatar", ERR_FILE_SIZE},
+		{[]file{{"avatar", "image/gif", gif}}, "Avatar", ERR_FILE_TYPE},
+		{[]file{{"avatar", "image/png", "<?php echo 1; ?>"}}, "Avatar", ERR_FILE_TYPE},
+		{[]file{{"avatar", "image/png", png}, {"scan", "image/png", png}, {"scan", "text/plain", "txt"}}, "Scans", ERR_FILE_TYPE},
+		{[]file{{"avatar", "image/png", png}, {"scan", "image/png", png}, {"scan", "image/png", png}, {"scan", "image/png", png}}, "Scans", ERR_MAX_SIZE},
+	} {
+		actual = upload{}
+		errs := Bind(newRequest(test.files...), &actual)
+		if assert.Len(t, errs, 1) {
+			assert.EqualValues(t, []string{test.field}, errs[0].FieldNames)
+			assert.EqualValues(t, test.classification, errs[0].Classification)
+		}
+	}
+}
+
+func Test_ParseByteSize(t *testing.T) {
+	for s, size := range map[string]int64{"512": 512, "64KB": 64 << 10, " 5mb ": 5 << 20, "1GB": 1 << 30} {
+		n, err := parseByteSize(s)
+		assert.NoError(t, err, s)
+		assert.EqualValues(t, size, n, s)
+	}
+	for _, s := range []string{"", "5M", "1.5MB", "-1KB", "MB", "9999999999GB"} {
+		_, err := parseByteSize(s)
+		assert.Error(t, err, s)
+	}
+
+	type upload struct {
+		Avatar *multipart.FileHeader `form:"avatar" binding:"MaxFileSize(5M)"`
+	}
+	assert.PanicsWithValue(t, `binding: invalid tags of binding.upload.Avatar: MaxFileSize(5M): invalid byte size "5M"`, func() {
+		RawValidate(upload{})
+	})
+}
+
+func Test_MultipartFormTruncated(t *testing.T) {
+	type upload struct {
+		Title string `form:"title"`
+	}
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	w.WriteField("title", "Cut short")
+	part, _ := w.CreateFormFile("attachment", "file.txt")
+	part.Write([]byte("partial"))
+
+	req, _ := http.NewRequest("POST", "/", bytes.NewReader(body.Bytes()))
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	var actual upload
+	errs := MultipartForm(req, &actual)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+	assert.Empty(t, actual.Title)
+}
+
+func Test_MultipartFormJSONPart(t *testing.T) {
+	type metadata struct {
+		Title string   `json:"title" binding:"Required"`
+		Tags  []string `json:"tags"`
+	}
+	type upload struct {
+		Metadata metadata              `form:"metadata,json"`
+		Extra    *metadata             `form:"extra,json"`
+		File     *multipart.FileHeader `form:"file" binding:"Required"`
+	}
+
+	newRequest := func(metadata, extra string) *http.Request {
+		body := &bytes.Buffer{}
+		w := multipart.NewWriter(body)
+		part, _ := w.CreateFormFile("file", "photo.jpg")
+		part.Write([]byte("jpeg"))
+		w.WriteField("metadata", metadata)
+		if len(extra) > 0 {
+			part, _ = w.CreateFormFile("extra", "extra.json")
+			part.Write([]byte(extra))
+		}
+		w.Close()
+		req, _ := http.NewRequest("POST", "/", body)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		return withState(req)
+	}
+
+	var actual upload
+	req := newRequest(`{"title":"Holiday","tags":["beach"]}`, `{"title":"More"}`)
+	errs := MultipartForm(req, &actual)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, metadata{Title: "Holiday", Tags: []string{"beach"}}, actual.Metadata)
+	assert.EqualValues(t, &metadata{Title: "More"}, actual.Extra)
+	assert.EqualValues(t, "photo.jpg", actual.File.Filename)
+	assert.EqualValues(t, []string{"Metadata", "Metadata.Title", "Metadata.Tags", "Extra", "Extra.Title", "File"}, Provided(req))
+
+	actual = upload{}
+	errs = MultipartForm(newRequest(`{"tags":["beach"]}`, ""), &actual)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, []string{"Title"}, errs[0].FieldNames)
+	assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+
+	actual = upload{}
+	errs = MultipartForm(newRequest(`{"title":`, ""), &actual)
+	assert.NotEmpty(t, errs)
+	assert.EqualValues(t, []string{"metadata"}, errs[0].FieldNames)
+	assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+}
+
 // Writes the input from a test case into a buffer using the multipart writer.
 func makeMultipartPayload(testCase multipartFormTestCase) (*bytes.Buffer, *multipart.Writer) {
 	body := &bytes.Buffer{}
@@ -0,0 +1,45 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+)
+
+// MustBind binds req into a new T like Bind. On failure it writes the
+// error response using the ErrorResponder of the request's Binder and returns false,
+// in which case the handler should simply return:
+//
+//	form, ok := binding.MustBind[CreateForm](w, r)
+//	if !ok {
+//		return
+//	}
+func MustBind[T any](rw http.ResponseWriter, req *http.Request) (T, bool) {
+	var obj T
+	if errs := Bind(req, &obj); len(errs) > 0 {
+		RespondErrors(rw, req, errs)
+		return obj, false
+	}
+	return obj, true
+}
+
+// ShouldBind binds req into a new T like Bind and returns the errors.
+// Like Bind, and unlike MustBind, it never writes to the response, so the
+// caller remains in control of all output.
+func ShouldBind[T any](req *http.Request) (T, Errors) {
+	var obj T
+	errs := Bind(req, &obj)
+	return obj, errs
+}
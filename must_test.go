@@ -0,0 +1,85 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MustBind(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	rw := httptest.NewRecorder()
+	post, ok := MustBind[Post](rw, newRequest(`{"title":"Hello, world"}`))
+	assert.True(t, ok)
+	assert.EqualValues(t, "Hello, world", post.Title)
+	assert.EqualValues(t, http.StatusOK, rw.Code)
+	assert.Empty(t, rw.Body.String())
+
+	rw = httptest.NewRecorder()
+	_, ok = MustBind[Post](rw, newRequest(`{"title":`))
+	assert.False(t, ok)
+	assert.EqualValues(t, http.StatusBadRequest, rw.Code)
+	assert.EqualValues(t, _JSON_CONTENT_TYPE, rw.Header().Get("Content-Type"))
+
+	defer SetErrorResponder(errorResponder)
+	SetErrorResponder(func(rw http.ResponseWriter, req *http.Request, errs Errors) {
+		http.Error(rw, errs[0].Message, http.StatusTeapot)
+	})
+	rw = httptest.NewRecorder()
+	_, ok = MustBind[Post](rw, newRequest(`{}`))
+	assert.False(t, ok)
+	assert.EqualValues(t, http.StatusTeapot, rw.Code)
+	assert.EqualValues(t, "Required\n", rw.Body.String())
+}
+
+func Test_ShouldBind(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"title":"Hello, world"}`))
+	req.Header.Set("Content-Type", "application/json")
+	post, errs := ShouldBind[Post](req)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "Hello, world", post.Title)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	_, errs = ShouldBind[Post](req)
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+}
+
+func Test_MustBindBinder(t *testing.T) {
+	b := New(WithErrorResponder(func(rw http.ResponseWriter, req *http.Request, errs Errors) {
+		rw.WriteHeader(http.StatusTeapot)
+	}))
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	var ok bool
+	rw := httptest.NewRecorder()
+	b.Handler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, ok = MustBind[Post](rw, req)
+	})).ServeHTTP(rw, req)
+	assert.False(t, ok)
+	assert.EqualValues(t, http.StatusTeapot, rw.Code)
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/netip"
+)
+
+func init() {
+	AddConverter(netip.Addr{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return netip.Addr{}, nil
+		}
+		addr, err := netip.ParseAddr(val)
+		if err != nil {
+			return nil, Error{Classification: ERR_IP, Message: "Value could not be parsed as IP address"}
+		}
+		return addr, nil
+	})
+	AddConverter(netip.Prefix{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return netip.Prefix{}, nil
+		}
+		prefix, err := netip.ParsePrefix(val)
+		if err != nil {
+			return nil, Error{Classification: ERR_CIDR, Message: "Value could not be parsed as CIDR prefix"}
+		}
+		return prefix, nil
+	})
+}
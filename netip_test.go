@@ -0,0 +1,71 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type firewallRule struct {
+	Source  netip.Prefix `form:"source" json:"source" binding:"Required"`
+	Target  netip.Addr   `form:"target" json:"target" binding:"Required"`
+	Gateway *netip.Addr  `form:"gateway" json:"gateway"`
+}
+
+func Test_NetIP(t *testing.T) {
+	t.Run("Form", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/?source=10.0.0.0/8&target=2001:db8::1&gateway=10.0.0.1", nil)
+		var rule firewallRule
+		errs := Form(req, &rule)
+		assert.Empty(t, errs)
+		assert.EqualValues(t, netip.MustParsePrefix("10.0.0.0/8"), rule.Source)
+		assert.EqualValues(t, netip.MustParseAddr("2001:db8::1"), rule.Target)
+		assert.EqualValues(t, netip.MustParseAddr("10.0.0.1"), *rule.Gateway)
+
+		req, _ = http.NewRequest("GET", "/?source=10.0.0.0/33&target=10.0.0.256", nil)
+		rule = firewallRule{}
+		errs = Form(req, &rule)
+		assert.True(t, errs.Has(ERR_CIDR))
+		assert.True(t, errs.Has(ERR_IP))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"source":"192.168.0.0/16","target":"192.168.1.1"}`))
+		var rule firewallRule
+		errs := JSON(req, &rule)
+		assert.Empty(t, errs)
+
+		req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"target":"localhost"}`))
+		errs = JSON(req, &rule)
+		assert.True(t, errs.Has(ERR_IP))
+	})
+}
+
+func Test_NetIPRules(t *testing.T) {
+	type rule struct {
+		Source netip.Prefix `binding:"CIDR"`
+		Target netip.Addr   `binding:"IPv6"`
+	}
+	assert.Empty(t, RawValidate(rule{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParseAddr("2001:db8::1")}))
+	errs := RawValidate(rule{Target: netip.MustParseAddr("10.0.0.1")})
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_IP, errs[0].Classification)
+	}
+}
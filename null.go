@@ -0,0 +1,128 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// NullBool and the database/sql null types can be bound from form values
+// and JSON scalars; a submitted value sets Valid. Empty values of the
+// numeric, boolean and time types, and JSON nulls, are treated as NULL,
+// while an empty NullString is a valid empty string.
+func init() {
+	AddConverter(NullBool{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return NullBool{}, nil
+		}
+		b, err := parseBool(val)
+		if err != nil {
+			return nil, Error{Classification: ERR_BOOLEAN_TYPE, Message: "Value could not be parsed as boolean"}
+		}
+		return NullBool{Bool: b, Valid: true}, nil
+	})
+	AddConverter(sql.NullString{}, func(val string) (interface{}, error) {
+		return sql.NullString{String: val, Valid: true}, nil
+	})
+	AddConverter(sql.NullInt64{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return sql.NullInt64{}, nil
+		}
+		n, err := parseNullInt(val, 64)
+		return sql.NullInt64{Int64: n, Valid: true}, err
+	})
+	AddConverter(sql.NullInt32{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return sql.NullInt32{}, nil
+		}
+		n, err := parseNullInt(val, 32)
+		return sql.NullInt32{Int32: int32(n), Valid: true}, err
+	})
+	AddConverter(sql.NullFloat64{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return sql.NullFloat64{}, nil
+		}
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, Error{Classification: ERR_FLOAT_TYPE, Message: "Value could not be parsed as 64-bit float"}
+		}
+		return sql.NullFloat64{Float64: f, Valid: true}, nil
+	})
+	AddConverter(sql.NullBool{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return sql.NullBool{}, nil
+		}
+		b, err := parseBool(val)
+		if err != nil {
+			return nil, Error{Classification: ERR_BOOLEAN_TYPE, Message: "Value could not be parsed as boolean"}
+		}
+		return sql.NullBool{Bool: b, Valid: true}, nil
+	})
+	AddConverter(sql.NullTime{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return sql.NullTime{}, nil
+		}
+		t, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			return nil, Error{Classification: ERR_DESERIALIZATION, Message: "Value could not be parsed as time"}
+		}
+		return sql.NullTime{Time: t, Valid: true}, nil
+	})
+}
+
+func parseNullInt(val string, bitSize int) (int64, error) {
+	n, err := strconv.ParseInt(val, 10, bitSize)
+	if isRangeError(err) {
+		return 0, Error{Classification: ERR_OVERFLOW, Message: "Value overflows int" + strconv.Itoa(bitSize)}
+	} else if err != nil {
+		return 0, Error{Classification: ERR_INTERGER_TYPE, Message: "Value could not be parsed as integer"}
+	}
+	return n, nil
+}
+
+// NullBool is a boolean that can tell "not sent" apart from "false".
+// Valid is true if a value was submitted. As with *bool fields, the
+// Required rule on a NullBool means the value must be present, not that
+// it must be true.
+type NullBool struct {
+	Bool  bool
+	Valid bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler. A JSON null leaves the
+// value invalid.
+func (b *NullBool) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*b = NullBool{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &b.Bool); err != nil {
+		return err
+	}
+	b.Valid = true
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b NullBool) MarshalJSON() ([]byte, error) {
+	if !b.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(b.Bool)
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type triStateForm struct {
+	Subscribed *bool    `form:"subscribed" json:"subscribed" binding:"Required"`
+	Public     NullBool `form:"public" json:"public" binding:"Required"`
+	Count      *int     `form:"count" json:"count"`
+}
+
+func Test_TriStateBool(t *testing.T) {
+	t.Run("Form", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/?subscribed=false&public=false&count=0", nil)
+		var form triStateForm
+		errs := Form(req, &form)
+		assert.Empty(t, errs)
+		if assert.NotNil(t, form.Subscribed) {
+			assert.False(t, *form.Subscribed)
+		}
+		assert.EqualValues(t, NullBool{Bool: false, Valid: true}, form.Public)
+		if assert.NotNil(t, form.Count) {
+			assert.EqualValues(t, 0, *form.Count)
+		}
+	})
+
+	t.Run("Form missing", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/?public=maybe", nil)
+		var form triStateForm
+		errs := Form(req, &form)
+		assert.Len(t, errs, 3)
+		assert.True(t, errs.Has(ERR_BOOLEAN_TYPE))
+		assert.True(t, errs.Has(ERR_REQUIRED))
+		assert.Nil(t, form.Subscribed)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"subscribed":false,"public":true}`))
+		var form triStateForm
+		errs := JSON(req, &form)
+		assert.Empty(t, errs)
+		assert.EqualValues(t, NullBool{Bool: true, Valid: true}, form.Public)
+
+		req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"public":null}`))
+		form = triStateForm{}
+		errs = JSON(req, &form)
+		assert.Len(t, errs, 2)
+	})
+}
+
+func Test_SQLNullTypes(t *testing.T) {
+	type nullForm struct {
+		Name     sql.NullString  `form:"name" binding:"Required"`
+		Age      sql.NullInt64   `form:"age"`
+		Rank     sql.NullInt32   `form:"rank"`
+		Score    sql.NullFloat64 `form:"score"`
+		Active   sql.NullBool    `form:"active"`
+		Birthday sql.NullTime    `form:"birthday"`
+		Missing  sql.NullString  `form:"missing"`
+	}
+
+	req, _ := http.NewRequest("GET", "/?name=&age=0&rank=&score=1.5&active=on&birthday=2020-01-02T03:04:05Z", nil)
+	var form nullForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, sql.NullString{String: "", Valid: true}, form.Name)
+	assert.EqualValues(t, sql.NullInt64{Int64: 0, Valid: true}, form.Age)
+	assert.False(t, form.Rank.Valid)
+	assert.EqualValues(t, sql.NullFloat64{Float64: 1.5, Valid: true}, form.Score)
+	assert.EqualValues(t, sql.NullBool{Bool: true, Valid: true}, form.Active)
+	assert.EqualValues(t, 2020, form.Birthday.Time.Year())
+	assert.False(t, form.Missing.Valid)
+
+	req, _ = http.NewRequest("GET", "/?age=x&rank=3000000000&birthday=yesterday", nil)
+	form = nullForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 4)
+	assert.True(t, errs.Has(ERR_INTERGER_TYPE))
+	assert.True(t, errs.Has(ERR_OVERFLOW))
+	assert.True(t, errs.Has(ERR_DESERIALIZATION))
+	assert.True(t, errs.Has(ERR_REQUIRED))
+}
+
+func Test_SQLNullTypesJSON(t *testing.T) {
+	type nullForm struct {
+		Name   sql.NullString  `json:"name" binding:"Required"`
+		Count  sql.NullInt64   `json:"count"`
+		Rank   *sql.NullInt32  `json:"rank"`
+		Score  sql.NullFloat64 `json:"score"`
+		Active sql.NullBool    `json:"active"`
+		When   sql.NullTime    `json:"when"`
+		Ages   []sql.NullInt64 `json:"ages"`
+		Extra  sql.NullString  `json:"extra"`
+		Nested struct {
+			Count sql.NullInt64 `json:"count"`
+		} `json:"nested"`
+	}
+
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"count":5,"name":"x","rank":7,"score":1.5,"active":true,`+
+		`"when":"2020-01-02T03:04:05Z","ages":[1,null,"3"],"extra":null,"nested":{"count":0}}`))
+	var form nullForm
+	errs := JSON(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, sql.NullString{String: "x", Valid: true}, form.Name)
+	assert.EqualValues(t, sql.NullInt64{Int64: 5, Valid: true}, form.Count)
+	if assert.NotNil(t, form.Rank) {
+		assert.EqualValues(t, sql.NullInt32{Int32: 7, Valid: true}, *form.Rank)
+	}
+	assert.EqualValues(t, sql.NullFloat64{Float64: 1.5, Valid: true}, form.Score)
+	assert.EqualValues(t, sql.NullBool{Bool: true, Valid: true}, form.Active)
+	assert.EqualValues(t, 2020, form.When.Time.Year())
+	assert.EqualValues(t, []sql.NullInt64{{Int64: 1, Valid: true}, {}, {Int64: 3, Valid: true}}, form.Ages)
+	assert.False(t, form.Extra.Valid)
+	assert.EqualValues(t, sql.NullInt64{Int64: 0, Valid: true}, form.Nested.Count)
+
+	// Rejected values are reported on their field, and the rest is bound.
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"count":"many","name":"x","ages":[1,2.5]}`))
+	form = nullForm{}
+	errs = JSON(req, &form)
+	if assert.Len(t, errs, 2) {
+		assert.EqualValues(t, []string{"Count"}, errs[0].FieldNames)
+		assert.EqualValues(t, ERR_INTERGER_TYPE, errs[0].Classification)
+		assert.EqualValues(t, "many", errs[0].Value)
+		assert.EqualValues(t, "sql.NullInt64", errs[0].ExpectedType)
+		assert.EqualValues(t, []string{"Ages[1]"}, errs[1].FieldNames)
+	}
+	assert.EqualValues(t, sql.NullString{String: "x", Valid: true}, form.Name)
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+func init() {
+	ruleParamCheckers["Min"] = func(param string) error {
+		return checkNumberParams(param, 1)
+	}
+	ruleParamCheckers["Max"] = ruleParamCheckers["Min"]
+	ruleParamCheckers["Between"] = func(params string) error {
+		return checkNumberParams(params, 2)
+	}
+}
+
+// checkNumberParams checks that params are n comma separated numbers.
+func checkNumberParams(params string, n int) error {
+	nums := strings.Split(params, ",")
+	if len(nums) != n {
+		return fmt.Errorf("expected %d numbers", n)
+	}
+	for _, num := range nums {
+		if _, ok := new(big.Rat).SetString(strings.TrimSpace(num)); !ok {
+			return fmt.Errorf("invalid number %q", num)
+		}
+	}
+	return nil
+}
+
+// numberValue returns v, an integer, a float, a big.Int or a big.Rat, or a
+// pointer to one, as an exact rational number, which is nil for NaN and
+// infinities. ok is false for values of other types and nil pointers.
+func numberValue(v interface{}) (r *big.Rat, ok bool) {
+	switch v := v.(type) {
+	case big.Int:
+		return new(big.Rat).SetInt(&v), true
+	case *big.Int:
+		if v == nil {
+			return nil, false
+		}
+		return new(big.Rat).SetInt(v), true
+	case big.Rat:
+		return &v, true
+	case *big.Rat:
+		return v, v != nil
+	}
+	val := reflect.Indirect(reflect.ValueOf(v))
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(val.Uint())), true
+	case reflect.Float32, reflect.Float64:
+		return new(big.Rat).SetFloat64(val.Float()), true
+	}
+	return nil, false
+}
+
+// inNumberRange reports whether v lies within min and max, the parameters
+// of rules such as Between(0,0.5); an empty bound is open. NaN and
+// infinities lie within no range. ok is false if v is not a number, see
+// numberValue. It panics if a bound is not a number; the bounds of rules
+// are checked beforehand by checkRules.
+func inNumberRange(v interface{}, min, max string) (in, ok bool) {
+	r, ok := numberValue(v)
+	if !ok {
+		return false, false
+	}
+	if r == nil {
+		return false, true
+	}
+	for i, param := range []string{min, max} {
+		param = strings.TrimSpace(param)
+		if param == "" {
+			continue
+		}
+		bound, valid := new(big.Rat).SetString(param)
+		if !valid {
+			panic("binding: invalid number " + param)
+		}
+		if cmp := r.Cmp(bound); i == 0 && cmp < 0 || i == 1 && cmp > 0 {
+			return false, true
+		}
+	}
+	return true, true
+}
@@ -0,0 +1,35 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NumericRulesMessage(t *testing.T) {
+	defer func(saved map[string]*template.Template) { messageTemplates = saved }(messageTemplates)
+	messageTemplates = map[string]*template.Template{}
+	SetMessageTemplate(ERR_BETWEEN, "{{.Field}} must be between {{.Min}} and {{.Max}}")
+
+	errs := RawValidate(struct {
+		Ratio float64 `binding:"Between(0,1)"`
+	}{2})
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, "Ratio must be between 0 and 1", errs[0].Message)
+	}
+}
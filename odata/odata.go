@@ -0,0 +1,491 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package odata parses a constrained subset of the OData $filter, $orderby
+// and $select query options into an AST, for use by list endpoints that
+// need more than simple equality filters.
+//
+// Only fields named in Options.Fields may be referenced, and filters are
+// limited in nesting depth, so the AST can be translated into a database
+// query without further sanitizing.
+package odata
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gitea.com/go-chi/binding"
+)
+
+const (
+	ERR_SYNTAX = "ODataSyntaxError"
+	ERR_FIELD  = "ODataFieldError"
+	ERR_DEPTH  = "ODataDepthError"
+)
+
+// DefaultMaxDepth is used when Options.MaxDepth is not set.
+const DefaultMaxDepth = 5
+
+type (
+	// Node is a node of a parsed $filter expression. It is one of
+	// *Logical, *Not, *Comparison or *Func.
+	Node interface {
+		node()
+	}
+
+	// Logical combines two expressions with "and" or "or".
+	Logical struct {
+		Op          string
+		Left, Right Node
+	}
+
+	// Not negates an expression.
+	Not struct {
+		Expr Node
+	}
+
+	// Comparison compares a field against a literal using one of
+	// "eq", "ne", "gt", "ge", "lt" or "le".
+	Comparison struct {
+		Field string
+		Op    string
+		Value Value
+	}
+
+	// Func is a call of one of the string functions "contains",
+	// "startswith" or "endswith".
+	Func struct {
+		Name  string
+		Field string
+		Value Value
+	}
+
+	// Value is a literal value of an expression.
+	Value struct {
+		Kind ValueKind
+		// Raw is the literal as written, with string literals unquoted.
+		Raw string
+	}
+
+	// ValueKind is the kind of a literal value.
+	ValueKind int
+
+	// OrderBy is a single item of an $orderby clause.
+	OrderBy struct {
+		Field string
+		Desc  bool
+	}
+
+	// Query holds the parsed query options of a request.
+	Query struct {
+		Filter  Node
+		OrderBy []OrderBy
+		Select  []string
+	}
+
+	// Options restricts what a query may contain.
+	Options struct {
+		// Fields lists the names that may be referenced by the query.
+		Fields []string
+		// MaxDepth limits the nesting of $filter expressions.
+		MaxDepth int
+	}
+)
+
+const (
+	String ValueKind = iota
+	Number
+	Bool
+	Null
+)
+
+func (*Logical) node()    {}
+func (*Not) node()        {}
+func (*Comparison) node() {}
+func (*Func) node()       {}
+
+var (
+	comparisonOps = map[string]bool{"eq": true, "ne": true, "gt": true, "ge": true, "lt": true, "le": true}
+	functions     = map[string]bool{"contains": true, "startswith": true, "endswith": true}
+)
+
+// Bind parses the $filter, $orderby and $select parameters of the request's
+// query string into q.
+func Bind(req *http.Request, q *Query, opts Options) binding.Errors {
+	var errs binding.Errors
+	values := req.URL.Query()
+
+	if filter := values.Get("$filter"); len(filter) > 0 {
+		node, err := ParseFilter(filter, opts)
+		if err != nil {
+			errs.Add([]string{"$filter"}, err.(*Error).Classification, err.Error())
+		} else {
+			q.Filter = node
+		}
+	}
+	if orderBy := values.Get("$orderby"); len(orderBy) > 0 {
+		items, err := ParseOrderBy(orderBy, opts)
+		if err != nil {
+			errs.Add([]string{"$orderby"}, err.(*Error).Classification, err.Error())
+		} else {
+			q.OrderBy = items
+		}
+	}
+	if sel := values.Get("$select"); len(sel) > 0 {
+		fields, err := ParseSelect(sel, opts)
+		if err != nil {
+			errs.Add([]string{"$select"}, err.(*Error).Classification, err.Error())
+		} else {
+			q.Select = fields
+		}
+	}
+	return errs
+}
+
+// Error is returned by the parse functions.
+type Error struct {
+	Classification string
+	Message        string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func syntaxError(format string, args ...interface{}) error {
+	return &Error{ERR_SYNTAX, fmt.Sprintf(format, args...)}
+}
+
+func checkField(name string, opts Options) error {
+	for _, f := range opts.Fields {
+		if f == name {
+			return nil
+		}
+	}
+	return &Error{ERR_FIELD, fmt.Sprintf("Field %q is not allowed", name)}
+}
+
+// ParseSelect parses a comma separated list of field names.
+func ParseSelect(str string, opts Options) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(str, ",") {
+		field = strings.TrimSpace(field)
+		if err := checkField(field, opts); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// ParseOrderBy parses a comma separated list of field names, each
+// optionally followed by "asc" or "desc".
+func ParseOrderBy(str string, opts Options) ([]OrderBy, error) {
+	var items []OrderBy
+	for _, item := range strings.Split(str, ",") {
+		parts := strings.Fields(item)
+		if len(parts) == 0 || len(parts) > 2 {
+			return nil, syntaxError("Invalid $orderby item %q", strings.TrimSpace(item))
+		}
+		if err := checkField(parts[0], opts); err != nil {
+			return nil, err
+		}
+		orderBy := OrderBy{Field: parts[0]}
+		if len(parts) == 2 {
+			switch strings.ToLower(parts[1]) {
+			case "asc":
+			case "desc":
+				orderBy.Desc = true
+			default:
+				return nil, syntaxError("Invalid $orderby direction %q", parts[1])
+			}
+		}
+		items = append(items, orderBy)
+	}
+	return items, nil
+}
+
+// ParseFilter parses a $filter expression.
+func ParseFilter(str string, opts Options) (Node, error) {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultMaxDepth
+	}
+	toks, err := tokenize(str)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, opts: opts}
+	node, err := p.parseOr(1)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, syntaxError("Unexpected %q", p.toks[p.pos].text)
+	}
+	return node, nil
+}
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokString
+	tokNumber
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func isIdentChar(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+		!first && (c >= '0' && c <= '9' || c == '.' || c == '/')
+}
+
+func tokenize(str string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(str); {
+		c := str[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			toks = append(toks, token{tokPunct, string(c)})
+			i++
+		case c == '\'':
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(str) {
+					return nil, syntaxError("Unterminated string literal")
+				}
+				if str[i] == '\'' {
+					// Quotes are escaped by doubling them.
+					if i+1 < len(str) && str[i+1] == '\'' {
+						sb.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteByte(str[i])
+				i++
+			}
+			toks = append(toks, token{tokString, sb.String()})
+		case c == '-' || c >= '0' && c <= '9':
+			start := i
+			i++
+			for i < len(str) && (str[i] >= '0' && str[i] <= '9' || str[i] == '.') {
+				i++
+			}
+			if str[start:i] == "-" {
+				return nil, syntaxError("Invalid number")
+			}
+			toks = append(toks, token{tokNumber, str[start:i]})
+		case isIdentChar(c, true):
+			start := i
+			for i < len(str) && isIdentChar(str[i], false) {
+				i++
+			}
+			toks = append(toks, token{tokIdent, str[start:i]})
+		default:
+			return nil, syntaxError("Unexpected character %q", c)
+		}
+	}
+	return toks, nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+	opts Options
+}
+
+func (p *parser) peek() *token {
+	if p.pos < len(p.toks) {
+		return &p.toks[p.pos]
+	}
+	return nil
+}
+
+func (p *parser) next() (token, error) {
+	if p.pos >= len(p.toks) {
+		return token{}, syntaxError("Unexpected end of expression")
+	}
+	p.pos++
+	return p.toks[p.pos-1], nil
+}
+
+func (p *parser) keyword(word string) bool {
+	if tok := p.peek(); tok != nil && tok.kind == tokIdent && strings.EqualFold(tok.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) error {
+	tok, err := p.next()
+	if err != nil {
+		return err
+	}
+	if tok.kind != tokPunct || tok.text != punct {
+		return syntaxError("Expected %q but got %q", punct, tok.text)
+	}
+	return nil
+}
+
+func (p *parser) checkDepth(depth int) error {
+	if depth > p.opts.MaxDepth {
+		return &Error{ERR_DEPTH, fmt.Sprintf("Expression exceeds maximum depth of %d", p.opts.MaxDepth)}
+	}
+	return nil
+}
+
+func (p *parser) parseOr(depth int) (Node, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "or", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (Node, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "and", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary(depth int) (Node, error) {
+	if p.keyword("not") {
+		if err := p.checkDepth(depth + 1); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+	return p.parsePrimary(depth)
+}
+
+func (p *parser) parsePrimary(depth int) (Node, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok.kind == tokPunct && tok.text == "(" {
+		if err := p.checkDepth(depth + 1); err != nil {
+			return nil, err
+		}
+		node, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+	if tok.kind != tokIdent {
+		return nil, syntaxError("Expected field name but got %q", tok.text)
+	}
+
+	name := strings.ToLower(tok.text)
+	if next := p.peek(); functions[name] && next != nil && next.kind == tokPunct && next.text == "(" {
+		p.pos++
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(","); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if value.Kind != String {
+			return nil, syntaxError("Function %s expects a string argument", name)
+		}
+		return &Func{Name: name, Field: field, Value: value}, p.expect(")")
+	}
+
+	if err := checkField(tok.text, p.opts); err != nil {
+		return nil, err
+	}
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if op.kind != tokIdent || !comparisonOps[strings.ToLower(op.text)] {
+		return nil, syntaxError("Expected comparison operator but got %q", op.text)
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return &Comparison{Field: tok.text, Op: strings.ToLower(op.text), Value: value}, nil
+}
+
+func (p *parser) parseField() (string, error) {
+	tok, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if tok.kind != tokIdent {
+		return "", syntaxError("Expected field name but got %q", tok.text)
+	}
+	return tok.text, checkField(tok.text, p.opts)
+}
+
+func (p *parser) parseValue() (Value, error) {
+	tok, err := p.next()
+	if err != nil {
+		return Value{}, err
+	}
+	switch tok.kind {
+	case tokString:
+		return Value{Kind: String, Raw: tok.text}, nil
+	case tokNumber:
+		return Value{Kind: Number, Raw: tok.text}, nil
+	case tokIdent:
+		switch strings.ToLower(tok.text) {
+		case "true", "false":
+			return Value{Kind: Bool, Raw: strings.ToLower(tok.text)}, nil
+		case "null":
+			return Value{Kind: Null, Raw: "null"}, nil
+		}
+	}
+	return Value{}, syntaxError("Expected literal value but got %q", tok.text)
+}
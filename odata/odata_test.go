@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package odata
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testOptions = Options{Fields: []string{"name", "age", "email"}}
+
+func Test_ParseFilter(t *testing.T) {
+	node, err := ParseFilter("name eq 'O''Brien' and (age ge 18 or not contains(email, '@example.com'))", testOptions)
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Logical{
+		Op:   "and",
+		Left: &Comparison{Field: "name", Op: "eq", Value: Value{Kind: String, Raw: "O'Brien"}},
+		Right: &Logical{
+			Op:    "or",
+			Left:  &Comparison{Field: "age", Op: "ge", Value: Value{Kind: Number, Raw: "18"}},
+			Right: &Not{Expr: &Func{Name: "contains", Field: "email", Value: Value{Kind: String, Raw: "@example.com"}}},
+		},
+	}, node)
+
+	for filter, classification := range map[string]string{
+		"password eq 'x'":              ERR_FIELD,
+		"startswith(password, 'x')":    ERR_FIELD,
+		"name eq":                      ERR_SYNTAX,
+		"name like 'x'":                ERR_SYNTAX,
+		"name eq 'x":                   ERR_SYNTAX,
+		"(name eq 'x'":                 ERR_SYNTAX,
+		"name eq 'x' age eq 1":         ERR_SYNTAX,
+		"((((((age eq 1))))))":         ERR_DEPTH,
+		"not not not not not age eq 1": ERR_DEPTH,
+	} {
+		_, err := ParseFilter(filter, testOptions)
+		if assert.Error(t, err, filter) {
+			assert.EqualValues(t, classification, err.(*Error).Classification, filter)
+		}
+	}
+}
+
+func Test_Bind(t *testing.T) {
+	query := url.Values{
+		"$filter":  {"age lt 30"},
+		"$orderby": {"name desc, age"},
+		"$select":  {"name,email"},
+	}
+	req, err := http.NewRequest("GET", "/users?"+query.Encode(), nil)
+	assert.NoError(t, err)
+
+	var q Query
+	errs := Bind(req, &q, testOptions)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, &Comparison{Field: "age", Op: "lt", Value: Value{Kind: Number, Raw: "30"}}, q.Filter)
+	assert.EqualValues(t, []OrderBy{{Field: "name", Desc: true}, {Field: "age"}}, q.OrderBy)
+	assert.EqualValues(t, []string{"name", "email"}, q.Select)
+
+	req, err = http.NewRequest("GET", "/users?$orderby=name+sideways&$select=secret", nil)
+	assert.NoError(t, err)
+	errs = Bind(req, &Query{}, testOptions)
+	assert.Len(t, errs, 2)
+	assert.True(t, errs.Has(ERR_SYNTAX))
+	assert.True(t, errs.Has(ERR_FIELD))
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"github.com/goccy/go-json"
+)
+
+// Optional wraps a field value and records whether it was present in the
+// payload, which lets PATCH handlers tell "not sent" apart from a zero
+// value. Validation rules apply to Value when the field is present, and
+// Required means the field must be present.
+type Optional[T any] struct {
+	Value T
+	// Present is true if the field was sent.
+	Present bool
+	// Null is true if the field was sent as a JSON null.
+	Null bool
+}
+
+// Some returns a present Optional holding v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Present: true}
+}
+
+// Get returns the value and whether it was present.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Present
+}
+
+func (Optional[T]) isOptional() {}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Present = true
+	if string(data) == "null" {
+		var zero T
+		o.Value, o.Null = zero, true
+		return nil
+	}
+	o.Null = false
+	return json.Unmarshal(data, &o.Value)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Present || o.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type patchUser struct {
+	Name    Optional[string]   `form:"name" json:"name" binding:"Required;MinSize(3)"`
+	Age     Optional[int]      `form:"age" json:"age" binding:"Range(1,150)"`
+	Tags    Optional[[]string] `form:"tag" json:"tags" binding:"MaxSize(2)"`
+	Address Optional[Person]   `json:"address"`
+}
+
+func Test_Optional(t *testing.T) {
+	t.Run("Form", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/?name=Joe&age=0&tag=a&tag=b", nil)
+		var form patchUser
+		errs := Form(req, &form)
+		assert.Empty(t, errs)
+		assert.EqualValues(t, Some("Joe"), form.Name)
+		assert.EqualValues(t, Some(0), form.Age)
+		assert.EqualValues(t, []string{"a", "b"}, form.Tags.Value)
+		assert.False(t, form.Address.Present)
+	})
+
+	t.Run("Form validation", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/?age=200&tag=a&tag=b&tag=c", nil)
+		var form patchUser
+		errs := Form(req, &form)
+		assert.Len(t, errs, 3)
+		assert.True(t, errs.Has(ERR_REQUIRED))
+		assert.True(t, errs.Has(ERR_RANGE))
+		assert.True(t, errs.Has(ERR_MAX_SIZE))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		req, _ := http.NewRequest("PATCH", "/", strings.NewReader(`{"name":"Al","age":null,"address":{"email":"a@b.c"}}`))
+		var form patchUser
+		errs := JSON(req, &form)
+		assert.Len(t, errs, 2)
+		assert.True(t, errs.Has(ERR_MIN_SIZE))
+		assert.True(t, errs.Has(ERR_REQUIRED))
+		assert.True(t, form.Age.Present)
+		assert.True(t, form.Age.Null)
+		assert.False(t, form.Tags.Present)
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	E164Pattern          = regexp.MustCompile(`\A\+[1-9]\d{1,14}\z`)
+	NationalPhonePattern = regexp.MustCompile(`\A\+?[\d\s().-]+\z`)
+)
+
+func init() {
+	ruleParamCheckers["Phone"] = func(format string) error {
+		switch strings.TrimSpace(format) {
+		case "", "e164", "national":
+			return nil
+		}
+		return fmt.Errorf("unknown phone number format %q", format)
+	}
+}
+
+// isPhone reports whether v is a phone number in E.164 format, e.g.
+// "+14155552671". With the format "national", numbers as people write them
+// are also accepted: 7 to 15 digits, optionally after a +, grouped by
+// spaces, hyphens, dots or parentheses, e.g. "(415) 555-2671" or
+// "030 1234567". It panics on other formats, which checkRules reports for
+// rules.
+func isPhone(v interface{}, format string) bool {
+	s := ruleString(v)
+	switch strings.TrimSpace(format) {
+	case "", "e164":
+		return E164Pattern.MatchString(s)
+	case "national":
+		if !NationalPhonePattern.MatchString(s) {
+			return false
+		}
+		digits := strings.Map(func(r rune) rune {
+			if '0' <= r && r <= '9' {
+				return r
+			}
+			return -1
+		}, s)
+		return len(digits) >= 7 && len(digits) <= 15
+	}
+	panic("binding: unknown phone number format " + format)
+}
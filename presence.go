@@ -0,0 +1,243 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// RequirePresence makes Required accept numbers and booleans that were
+// explicitly submitted with their zero value, e.g. "count=0" or
+// {"enabled": false}, while still rejecting fields that were omitted.
+// Strings, slices and other types must still be non-zero. The other rules
+// of the field, such as Min or Range, apply to the submitted zero value.
+var RequirePresence = false
+
+// fieldPaths records the paths of the struct fields present in a request,
+// e.g. "Address.City".
+type fieldPaths struct {
+	prefix string
+	paths  *[]string
+}
+
+func newFieldPaths() fieldPaths {
+	return fieldPaths{paths: new([]string)}
+}
+
+func (p fieldPaths) add(name string) {
+	if p.paths != nil {
+		*p.paths = append(*p.paths, p.prefix+name)
+	}
+}
+
+// path returns the path of the field name.
+func (p fieldPaths) path(name string) string {
+	return p.prefix + name
+}
+
+// len returns the number of paths recorded so far.
+func (p fieldPaths) len() int {
+	if p.paths == nil {
+		return 0
+	}
+	return len(*p.paths)
+}
+
+// addParent records the nested struct name as present, before the paths of
+// its fields, if any were recorded since len returned n.
+func (p fieldPaths) addParent(name string, n int) {
+	if p.len() == n {
+		return
+	}
+	paths := append(*p.paths, "")
+	copy(paths[n+1:], paths[n:])
+	paths[n] = p.prefix + name
+	*p.paths = paths
+}
+
+// at returns the paths of the fields of the nested struct name.
+func (p fieldPaths) at(name string) fieldPaths {
+	return fieldPaths{p.prefix + name + ".", p.paths}
+}
+
+// index returns the paths of the fields of element i of the collection
+// whose fields p holds, e.g. "Items[0]." for "Items.".
+func (p fieldPaths) index(i int) fieldPaths {
+	return fieldPaths{strings.TrimSuffix(p.prefix, ".") + "[" + strconv.Itoa(i) + "].", p.paths}
+}
+
+// jsonPresence returns the paths of the fields of obj present in the JSON
+// document data.
+func jsonPresence(data []byte, obj interface{}) fieldPaths {
+	present := newFieldPaths()
+	var doc interface{}
+	if json.Unmarshal(data, &doc) == nil {
+		addJSONPresence(present, reflect.TypeOf(obj), doc)
+	}
+	return present
+}
+
+func addJSONPresence(present fieldPaths, typ reflect.Type, doc interface{}) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if items, ok := doc.([]interface{}); ok && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) {
+		for i, item := range items {
+			addJSONPresence(present.index(i), typ.Elem(), item)
+		}
+		return
+	}
+	obj, ok := doc.(map[string]interface{})
+	if !ok || typ.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" && field.Anonymous {
+			addJSONPresence(present.at(field.Name), field.Type, obj)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		value, ok := obj[name]
+		if !ok {
+			for key, v := range obj {
+				if strings.EqualFold(key, name) {
+					value, ok = v, true
+					break
+				}
+			}
+		}
+		if ok {
+			present.add(field.Name)
+			addJSONPresence(present.at(field.Name), field.Type, value)
+		}
+	}
+}
+
+// provided holds the fields present in the requests bound into objects.
+// The fields of elements of collections, such as "Items[0].Sku", are
+// only in byObj, for validation, and not in last.
+type provided struct {
+	byObj map[interface{}]map[string]bool
+	last  []string
+}
+
+// setProvided records the fields of obj present in req in its
+// requestState.
+func setProvided(req *http.Request, obj interface{}, present fieldPaths) {
+	s := stateOf(req)
+	if s == nil {
+		return
+	}
+	if s.provided == nil {
+		s.provided = &provided{byObj: make(map[interface{}]map[string]bool)}
+	}
+	p := s.provided
+
+	set := make(map[string]bool, len(*present.paths))
+	p.last = nil
+	for _, path := range *present.paths {
+		set[path] = true
+		if !isElementPath(path) {
+			p.last = append(p.last, path)
+		}
+	}
+	p.byObj[obj] = set
+}
+
+// addProvided records the fields of obj in present as present in req, in
+// addition to those recorded by setProvided.
+func addProvided(req *http.Request, obj interface{}, present fieldPaths) {
+	p := providedOf(req)
+	if p == nil || p.byObj[obj] == nil {
+		setProvided(req, obj, present)
+		return
+	}
+	set := p.byObj[obj]
+	for _, path := range *present.paths {
+		if !set[path] {
+			set[path] = true
+			if !isElementPath(path) {
+				p.last = append(p.last, path)
+			}
+		}
+	}
+}
+
+// isElementPath reports whether path is that of a field of an element of
+// a collection, e.g. "Items[0].Sku".
+func isElementPath(path string) bool {
+	return strings.IndexByte(path, '[') >= 0
+}
+
+// Provided returns the paths of the struct fields that were present in
+// the request last bound from req, e.g. ["Title", "Address.City"], in the
+// order of the struct. Partial updates and audit logs can use it to tell
+// omitted fields from fields submitted with their zero value. It returns
+// nil if nothing was bound from req, and needs Handler.
+func Provided(req *http.Request) []string {
+	p := providedOf(req)
+	if p == nil {
+		return nil
+	}
+	return p.last
+}
+
+// providedFields returns the set of the paths of the fields of obj present
+// in req, or nil if obj was not bound from req.
+func providedFields(req *http.Request, obj interface{}) map[string]bool {
+	p := providedOf(req)
+	if p == nil {
+		return nil
+	}
+	return p.byObj[obj]
+}
+
+// providedOf returns the fields recorded as present in req, or nil.
+func providedOf(req *http.Request) *provided {
+	if s := stateOf(req); s != nil {
+		return s.provided
+	}
+	return nil
+}
+
+// isPresenceKind reports whether Required is satisfied by the presence
+// of a zero value of kind when RequirePresence is set.
+func isPresenceKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
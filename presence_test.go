@@ -0,0 +1,138 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stockForm struct {
+	Count   int    `form:"count" json:"count" binding:"Required"`
+	Enabled bool   `form:"enabled" json:"enabled" binding:"Required"`
+	Note    string `form:"note" json:"note" binding:"Required"`
+	Limits  struct {
+		Max float64 `form:"max" json:"max" binding:"Required"`
+	} `json:"limits"`
+}
+
+func Test_RequirePresence(t *testing.T) {
+	RequirePresence = true
+	defer func() { RequirePresence = false }()
+
+	req, _ := http.NewRequest("GET", "/?count=0&enabled=false&max=0", nil)
+	var form stockForm
+	errs := Form(req, &form)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, []string{"Note"}, errs[0].FieldNames)
+
+	req, _ = http.NewRequest("GET", "/?note=", nil)
+	form = stockForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 4)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"count":0,"ENABLED":false,"note":"n","limits":{"max":0}}`))
+	req.Header.Set("Content-Type", "application/json")
+	form = stockForm{}
+	errs = Bind(req, &form)
+	assert.Empty(t, errs)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"note":"n","limits":{}}`))
+	req.Header.Set("Content-Type", "application/json")
+	form = stockForm{}
+	errs = Bind(req, &form)
+	assert.Len(t, errs, 3)
+
+	RequirePresence = false
+	req, _ = http.NewRequest("GET", "/?count=0&enabled=false&max=0&note=n", nil)
+	form = stockForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 3)
+}
+
+func Test_RequirePresenceRules(t *testing.T) {
+	type order struct {
+		Quantity int    `form:"quantity" binding:"Required;Min(5)"`
+		Rating   int    `form:"rating" binding:"Range(1,5)"`
+		Level    int    `form:"level" binding:"In(1,2,3)"`
+		Note     string `form:"note"`
+	}
+	b := New(WithRequirePresence(true))
+
+	req, _ := http.NewRequest("GET", "/?quantity=0&rating=0&level=0", nil)
+	var form order
+	errs := b.Form(req, &form)
+	if assert.Len(t, errs, 3) {
+		assert.EqualValues(t, ERR_MIN, errs[0].Classification)
+		assert.EqualValues(t, ERR_RANGE, errs[1].Classification)
+		assert.EqualValues(t, ERR_IN, errs[2].Classification)
+	}
+
+	req, _ = http.NewRequest("GET", "/?note=n", nil)
+	form = order{}
+	errs = b.Form(req, &form)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+	}
+}
+
+func Test_RequirePresenceElements(t *testing.T) {
+	type line struct {
+		Qty int    `form:"qty" json:"qty" binding:"Required"`
+		Sku string `form:"sku" json:"sku"`
+	}
+	type orderForm struct {
+		Lines []line `form:"lines" json:"lines"`
+	}
+	b := New(WithRequirePresence(true))
+
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"lines":[{"qty":0},{"sku":"a"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = withState(req)
+	var form orderForm
+	errs := b.Bind(req, &form)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, []string{"Lines[1].Qty"}, errs[0].FieldNames)
+	}
+	assert.EqualValues(t, []string{"Lines"}, Provided(req))
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader("lines[0].qty=0&lines[1].sku=a"))
+	req.Header.Set("Content-Type", formContentType)
+	form = orderForm{}
+	errs = b.Bind(req, &form)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, []string{"Lines[1].Qty"}, errs[0].FieldNames)
+	}
+}
+
+func Test_Provided(t *testing.T) {
+	req, _ := http.NewRequest("PATCH", "/", strings.NewReader(`{"note":"n","limits":{"max":1}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = withState(req)
+	assert.Nil(t, Provided(req))
+	var form stockForm
+	errs := Bind(req, &form)
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, []string{"Note", "Limits", "Limits.Max"}, Provided(req))
+
+	req, _ = http.NewRequest("GET", "/?count=3&max=2", nil)
+	req = withState(req)
+	form = stockForm{}
+	Form(req, &form)
+	assert.EqualValues(t, []string{"Count", "Limits", "Limits.Max"}, Provided(req))
+}
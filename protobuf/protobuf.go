@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package protobuf adds Protocol Buffers bodies to binding.Bind. Import it
+// for its side effects:
+//
+//	import _ "gitea.com/go-chi/binding/protobuf"
+//
+// Bodies with Content-Type application/x-protobuf or application/protobuf
+// are then unmarshaled into the bound struct, which must be a generated
+// proto.Message. Generated messages carry no binding tags; use BindTo to
+// check a message against a struct that has them.
+package protobuf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"gitea.com/go-chi/binding"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	binding.AddBodyDecoder(decode, "application/x-protobuf", "application/protobuf")
+}
+
+func decode(body io.Reader, obj interface{}) error {
+	msg, ok := obj.(proto.Message)
+	if !ok {
+		return fmt.Errorf("protobuf: %T is not a proto.Message", obj)
+	}
+	return unmarshal(body, msg)
+}
+
+func unmarshal(body io.Reader, msg proto.Message) error {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+// Bind binds the Protocol Buffers body of req into msg. Unlike
+// binding.Bind it does not look at the Content-Type.
+func Bind(req *http.Request, msg proto.Message) binding.Errors {
+	return binding.DecodeWith(req, msg, decode)
+}
+
+// BindTo unmarshals the Protocol Buffers body of req into msg, then copies
+// it into obj and validates obj. The copy goes through the JSON mapping of
+// msg with the original proto field names, so the fields of obj are
+// matched by json tags such as `json:"user_id"`. Fields of msg that obj
+// does not have are ignored.
+func BindTo(req *http.Request, msg proto.Message, obj interface{}) binding.Errors {
+	return binding.DecodeWith(req, obj, func(body io.Reader, obj interface{}) error {
+		if err := unmarshal(body, msg); err != nil {
+			return err
+		}
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, obj)
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package protobuf
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitea.com/go-chi/binding"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+func request(t *testing.T, contentType string, msg proto.Message) *http.Request {
+	body, err := proto.Marshal(msg)
+	assert.NoError(t, err)
+	req, _ := http.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return req
+}
+
+func Test_Bind(t *testing.T) {
+	for _, contentType := range []string{"application/x-protobuf", "application/protobuf"} {
+		var method apipb.Method
+		errs := binding.Bind(request(t, contentType, &apipb.Method{Name: "GetUser", RequestStreaming: true}), &method)
+		assert.Empty(t, errs)
+		assert.Equal(t, "GetUser", method.GetName())
+		assert.True(t, method.GetRequestStreaming())
+	}
+
+	var method apipb.Method
+	req, _ := http.NewRequest("POST", "/", strings.NewReader("\xff"))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	errs := binding.Bind(req, &method)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, binding.ERR_DESERIALIZATION, errs[0].Classification)
+	}
+
+	// Plain structs cannot be unmarshaled.
+	var plain struct{ Name string }
+	errs = binding.Bind(request(t, "application/x-protobuf", &apipb.Method{Name: "GetUser"}), &plain)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, binding.ERR_DESERIALIZATION, errs[0].Classification)
+	}
+}
+
+type method struct {
+	Name           string `json:"name" binding:"Required;AlphaDash"`
+	RequestTypeURL string `json:"request_type_url" binding:"Required"`
+}
+
+func Test_BindTo(t *testing.T) {
+	var m method
+	errs := BindTo(request(t, "application/octet-stream", &apipb.Method{Name: "GetUser", RequestTypeUrl: "type.googleapis.com/User"}), &apipb.Method{}, &m)
+	assert.Empty(t, errs)
+	assert.Equal(t, method{Name: "GetUser", RequestTypeURL: "type.googleapis.com/User"}, m)
+
+	m = method{}
+	errs = BindTo(request(t, "application/x-protobuf", &apipb.Method{Name: "Get User"}), &apipb.Method{}, &m)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, binding.ERR_ALPHA_DASH, errs[0].Classification)
+		assert.Equal(t, []string{"Name"}, errs[0].FieldNames)
+		assert.Equal(t, binding.ERR_REQUIRED, errs[1].Classification)
+		assert.Equal(t, []string{"RequestTypeURL"}, errs[1].FieldNames)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// RawBodyLimit is the number of bytes of the request body retained while
+// binding, for RawBody. Audit logging and signature checks in handlers can
+// then use the body without a middleware of their own buffering it ahead
+// of binding. Zero, the default, retains nothing.
+var RawBodyLimit int64
+
+// WithRawBody sets the number of bytes of the request body retained for
+// RawBody; see RawBodyLimit.
+func WithRawBody(limit int64) Option {
+	return func(b *Binder) {
+		b.rawBodyLimit = limit
+	}
+}
+
+// rawBody records up to limit bytes written to it.
+type rawBody struct {
+	buf       bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+func (r *rawBody) Write(p []byte) (int, error) {
+	room := r.limit - int64(r.buf.Len())
+	if int64(len(p)) > room {
+		r.truncated = true
+		p = p[:room]
+	}
+	r.buf.Write(p)
+	return len(p), nil
+}
+
+// captureRawBody makes the body of req be recorded as it is read, if the
+// Binder of req retains raw bodies, in its requestState.
+func captureRawBody(req *http.Request) {
+	s := stateOf(req)
+	limit := binderFrom(req).rawBodyLimit
+	if s == nil || limit <= 0 || req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+	raw := &rawBody{limit: limit}
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(req.Body, raw), req.Body}
+	s.rawBody = raw
+}
+
+// RawBody returns the body of req as read while binding it, and whether
+// it was cut short at the limit set by WithRawBody or RawBodyLimit. It
+// returns nil if the body was not retained. Compressed bodies are returned
+// as sent, before their Content-Encoding is removed. It needs Handler.
+func RawBody(req *http.Request) (body []byte, truncated bool) {
+	s := stateOf(req)
+	if s == nil || s.rawBody == nil {
+		return nil, false
+	}
+	return s.rawBody.buf.Bytes(), s.rawBody.truncated
+}
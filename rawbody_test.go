@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RawBody(t *testing.T) {
+	body := `{"title":"Hello, world","content":"Some content"}`
+	newRequest := func() *http.Request {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return withState(req)
+	}
+
+	// Nothing is retained by default.
+	req := newRequest()
+	var post Post
+	assert.Empty(t, Bind(req, &post))
+	raw, truncated := RawBody(req)
+	assert.Nil(t, raw)
+	assert.False(t, truncated)
+
+	req = newRequest()
+	post = Post{}
+	assert.Empty(t, New(WithRawBody(1024)).Bind(req, &post))
+	raw, truncated = RawBody(req)
+	assert.EqualValues(t, body, string(raw))
+	assert.False(t, truncated)
+	assert.EqualValues(t, "Hello, world", post.Title)
+
+	// The body is still bound in full when it exceeds the limit.
+	req = newRequest()
+	post = Post{}
+	assert.Empty(t, New(WithRawBody(10)).Bind(req, &post))
+	raw, truncated = RawBody(req)
+	assert.EqualValues(t, body[:10], string(raw))
+	assert.True(t, truncated)
+	assert.EqualValues(t, "Some content", post.Content)
+
+	// Verifiers see the same body.
+	req = newRequest()
+	post = Post{}
+	AddBodyVerifier(BodyVerifierFunc(func(req *http.Request, verified []byte) error {
+		assert.EqualValues(t, body, string(verified))
+		return nil
+	}))
+	defer func() { bodyVerifiers = nil }()
+	assert.Empty(t, New(WithRawBody(1024)).Bind(req, &post))
+	raw, _ = RawBody(req)
+	assert.EqualValues(t, body, string(raw))
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// BindReader binds the document read from r into obj and validates it,
+// like Bind does for a POST request with the given Content-Type, e.g.
+// "application/json". It reuses the binding and validation of requests
+// for payloads that do not come from HTTP, such as messages of a queue.
+// Validators are called with a synthetic request.
+func BindReader(r io.Reader, contentType string, obj interface{}) Errors {
+	return Bind(readerRequest(r, contentType), obj)
+}
+
+// BindValues binds values into obj and validates it, like Form does for
+// a request with these form values. Fields are named by their form tags,
+// so that command line flags or parsed configuration can be bound into the
+// same structs as requests.
+func BindValues(values url.Values, obj interface{}) Errors {
+	return Form(valuesRequest(values), obj)
+}
+
+// readerRequest returns a POST request with r as body.
+func readerRequest(r io.Reader, contentType string) *http.Request {
+	req, _ := http.NewRequest("POST", "/", r)
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", contentType)
+	return req
+}
+
+// valuesRequest returns a POST request with values as parsed form.
+func valuesRequest(values url.Values) *http.Request {
+	req, _ := http.NewRequest("POST", "/", http.NoBody)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if values == nil {
+		values = url.Values{}
+	}
+	req.Form, req.PostForm = values, values
+	return req
+}
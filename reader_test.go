@@ -0,0 +1,67 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BindReader(t *testing.T) {
+	var post Post
+	errs := BindReader(strings.NewReader(`{"title":"Hello, world","content":"From a queue"}`), "application/json", &post)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, Post{Title: "Hello, world", Content: "From a queue"}, post)
+
+	post = Post{}
+	errs = BindReader(strings.NewReader("title=Hello, world"), "application/x-www-form-urlencoded", &post)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "Hello, world", post.Title)
+
+	post = Post{}
+	errs = BindReader(strings.NewReader(`{"title":`), "application/json", &post)
+	if assert.NotEmpty(t, errs) {
+		assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+	}
+
+	errs = BindReader(strings.NewReader(`title: Hello`), "text/plain", &post)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_CONTENT_TYPE, errs[0].Classification)
+	}
+}
+
+func Test_BindValues(t *testing.T) {
+	var post Post
+	errs := BindValues(url.Values{"title": {"Hello, world"}, "content": {"From the CLI"}}, &post)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, Post{Title: "Hello, world", Content: "From the CLI"}, post)
+
+	post = Post{}
+	errs = BindValues(nil, &post)
+	if assert.NotEmpty(t, errs) {
+		assert.EqualValues(t, []string{"Title"}, errs[0].FieldNames)
+		assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+	}
+
+	b := New(WithFormLimits(FormLimits{MaxKeys: 1}))
+	post = Post{}
+	errs = b.BindValues(url.Values{"title": {"Hello, world"}, "content": {"Too much"}}, &post)
+	if assert.NotEmpty(t, errs) {
+		assert.EqualValues(t, ERR_LIMIT, errs[0].Classification)
+	}
+}
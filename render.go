@@ -0,0 +1,86 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+type (
+	// RenderBinder adapts a struct to the Binder interface of go-chi/render,
+	// so that render.Bind decodes the request into it and then validates
+	// it with the binding tags, see ForRender.
+	RenderBinder struct {
+		obj interface{}
+	}
+
+	// RenderError is returned by RenderBinder.Bind when validation fails.
+	RenderError struct {
+		Errors Errors
+	}
+)
+
+// ForRender wraps obj, a pointer to a struct, for render.Bind:
+//
+//	var form CreatePostForm
+//	if err := render.Bind(req, binding.ForRender(&form)); err != nil {
+//		...
+//	}
+//
+// Structs implementing render.Binder themselves can validate the same
+// way from their Bind method:
+//
+//	func (f *CreatePostForm) Bind(req *http.Request) error {
+//		return binding.ForRender(f).Bind(req)
+//	}
+func ForRender(obj interface{}) *RenderBinder {
+	return &RenderBinder{obj: obj}
+}
+
+// Bind validates the wrapped struct. It returns a *RenderError holding
+// the validation errors, if any.
+func (b *RenderBinder) Bind(req *http.Request) error {
+	if errs := Validate(req, b.obj); len(errs) > 0 {
+		return &RenderError{Errors: errs}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes data into the wrapped struct.
+func (b *RenderBinder) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, b.obj)
+}
+
+// UnmarshalXML decodes the element into the wrapped struct.
+func (b *RenderBinder) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement(b.obj, &start)
+}
+
+// Error returns the messages of the validation errors.
+func (e *RenderError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		if len(err.FieldNames) > 0 {
+			messages[i] = strings.Join(err.FieldNames, ", ") + ": " + err.Message
+		} else {
+			messages[i] = err.Message
+		}
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type renderForm struct {
+	Title string   `json:"title" xml:"title" binding:"Required"`
+	Tags  []string `json:"tags" xml:"tag" binding:"MaxSize(2)"`
+}
+
+func (f *renderForm) Bind(req *http.Request) error {
+	return ForRender(f).Bind(req)
+}
+
+func Test_ForRender(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+
+	// render.Bind decodes with encoding/json and then calls Bind.
+	var form renderForm
+	binder := ForRender(&form)
+	assert.NoError(t, json.Unmarshal([]byte(`{"title":"Hello","tags":["a"]}`), binder))
+	assert.NoError(t, binder.Bind(req))
+	assert.EqualValues(t, renderForm{Title: "Hello", Tags: []string{"a"}}, form)
+
+	form = renderForm{}
+	assert.NoError(t, xml.Unmarshal([]byte(`<post><tag>a</tag><tag>b</tag><tag>c</tag></post>`), binder))
+	err := binder.Bind(req)
+	if assert.IsType(t, &RenderError{}, err) {
+		errs := err.(*RenderError).Errors
+		assert.Len(t, errs, 2)
+		assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+		assert.EqualValues(t, ERR_MAX_SIZE, errs[1].Classification)
+	}
+	assert.EqualValues(t, "Title: Required; Tags: MaxSize", err.Error())
+
+	form = renderForm{Title: "Hello"}
+	assert.NoError(t, form.Bind(req))
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"reflect"
+
+	chi "github.com/go-chi/chi/v5"
+)
+
+// urlParam returns the value of the chi URL parameter key of req, and
+// whether it was matched. Routers mounted with Mount share the routing
+// context of their parent, so parameters declared by parent routers are
+// found as well; the innermost one wins if a name is declared twice.
+func urlParam(req *http.Request, key string) (string, bool) {
+	rctx := chi.RouteContext(req.Context())
+	if rctx == nil {
+		return "", false
+	}
+	params := rctx.URLParams
+	for i := len(params.Keys) - 1; i >= 0; i-- {
+		if params.Keys[i] == key {
+			return params.Values[i], true
+		}
+	}
+	return "", false
+}
+
+// RoutePattern returns the full pattern of the chi route matched by req,
+// across mounted routers, e.g. "/orgs/{org}/repos/{id}", or "" if req was
+// not routed by chi. It is meant for diagnostics, such as error logs of
+// requests that failed to bind. Binding also sets string fields tagged
+// route:"pattern" to it, e.g. for structs logging or measuring requests:
+//
+//	type auditEntry struct {
+//		Route  string `form:"-" route:"pattern"`
+//		Reason string `form:"reason"`
+//	}
+func RoutePattern(req *http.Request) string {
+	rctx := chi.RouteContext(req.Context())
+	if rctx == nil {
+		return ""
+	}
+	return rctx.RoutePattern()
+}
+
+// bindRoute binds the fields of obj tagged with param, e.g. param:"id",
+// from the chi URL parameters of req, and sets the string fields tagged
+// with route:"pattern" to the route pattern of req.
+func bindRoute(req *http.Request, obj interface{}, errors Errors) Errors {
+	if chi.RouteContext(req.Context()) == nil {
+		return errors
+	}
+	present := newFieldPaths()
+	errors = mapTagged(reflect.ValueOf(obj), "param", func(name string) ([]string, bool) {
+		value, ok := urlParam(req, name)
+		return []string{value}, ok
+	}, SOURCE_PATH, present, binderFrom(req), errors)
+	if present.len() > 0 {
+		addProvided(req, obj, present)
+	}
+	setRoutePattern(reflect.ValueOf(obj), RoutePattern(req))
+	return errors
+}
+
+func setRoutePattern(v reflect.Value, pattern string) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		if field.Tag.Get("route") == "pattern" && field.Type.Kind() == reflect.String && v.Field(i).CanSet() {
+			v.Field(i).SetString(pattern)
+			continue
+		}
+		setRoutePattern(v.Field(i), pattern)
+	}
+}
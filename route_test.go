@@ -0,0 +1,138 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	chi "github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MountedRoutes(t *testing.T) {
+	var (
+		pattern string
+		params  = make(map[string]string)
+	)
+	repos := chi.NewRouter()
+	repos.Get("/repos/{id}", func(rw http.ResponseWriter, req *http.Request) {
+		pattern = RoutePattern(req)
+		for _, key := range []string{"org", "id", "missing"} {
+			if value, ok := urlParam(req, key); ok {
+				params[key] = value
+			}
+		}
+	})
+	m := chi.NewRouter()
+	m.Mount("/orgs/{org}", repos)
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/orgs/gitea/repos/42", nil))
+	assert.EqualValues(t, "/orgs/{org}/repos/{id}", pattern)
+	assert.EqualValues(t, map[string]string{"org": "gitea", "id": "42"}, params)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	assert.EqualValues(t, "", RoutePattern(req))
+	_, ok := urlParam(req, "id")
+	assert.False(t, ok)
+}
+
+func Test_RoutePatternField(t *testing.T) {
+	type request struct {
+		Route  string `form:"-" json:"route" route:"pattern"`
+		Reason string `form:"reason" json:"reason"`
+		Meta   *struct {
+			Route string `json:"route" route:"pattern"`
+		} `json:"meta"`
+	}
+	var got request
+	m := chi.NewRouter()
+	m.Post("/users/{id}/posts/{postID}", func(rw http.ResponseWriter, req *http.Request) {
+		assert.Empty(t, Bind(req, &got))
+	})
+
+	req := httptest.NewRequest("POST", "/users/1/posts/2", strings.NewReader(`{"route":"/spoofed","reason":"r","meta":{}}`))
+	req.Header.Set("Content-Type", "application/json")
+	m.ServeHTTP(httptest.NewRecorder(), req)
+	assert.EqualValues(t, "/users/{id}/posts/{postID}", got.Route)
+	assert.EqualValues(t, "r", got.Reason)
+	if assert.NotNil(t, got.Meta) {
+		assert.EqualValues(t, "/users/{id}/posts/{postID}", got.Meta.Route)
+	}
+
+	// Requests not routed by chi leave the fields alone.
+	got = request{}
+	req = httptest.NewRequest("POST", "/", strings.NewReader(`{"route":"/sent"}`))
+	req.Header.Set("Content-Type", "application/json")
+	assert.Empty(t, Bind(req, &got))
+	assert.EqualValues(t, "/sent", got.Route)
+}
+
+func Test_ParamTag(t *testing.T) {
+	type comment struct {
+		Org     string `form:"-" param:"org"`
+		PostID  int64  `form:"-" param:"postID" binding:"Required"`
+		Draft   *bool  `form:"-" param:"draft"`
+		Content string `form:"content" json:"content"`
+		Ref     struct {
+			ID uint `form:"-" json:"id" param:"postID"`
+		} `json:"ref"`
+	}
+	var (
+		got  comment
+		errs Errors
+		req  *http.Request
+	)
+	handler := func(bind func(*http.Request, interface{}) Errors) http.HandlerFunc {
+		return func(rw http.ResponseWriter, r *http.Request) {
+			got, req = comment{}, r
+			errs = bind(r, &got)
+		}
+	}
+	posts := chi.NewRouter()
+	posts.Post("/posts/{postID}/comments", handler(Bind))
+	posts.Put("/posts/{postID}/comments", handler(Form))
+	m := chi.NewRouter()
+	m.Use(Handler)
+	m.Mount("/orgs/{org}", posts)
+
+	r := httptest.NewRequest("POST", "/orgs/gitea/posts/42/comments", strings.NewReader(`{"content":"Nice","ref":{"id":7}}`))
+	r.Header.Set("Content-Type", "application/json")
+	m.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "gitea", got.Org)
+	assert.EqualValues(t, 42, got.PostID)
+	assert.Nil(t, got.Draft)
+	assert.EqualValues(t, "Nice", got.Content)
+	assert.EqualValues(t, 42, got.Ref.ID)
+	assert.EqualValues(t, []string{"Content", "Ref", "Ref.ID", "Org", "PostID"}, Provided(req))
+
+	r = httptest.NewRequest("PUT", "/orgs/gitea/posts/x/comments", strings.NewReader("content=Nice"))
+	r.Header.Set("Content-Type", formContentType)
+	m.ServeHTTP(httptest.NewRecorder(), r)
+	assert.EqualValues(t, "Nice", got.Content)
+	if assert.Len(t, errs, 3) {
+		assert.EqualValues(t, ERR_INTERGER_TYPE, errs[0].Classification)
+		assert.EqualValues(t, []string{"postID"}, errs[0].FieldNames)
+		assert.EqualValues(t, SOURCE_PATH, errs[0].Source)
+		assert.EqualValues(t, "x", errs[0].Value)
+		assert.EqualValues(t, ERR_INTERGER_TYPE, errs[1].Classification)
+		assert.EqualValues(t, []string{"postID"}, errs[1].FieldNames)
+		assert.EqualValues(t, ERR_REQUIRED, errs[2].Classification)
+		assert.EqualValues(t, []string{"PostID"}, errs[2].FieldNames)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldRules are the rules of a struct field defined outside of its
+// binding tag.
+type FieldRules struct {
+	// Rules, if not empty, replace the rules of the binding tag.
+	Rules string `yaml:"rules"`
+	// Append adds rules to those of the binding tag or Rules.
+	Append string `yaml:"append"`
+}
+
+// UnmarshalYAML allows a plain string as a shorthand for Rules.
+func (r *FieldRules) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&r.Rules)
+	}
+	type plain FieldRules
+	return node.Decode((*plain)(r))
+}
+
+// externalRules holds the map[string]map[string]FieldRules loaded last,
+// swapped atomically so rules can be reloaded while requests are served.
+var externalRules atomic.Value
+
+// LoadRules loads validation rules from a YAML or JSON document, replacing
+// rules loaded before. The document maps type names, as printed by %T
+// without the leading *, to field names and their rules, e.g.
+//
+//	models.CreateIssue:
+//	  Title: "Required;MaxSize(120)"
+//	  Labels:
+//	    append: "MaxSize(10)"
+//
+// Rules loaded this way augment or override the binding tags of the
+// fields, so limits can be changed without changing code. LoadRules may
+// be called at any time to reload the rules; if the document is invalid,
+// the rules in use are kept.
+func LoadRules(data []byte) error {
+	var rules map[string]map[string]FieldRules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return err
+	}
+	for typ, fields := range rules {
+		for field, r := range fields {
+			if err := checkRules(r.Rules + ";" + r.Append); err != nil {
+				return fmt.Errorf("%s.%s: %v", typ, field, err)
+			}
+		}
+	}
+	externalRules.Store(rules)
+	return nil
+}
+
+// LoadRulesFile loads validation rules from the named file, see LoadRules.
+func LoadRulesFile(name string) error {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	return LoadRules(data)
+}
+
+// ruleParamCheckers check the parameters of rules by rule name, e.g. that
+// the bound of Min is a number, so that mistakes are found by checkRules.
+var ruleParamCheckers = map[string]func(params string) error{}
+
+// checkRules checks that all rule sets referred to by rules are defined
+// and that the parameters of the rules are valid.
+func checkRules(rules string) error {
+	for _, rule := range splitRules(rules) {
+		if strings.HasPrefix(rule, "@") {
+			set, ok := ruleSets[rule[1:]]
+			if !ok {
+				return fmt.Errorf("unknown rule set %s", rule)
+			}
+			if err := checkRules(strings.Join(set, ";")); err != nil {
+				return fmt.Errorf("%s: %v", rule, err)
+			}
+			continue
+		}
+		name := ruleName(rule)
+		check, ok := ruleParamCheckers[name]
+		if !ok || name == rule {
+			continue
+		}
+		if !strings.HasSuffix(rule, ")") {
+			return fmt.Errorf("%s: missing )", rule)
+		}
+		if err := check(rule[len(name)+1 : len(rule)-1]); err != nil {
+			return fmt.Errorf("%s: %v", rule, err)
+		}
+	}
+	return nil
+}
+
+// fieldTagCheckers check the tags of struct fields other than binding,
+// e.g. that the time zone of a time_location tag exists.
+var fieldTagCheckers []func(field reflect.StructField) error
+
+// checkedTypes records the struct types whose rules have been checked.
+var checkedTypes sync.Map
+
+// checkTypeRules checks the rules and other tags of the fields of struct
+// type typ, and of the struct types it holds, the first time it is bound or validated. An
+// invalid rule panics there, whatever the values of the fields, rather
+// than when a value happens to reach it.
+func checkTypeRules(typ reflect.Type) {
+	if _, ok := checkedTypes.Load(typ); ok {
+		return
+	}
+	checkStructRules(typ, map[reflect.Type]bool{})
+	checkedTypes.Store(typ, true)
+}
+
+func checkStructRules(typ reflect.Type, seen map[reflect.Type]bool) {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || seen[typ] {
+		return
+	}
+	seen[typ] = true
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if err := checkRules(tagRules(typ, field)); err != nil {
+			panic(fmt.Sprintf("binding: invalid tags of %s.%s: %v", typ, field.Name, err))
+		}
+		for _, check := range fieldTagCheckers {
+			if err := check(field); err != nil {
+				panic(fmt.Sprintf("binding: invalid tags of %s.%s: %v", typ, field.Name, err))
+			}
+		}
+		checkStructRules(field.Type, seen)
+	}
+}
+
+// tagRules returns the rules of the binding tag of field of struct typ,
+// with any externally loaded rules applied.
+func tagRules(typ reflect.Type, field reflect.StructField) string {
+	tag := field.Tag.Get("binding")
+	rules, _ := externalRules.Load().(map[string]map[string]FieldRules)
+	ext, ok := rules[typ.String()][field.Name]
+	if !ok {
+		return tag
+	}
+	if ext.Rules != "" {
+		tag = ext.Rules
+	}
+	if ext.Append != "" {
+		if tag != "" {
+			tag += ";"
+		}
+		tag += ext.Append
+	}
+	return tag
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type issueForm struct {
+	Title  string   `form:"title" binding:"Required;MaxSize(100)"`
+	Labels []string `form:"labels"`
+	State  string   `form:"state" binding:"In(open,closed)"`
+}
+
+func Test_LoadRules(t *testing.T) {
+	defer externalRules.Store(map[string]map[string]FieldRules(nil))
+
+	assert.NoError(t, LoadRules([]byte(`
+binding.issueForm:
+  Title: "Required;MaxSize(5)"
+  Labels:
+    append: "MaxSize(2)"
+  State:
+    rules: "In(open,closed,locked)"
+`)))
+
+	req, _ := http.NewRequest("GET", "/?title=Broken+build&labels=a&labels=b&labels=c&state=locked", nil)
+	var form issueForm
+	errs := Form(req, &form)
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, ERR_MAX_SIZE, errs[0].Classification)
+	assert.EqualValues(t, []string{"Title"}, errs[0].FieldNames)
+	assert.EqualValues(t, ERR_MAX_SIZE, errs[1].Classification)
+	assert.EqualValues(t, []string{"Labels"}, errs[1].FieldNames)
+
+	assert.NoError(t, LoadRules([]byte(`{"binding.issueForm": {"State": {"append": "NotIn(locked)"}}}`)))
+	form = issueForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, ERR_IN, errs[0].Classification)
+
+	assert.Error(t, LoadRules([]byte(`binding.issueForm: [`)))
+	assert.Error(t, LoadRules([]byte(`binding.issueForm: {Title: "@missing"}`)))
+	form = issueForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 1)
+}
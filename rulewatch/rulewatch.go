@@ -0,0 +1,95 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package rulewatch reloads external validation rules whenever their file
+// changes, so limits can be adjusted without restarting the service:
+//
+//	w, err := rulewatch.Watch("rules.yaml", func(err error) {
+//		log.Printf("Keeping previous validation rules: %v", err)
+//	})
+//	defer w.Close()
+//
+// Each change is loaded with binding.LoadRulesFile, which swaps the rules
+// atomically and keeps the rules in use if the new file is invalid.
+package rulewatch
+
+import (
+	"path/filepath"
+
+	"gitea.com/go-chi/binding"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher watches a rule file for changes.
+type Watcher struct {
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// Watch loads the rules of the named file and reloads them whenever the
+// file is written, created or replaced. Errors that occur while reloading
+// are passed to onError, which may be nil.
+func Watch(name string, onError func(error)) (*Watcher, error) {
+	if err := binding.LoadRulesFile(name); err != nil {
+		return nil, err
+	}
+
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// Watch the directory, as editors and config management tools often
+	// replace files instead of writing to them.
+	if err = fw.Add(filepath.Dir(name)); err != nil {
+		fw.Close()
+		return nil, err
+	}
+
+	w := &Watcher{watcher: fw, done: make(chan struct{})}
+	go w.run(filepath.Clean(name), onError)
+	return w, nil
+}
+
+func (w *Watcher) run(name string, onError func(error)) {
+	defer close(w.done)
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != name ||
+				event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if err := binding.LoadRulesFile(name); err != nil && onError != nil {
+				onError(err)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			if onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Close stops watching the rule file. The rules loaded last stay in use.
+func (w *Watcher) Close() error {
+	err := w.watcher.Close()
+	<-w.done
+	return err
+}
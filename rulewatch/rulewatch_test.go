@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package rulewatch
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitea.com/go-chi/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+type noteForm struct {
+	Text string `form:"text" binding:"MaxSize(100)"`
+}
+
+func bindNote() binding.Errors {
+	req, _ := http.NewRequest("GET", "/?text=hello+world", nil)
+	var form noteForm
+	return binding.Form(req, &form)
+}
+
+func Test_Watch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rulewatch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "rules.yaml")
+	assert.NoError(t, ioutil.WriteFile(name, []byte(`rulewatch.noteForm: {Text: "MaxSize(20)"}`), 0644))
+
+	errc := make(chan error, 10)
+	w, err := Watch(name, func(err error) { errc <- err })
+	assert.NoError(t, err)
+	defer w.Close()
+	assert.Empty(t, bindNote())
+
+	assert.NoError(t, ioutil.WriteFile(name, []byte(`rulewatch.noteForm: {Text: "MaxSize(5)"}`), 0644))
+	for deadline := time.Now().Add(5 * time.Second); len(bindNote()) == 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Len(t, bindNote(), 1)
+
+	assert.NoError(t, ioutil.WriteFile(name, []byte(`rulewatch.noteForm: [`), 0644))
+	select {
+	case err := <-errc:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("invalid rules were not reported")
+	}
+	assert.Len(t, bindNote(), 1)
+
+	_, err = Watch(filepath.Join(dir, "missing.yaml"), nil)
+	assert.Error(t, err)
+}
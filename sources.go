@@ -0,0 +1,168 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// bindSources binds the fields of obj tagged with the source tags from the
+// matching parts of req. They are bound after the request is decoded into
+// obj, so they win over values of the same fields in the body. Fields
+// tagged for several sources are bound from the last of them present in
+// req, in this order: the body, the query string, headers, cookies and
+// the URL parameters of the route. Fields tagged with query are bound by
+// the decoder of bodyless requests already, so query is false for them.
+func bindSources(req *http.Request, obj interface{}, query bool, errors Errors) Errors {
+	if query {
+		errors = bindQuery(req, obj, errors)
+	}
+	errors = bindHeaders(req, obj, errors)
+	errors = bindCookies(req, obj, errors)
+	return bindRoute(req, obj, errors)
+}
+
+// bindQuery binds the fields of obj tagged with query, e.g.
+// query:"expand", from the query string of requests bound from their body.
+func bindQuery(req *http.Request, obj interface{}, errors Errors) Errors {
+	if req.URL == nil || req.URL.RawQuery == "" {
+		return errors
+	}
+	query, _ := url.ParseQuery(req.URL.RawQuery)
+	b := binderFrom(req)
+	if errs := b.formLimits.check(query, nil); len(errs) > 0 {
+		return append(errors, errs...)
+	}
+	present := newFieldPaths()
+	errors = mapTagged(reflect.ValueOf(obj), "query", func(name string) ([]string, bool) {
+		values, ok := query[name]
+		return values, ok
+	}, SOURCE_QUERY, present, b, errors)
+	if present.len() > 0 {
+		addProvided(req, obj, present)
+	}
+	return errors
+}
+
+// bindHeaders binds the fields of obj tagged with header, e.g.
+// header:"X-Request-Id", from the headers of req. Header names are
+// canonicalized, and repeated headers are bound into slices.
+func bindHeaders(req *http.Request, obj interface{}, errors Errors) Errors {
+	present := newFieldPaths()
+	errors = mapTagged(reflect.ValueOf(obj), "header", func(name string) ([]string, bool) {
+		values, ok := req.Header[http.CanonicalHeaderKey(name)]
+		return values, ok
+	}, SOURCE_HEADER, present, binderFrom(req), errors)
+	if present.len() > 0 {
+		addProvided(req, obj, present)
+	}
+	return errors
+}
+
+// bindCookies binds the fields of obj tagged with cookie, e.g.
+// cookie:"session_id", from the cookies of req. A cookie tagged with the
+// required option, e.g. cookie:"session_id,required", is reported as
+// ERR_DESERIALIZATION if it is missing.
+func bindCookies(req *http.Request, obj interface{}, errors Errors) Errors {
+	present := newFieldPaths()
+	errors = mapTagged(reflect.ValueOf(obj), "cookie", func(name string) ([]string, bool) {
+		cookie, err := req.Cookie(name)
+		if err != nil {
+			return nil, false
+		}
+		return []string{cookie.Value}, true
+	}, SOURCE_COOKIE, present, binderFrom(req), errors)
+	if present.len() > 0 {
+		addProvided(req, obj, present)
+	}
+	return errors
+}
+
+// mapTagged binds the fields of the struct v, and of the structs nested in
+// it, named by tag from the values returned by lookup. The values are
+// converted like form values and reported with the given source. Missing
+// values of fields tagged with the required option are reported as
+// ERR_DESERIALIZATION.
+func mapTagged(v reflect.Value, tag string, lookup func(name string) ([]string, bool), source string,
+	present fieldPaths, b *Binder, errors Errors) Errors {
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return errors
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return errors
+	}
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldVal := v.Field(i)
+		name := field.Tag.Get(tag)
+		if j := strings.IndexByte(name, ','); j >= 0 {
+			name = name[:j]
+		}
+		if name == "" || name == "-" {
+			if isNestedStruct(field.Type) || field.Anonymous && field.Type.Kind() == reflect.Ptr {
+				n := present.len()
+				errors = mapTagged(fieldVal, tag, lookup, source, present.at(field.Name), b, errors)
+				present.addParent(field.Name, n)
+			}
+			continue
+		}
+		if !fieldVal.CanSet() {
+			continue
+		}
+		if values, ok := lookup(name); ok && len(values) > 0 {
+			errors = setFieldValues(field, fieldVal, name, values, source, present, b, errors)
+		} else if hasFormOption(field.Tag.Get(tag), "required") {
+			errors = append(errors, Error{
+				FieldNames:     []string{name},
+				Classification: ERR_DESERIALIZATION,
+				Message:        "Missing " + source,
+				Source:         source,
+			})
+		}
+	}
+	return errors
+}
+
+// sourceTags are the tags naming the inputs bound into fields from parts
+// of the request other than the form or body.
+var sourceTags = []string{"param", "query", "header", "cookie"}
+
+// hasSourceTag reports whether field is bound from a source tag, so that
+// it is validated even if it is excluded from forms with form:"-".
+func hasSourceTag(field reflect.StructField) bool {
+	for _, tag := range sourceTags {
+		if name, ok := field.Tag.Lookup(tag); ok && name != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// inputName returns the name of the input bound into field, from its tag
+// called tag if it has one, or else from its form tag.
+func inputName(field reflect.StructField, tag string) string {
+	if name, ok := field.Tag.Lookup(tag); ok && tag != "form" {
+		return parseFormName(field.Name, name)
+	}
+	return parseFormName(field.Name, field.Tag.Get("form"))
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	chi "github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HeaderTag(t *testing.T) {
+	type request struct {
+		RequestID string    `form:"-" header:"x-request-id" binding:"Required"`
+		Tenant    int       `form:"-" header:"X-Tenant"`
+		DryRun    *bool     `form:"-" header:"X-Dry-Run"`
+		Since     time.Time `form:"-" header:"If-Modified-Since"`
+		Forwarded []string  `form:"-" header:"X-Forwarded-For"`
+		Title     string    `json:"title"`
+	}
+	newRequest := func() *http.Request {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"title":"Hello"}`))
+		req.Header.Set("Content-Type", "application/json")
+		return withState(req)
+	}
+
+	req := newRequest()
+	req.Header.Set("X-Request-Id", "abc")
+	req.Header.Set("X-Tenant", "7")
+	req.Header.Set("X-Dry-Run", "true")
+	req.Header.Set("If-Modified-Since", "Wed, 21 Oct 2015 07:28:00 GMT")
+	req.Header.Add("X-Forwarded-For", "10.0.0.1")
+	req.Header.Add("X-Forwarded-For", "10.0.0.2")
+	var r request
+	assert.Empty(t, Bind(req, &r))
+	assert.EqualValues(t, "abc", r.RequestID)
+	assert.EqualValues(t, 7, r.Tenant)
+	if assert.NotNil(t, r.DryRun) {
+		assert.True(t, *r.DryRun)
+	}
+	assert.True(t, time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC).Equal(r.Since))
+	assert.EqualValues(t, []string{"10.0.0.1", "10.0.0.2"}, r.Forwarded)
+	assert.EqualValues(t, "Hello", r.Title)
+	assert.EqualValues(t, []string{"Title", "RequestID", "Tenant", "DryRun", "Since", "Forwarded"}, Provided(req))
+
+	req = newRequest()
+	req.Header.Set("X-Tenant", "acme")
+	req.Header.Set("If-Modified-Since", "yesterday")
+	r = request{}
+	errs := Bind(req, &r)
+	if assert.Len(t, errs, 3) {
+		assert.EqualValues(t, ERR_INTERGER_TYPE, errs[0].Classification)
+		assert.EqualValues(t, []string{"X-Tenant"}, errs[0].FieldNames)
+		assert.EqualValues(t, SOURCE_HEADER, errs[0].Source)
+		assert.EqualValues(t, ERR_TIME, errs[1].Classification)
+		assert.EqualValues(t, ERR_REQUIRED, errs[2].Classification)
+		assert.EqualValues(t, []string{"RequestID"}, errs[2].FieldNames)
+	}
+}
+
+func Test_CookieTag(t *testing.T) {
+	type prefs struct {
+		Session string `form:"-" cookie:"session_id,required"`
+		Theme   string `form:"-" cookie:"theme" binding:"In(light,dark)"`
+		Width   int    `form:"-" cookie:"width"`
+	}
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session_id", Value: "s3cr3t"})
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	req.AddCookie(&http.Cookie{Name: "width", Value: "1280"})
+	req = withState(req)
+	var p prefs
+	assert.Empty(t, Bind(req, &p))
+	assert.EqualValues(t, prefs{Session: "s3cr3t", Theme: "dark", Width: 1280}, p)
+	assert.EqualValues(t, []string{"Session", "Theme", "Width"}, Provided(req))
+
+	req, _ = http.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "blue"})
+	req.AddCookie(&http.Cookie{Name: "width", Value: "wide"})
+	p = prefs{}
+	errs := Bind(req, &p)
+	if assert.Len(t, errs, 3) {
+		assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+		assert.EqualValues(t, []string{"session_id"}, errs[0].FieldNames)
+		assert.EqualValues(t, "Missing cookie", errs[0].Message)
+		assert.EqualValues(t, SOURCE_COOKIE, errs[0].Source)
+		assert.EqualValues(t, ERR_INTERGER_TYPE, errs[1].Classification)
+		assert.EqualValues(t, SOURCE_COOKIE, errs[1].Source)
+		assert.EqualValues(t, ERR_IN, errs[2].Classification)
+	}
+}
+
+func Test_MultiSource(t *testing.T) {
+	type updateUser struct {
+		ID      int    `json:"id" param:"id"`
+		Expand  string `json:"-" query:"expand"`
+		Tenant  string `json:"tenant" query:"tenant" header:"X-Tenant"`
+		Name    string `json:"name" binding:"Required"`
+		Version int    `json:"-" query:"version" header:"If-Match" param:"version"`
+	}
+
+	var u updateUser
+	r := chi.NewRouter()
+	handler := func(rw http.ResponseWriter, req *http.Request) {
+		u = updateUser{}
+		if errs := Bind(req, &u); len(errs) > 0 {
+			rw.WriteHeader(http.StatusUnprocessableEntity)
+		}
+	}
+	r.Put("/users/{id}", handler)
+	r.Put("/users/{id}/versions/{version}", handler)
+
+	serve := func(path string, header http.Header) {
+		req, _ := http.NewRequest("PUT", path, strings.NewReader(`{"id":1,"tenant":"body","name":"Alice"}`))
+		req.Header = header
+		req.Header.Set("Content-Type", "application/json")
+		rw := httptest.NewRecorder()
+		r.ServeHTTP(rw, req)
+		assert.EqualValues(t, http.StatusOK, rw.Code, path)
+	}
+
+	serve("/users/42?expand=profile", http.Header{})
+	assert.EqualValues(t, updateUser{ID: 42, Expand: "profile", Tenant: "body", Name: "Alice"}, u)
+
+	serve("/users/42?tenant=query&version=1", http.Header{})
+	assert.EqualValues(t, "query", u.Tenant)
+	assert.EqualValues(t, 1, u.Version)
+
+	serve("/users/42?tenant=query&version=1", http.Header{"X-Tenant": {"header"}, "If-Match": {"2"}})
+	assert.EqualValues(t, "header", u.Tenant)
+	assert.EqualValues(t, 2, u.Version)
+
+	serve("/users/42/versions/3?version=1", http.Header{"If-Match": {"2"}})
+	assert.EqualValues(t, 3, u.Version)
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"context"
+	"net/http"
+)
+
+type stateKey struct{}
+
+// requestState holds what binding records about a request. Handler keeps
+// one in the context of the request, and binding changes its fields rather
+// than the request.
+type requestState struct {
+	provided  *provided
+	errors    Errors
+	rawBody   *rawBody
+	fieldMask *fieldMask
+}
+
+// Handler is a middleware keeping what binding records about the requests
+// it handles, so that Provided, ErrorsFrom, RawBody and FieldMask report it
+// to later handlers and middleware. Binder.Handler does the same. Without
+// either, the record is only kept while binding and they return nil.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(rw, withState(req))
+	})
+}
+
+// withState returns req if it has a requestState, or else a copy of req
+// with an empty one.
+func withState(req *http.Request) *http.Request {
+	if stateOf(req) != nil {
+		return req
+	}
+	return req.WithContext(context.WithValue(req.Context(), stateKey{}, &requestState{}))
+}
+
+// stateOf returns the requestState of req, or nil if it has none.
+func stateOf(req *http.Request) *requestState {
+	if req == nil {
+		return nil
+	}
+	s, _ := req.Context().Value(stateKey{}).(*requestState)
+	return s
+}
+
+// keepForms copies the forms parsed while binding r, a copy of req, back
+// to req, so that handlers can still read them and the server removes the
+// temporary files of multipart forms.
+func keepForms(req, r *http.Request) {
+	if r != req {
+		req.Form, req.PostForm, req.MultipartForm = r.Form, r.PostForm, r.MultipartForm
+	}
+}
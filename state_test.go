@@ -0,0 +1,77 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Handler(t *testing.T) {
+	type noteForm struct {
+		Count int    `form:"count" binding:"Required"`
+		Note  string `form:"note"`
+	}
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest("POST", "/", strings.NewReader("note=n"))
+		req.Header.Set("Content-Type", formContentType)
+		return req
+	}
+
+	// Without Handler, the request keeps its parsed form but no record.
+	req := newRequest()
+	var form noteForm
+	assert.Len(t, Form(req, &form), 1)
+	assert.EqualValues(t, "n", form.Note)
+	assert.EqualValues(t, "n", req.PostForm.Get("note"))
+	assert.Nil(t, Provided(req))
+	assert.Nil(t, ErrorsFrom(req))
+
+	var provided []string
+	var errs Errors
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		form = noteForm{}
+		Form(req, &form)
+		provided, errs = Provided(req), ErrorsFrom(req)
+	})
+	Handler(next).ServeHTTP(httptest.NewRecorder(), newRequest())
+	assert.EqualValues(t, []string{"Note"}, provided)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+	}
+
+	// Binder methods record into the state installed by Handler.
+	b := New()
+	Handler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		form = noteForm{}
+		b.Form(req, &form)
+		provided, errs = Provided(req), ErrorsFrom(req)
+	})).ServeHTTP(httptest.NewRecorder(), newRequest())
+	assert.EqualValues(t, []string{"Note"}, provided)
+	assert.Len(t, errs, 1)
+
+	req = newRequest()
+	assert.Len(t, b.Form(req, &form), 1)
+	assert.EqualValues(t, "n", req.PostForm.Get("note"))
+	assert.Nil(t, ErrorsFrom(req))
+
+	provided = nil
+	b.Handler(next).ServeHTTP(httptest.NewRecorder(), newRequest())
+	assert.EqualValues(t, []string{"Note"}, provided)
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/goccy/go-json"
+)
+
+// ElementFunc is called by BindEach with each element of a stream, after
+// it was bound into obj, and the errors of binding it. Returning an error
+// stops the stream.
+type ElementFunc func(index int, obj interface{}, errs Errors) error
+
+// BindEach binds the elements of a newline delimited JSON body
+// (application/x-ndjson) or of a JSON array body into new structs returned
+// by newObj, one at a time, and calls fn with each of them. Elements are
+// bound and validated like JSON bodies, so bulk uploads can be processed
+// without holding all of them in memory. Empty lines of NDJSON bodies are
+// skipped.
+//
+// The returned Errors only hold the errors of the request as a whole, e.g.
+// an unsupported Content-Type or a malformed stream; the error is the one
+// returned by fn, if any.
+func BindEach(req *http.Request, newObj func() interface{}, fn ElementFunc) (Errors, error) {
+	inflated, errors := decompressBody(req)
+	if len(errors) > 0 {
+		return errors, nil
+	}
+	var next func() ([]byte, error)
+	switch mediaFormat(req.Header.Get("Content-Type")) {
+	case "ndjson":
+		if req.Body == nil {
+			return nil, nil
+		}
+		next = ndjsonElements(req.Body)
+	case "json":
+		if req.Body == nil {
+			return nil, nil
+		}
+		var err error
+		next, err = jsonArrayElements(req.Body)
+		if err != nil {
+			errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+			return inflated.check(errors), nil
+		}
+	default:
+		errors.Add([]string{}, ERR_CONTENT_TYPE, "Unsupported Content-Type")
+		return errors, nil
+	}
+	defer req.Body.Close()
+
+	for i := 0; ; i++ {
+		data, err := next()
+		if err == io.EOF {
+			return nil, nil
+		} else if err != nil {
+			errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+			return inflated.check(errors), nil
+		}
+		obj := newObj()
+		if err := fn(i, obj, JSON(elementRequest(req, data), obj)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ndjsonElements returns a function reading the non-empty lines of body.
+func ndjsonElements(body io.Reader) func() ([]byte, error) {
+	r := bufio.NewReader(body)
+	return func() ([]byte, error) {
+		for {
+			line, err := r.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				return line, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+var errNotArray = errors.New("expected a JSON array")
+
+// jsonArrayElements returns a function reading the elements of the JSON
+// array in body.
+func jsonArrayElements(body io.Reader) (func() ([]byte, error), error) {
+	dec := json.NewDecoder(body)
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, errNotArray
+	}
+	return func() ([]byte, error) {
+		if !dec.More() {
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		var raw json.RawMessage
+		err := dec.Decode(&raw)
+		return raw, err
+	}, nil
+}
+
+// elementRequest returns a request for binding one element of a stream
+// read from req on its own.
+func elementRequest(req *http.Request, data []byte) *http.Request {
+	sub := new(http.Request)
+	*sub = *req
+	sub.Header = req.Header.Clone()
+	sub.Header.Set("Content-Type", "application/json")
+	sub.Body = ioutil.NopCloser(bytes.NewReader(data))
+	sub.ContentLength = int64(len(data))
+	return sub
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func bindEach(contentType, body string, stopAt int) ([]Post, []Errors, Errors, error) {
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	var posts []Post
+	var elementErrs []Errors
+	errs, err := BindEach(req, func() interface{} { return new(Post) }, func(i int, obj interface{}, errs Errors) error {
+		if i == stopAt {
+			return errors.New("stop")
+		}
+		posts = append(posts, *obj.(*Post))
+		elementErrs = append(elementErrs, errs)
+		return nil
+	})
+	return posts, elementErrs, errs, err
+}
+
+func Test_BindEach(t *testing.T) {
+	ndjson := "{\"title\":\"Hello, world\"}\n\n{\"title\":\"Short\"}\n{\"title\":\"Goodbye, world\",\"content\":\"Bye\"}\n"
+	array := `[{"title":"Hello, world"}, {"title":"Short"}, {"title":"Goodbye, world","content":"Bye"}]`
+	for contentType, body := range map[string]string{"application/x-ndjson": ndjson, "application/json": array} {
+		posts, elementErrs, errs, err := bindEach(contentType, body, -1)
+		assert.NoError(t, err)
+		assert.Empty(t, errs)
+		if assert.Len(t, posts, 3) {
+			assert.EqualValues(t, "Hello, world", posts[0].Title)
+			assert.EqualValues(t, "Bye", posts[2].Content)
+			assert.Empty(t, elementErrs[0])
+			assert.NotEmpty(t, elementErrs[1])
+			assert.Empty(t, elementErrs[2])
+		}
+
+		posts, _, errs, err = bindEach(contentType, body, 1)
+		assert.EqualError(t, err, "stop")
+		assert.Empty(t, errs)
+		assert.Len(t, posts, 1)
+	}
+}
+
+func Test_BindEachMalformed(t *testing.T) {
+	// Malformed lines only fail their element.
+	posts, elementErrs, errs, err := bindEach("application/x-ndjson", "{\"title\":\n{\"title\":\"Hello, world\"}\n", -1)
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+	if assert.Len(t, posts, 2) {
+		assert.EqualValues(t, ERR_DESERIALIZATION, elementErrs[0][0].Classification)
+		assert.Empty(t, elementErrs[1])
+	}
+
+	// A malformed array cannot be read any further.
+	posts, _, errs, err = bindEach("application/json", `[{"title":"Hello, world"}, {"title":`, -1)
+	assert.NoError(t, err)
+	assert.Len(t, posts, 1)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+	}
+
+	_, _, errs, _ = bindEach("application/json", `{"title":"Hello, world"}`, -1)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+	}
+
+	_, _, errs, _ = bindEach("text/csv", "title\n", -1)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, ERR_CONTENT_TYPE, errs[0].Classification)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// StringIntegers makes integer fields, such as int64 and uint64 IDs,
+// accept JSON strings holding an integer as well as numbers, e.g.
+// {"id": "9223372036854775807"}. JavaScript clients have to send such IDs
+// as strings, since their numbers cannot represent them. Unlike the
+// ",string" option of json tags, plain numbers are still accepted.
+var StringIntegers = false
+
+// WithStringIntegers sets whether integer fields accept JSON strings; see
+// StringIntegers.
+func WithStringIntegers(accept bool) Option {
+	return func(b *Binder) {
+		b.stringIntegers = accept
+	}
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unquoteJSONIntegers rewrites the strings of data bound into duration
+// fields of typ, and into integer fields if integers is set, into numbers.
+// It returns data unchanged if there are none or if data is malformed,
+// which is left to the decoder to report.
+func unquoteJSONIntegers(data []byte, typ reflect.Type, integers bool) []byte {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if dec.Decode(&doc) != nil {
+		return data
+	}
+	doc, changed := unquoteIntegers(typ, doc, integers)
+	if !changed {
+		return data
+	}
+	var buf bytes.Buffer
+	writeJSONValue(&buf, doc)
+	return buf.Bytes()
+}
+
+// writeJSONValue encodes a document decoded with UseNumber.
+func writeJSONValue(buf *bytes.Buffer, doc interface{}) {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONValue(buf, key)
+			buf.WriteByte(':')
+			writeJSONValue(buf, v[key])
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONValue(buf, elem)
+		}
+		buf.WriteByte(']')
+	case json.Number:
+		buf.WriteString(string(v))
+	default:
+		out, _ := json.Marshal(v)
+		buf.Write(out)
+	}
+}
+
+func unquoteIntegers(typ reflect.Type, doc interface{}, integers bool) (interface{}, bool) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if reflect.PtrTo(typ).Implements(jsonUnmarshalerType) {
+		return doc, false
+	}
+	if typ == durationType {
+		if s, ok := doc.(string); ok {
+			if d, err := time.ParseDuration(s); err == nil {
+				return json.Number(strconv.FormatInt(int64(d), 10)), true
+			}
+		}
+	}
+
+	if !integers && !holdsDuration(typ) {
+		return doc, false
+	}
+
+	changed := false
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s, ok := doc.(string); ok {
+			if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return json.Number(s), true
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s, ok := doc.(string); ok {
+			if _, err := strconv.ParseUint(s, 10, 64); err == nil {
+				return json.Number(s), true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		elems, _ := doc.([]interface{})
+		for i := range elems {
+			var c bool
+			elems[i], c = unquoteIntegers(typ.Elem(), elems[i], integers)
+			changed = changed || c
+		}
+	case reflect.Map:
+		obj, _ := doc.(map[string]interface{})
+		for key, value := range obj {
+			var c bool
+			obj[key], c = unquoteIntegers(typ.Elem(), value, integers)
+			changed = changed || c
+		}
+	case reflect.Struct:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return doc, false
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+			opts := strings.Split(field.Tag.Get("json"), ",")
+			name := opts[0]
+			if name == "-" || len(opts) > 1 && opts[1] == "string" {
+				continue
+			}
+			if name == "" && field.Anonymous {
+				_, c := unquoteIntegers(field.Type, obj, integers)
+				changed = changed || c
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			for key, value := range obj {
+				if key == name || strings.EqualFold(key, name) {
+					var c bool
+					obj[key], c = unquoteIntegers(field.Type, value, integers)
+					changed = changed || c
+				}
+			}
+		}
+	}
+	return doc, changed
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+func init() {
+	AddConverter(time.Time{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return time.Time{}, nil
+		}
+		if t, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			return t, nil
+		}
+		// Dates of HTTP headers, such as If-Modified-Since.
+		if t, err := http.ParseTime(val); err == nil {
+			return t, nil
+		}
+		return nil, Error{Classification: ERR_TIME, Message: "Value could not be parsed as time"}
+	})
+	fieldTagCheckers = append(fieldTagCheckers, func(field reflect.StructField) error {
+		if zone := field.Tag.Get("time_location"); zone != "" {
+			if _, err := location(zone); err != nil {
+				return fmt.Errorf("time_location %q: %v", zone, err)
+			}
+		}
+		return nil
+	})
+}
+
+// locations caches the locations of time_location tags by name.
+var locations sync.Map
+
+// location returns the location of a time_location tag, loading it once.
+func location(zone string) (*time.Location, error) {
+	if cached, ok := locations.Load(zone); ok {
+		return cached.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, err
+	}
+	cached, _ := locations.LoadOrStore(zone, loc)
+	return cached.(*time.Location), nil
+}
+
+// TimeLayout is the layout used to parse form and query values of
+// time.Time fields without a time_format tag, e.g. "2006-01-02". Empty,
+// the default, accepts RFC 3339 and HTTP dates.
+var TimeLayout string
+
+// WithTimeLayout sets the layout used to parse form and query values of
+// time fields without a time_format tag; see TimeLayout.
+func WithTimeLayout(layout string) Option {
+	return func(b *Binder) {
+		b.timeLayout = layout
+	}
+}
+
+// setTimeValue sets target, a time.Time or *time.Time, to val parsed with
+// the layout of the time_format tag of field, e.g. `time_format:"2006-01-02"`,
+// in the location of its time_location tag, e.g.
+// `time_location:"Europe/Berlin"`, which applies to values without a zone
+// offset. The layout defaults to defaultLayout, or else RFC 3339, and the
+// location to UTC. Values that do not match the layout are reported as
+// ERR_TIME. It reports false if field has neither tag and there is no
+// defaultLayout, so that val is converted as usual.
+func setTimeValue(field reflect.StructField, target reflect.Value, val, defaultLayout, name string, errors Errors) (Errors, bool) {
+	layout, hasLayout := field.Tag.Lookup("time_format")
+	zone, hasZone := field.Tag.Lookup("time_location")
+	if !hasLayout {
+		layout = defaultLayout
+	}
+	if !hasLayout && !hasZone && layout == "" {
+		return errors, false
+	}
+	typ := target.Type()
+	if typ != timeType && (typ.Kind() != reflect.Ptr || typ.Elem() != timeType) {
+		return errors, false
+	}
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+	loc := time.UTC
+	if zone != "" {
+		var err error
+		if loc, err = location(zone); err != nil {
+			panic(fmt.Sprintf("binding: invalid time_location %q of %s: %v", zone, field.Name, err))
+		}
+	}
+
+	var t time.Time
+	if val != "" {
+		var err error
+		if t, err = time.ParseInLocation(layout, val, loc); err != nil {
+			errors.Add([]string{name}, ERR_TIME, "Value could not be parsed as time in format "+layout)
+			return errors, true
+		}
+	}
+	if typ.Kind() == reflect.Ptr {
+		if val == "" {
+			target.Set(reflect.Zero(typ))
+			return errors, true
+		}
+		target.Set(reflect.New(timeType))
+		target = target.Elem()
+	}
+	target.Set(reflect.ValueOf(t))
+	return errors, true
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TimeFormat(t *testing.T) {
+	type eventQuery struct {
+		From    time.Time   `query:"from" time_format:"2006-01-02"`
+		At      *time.Time  `query:"at" time_format:"2006-01-02 15:04" time_location:"America/New_York"`
+		Days    []time.Time `query:"day" time_format:"02.01.2006"`
+		Updated time.Time   `query:"updated"`
+	}
+
+	req, _ := http.NewRequest("GET", "/?from=2021-03-04&at=2021-03-04+09:30&day=01.02.2021&day=02.02.2021&updated=2021-03-04T10:00:00Z", nil)
+	var query eventQuery
+	errs := Query(req, &query)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), query.From)
+	if assert.NotNil(t, query.At) {
+		assert.EqualValues(t, "2021-03-04T14:30:00Z", query.At.UTC().Format(time.RFC3339))
+	}
+	assert.EqualValues(t, []time.Time{time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 2, 0, 0, 0, 0, time.UTC)}, query.Days)
+	assert.True(t, time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC).Equal(query.Updated))
+
+	req, _ = http.NewRequest("GET", "/?from=2021-03-04T10:00:00Z&at=&updated=yesterday", nil)
+	query = eventQuery{}
+	errs = Query(req, &query)
+	assert.Nil(t, query.At)
+	if assert.Len(t, errs, 2) {
+		assert.EqualValues(t, []string{"from"}, errs[0].FieldNames)
+		assert.EqualValues(t, ERR_TIME, errs[0].Classification)
+		assert.EqualValues(t, "Value could not be parsed as time in format 2006-01-02", errs[0].Message)
+		assert.EqualValues(t, []string{"updated"}, errs[1].FieldNames)
+		assert.EqualValues(t, ERR_TIME, errs[1].Classification)
+	}
+}
+
+func Test_TimeLocationInvalid(t *testing.T) {
+	type meetingQuery struct {
+		At time.Time `query:"at" time_location:"Mars/Olympus_Mons"`
+	}
+	const msg = `binding: invalid tags of binding.meetingQuery.At: time_location "Mars/Olympus_Mons": unknown time zone Mars/Olympus_Mons`
+
+	// The zone is checked even if no time is submitted.
+	req, _ := http.NewRequest("GET", "/", nil)
+	assert.PanicsWithValue(t, msg, func() { Query(req, &meetingQuery{}) })
+	assert.PanicsWithValue(t, msg, func() { RawValidate(meetingQuery{}) })
+}
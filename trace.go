@@ -0,0 +1,81 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+// Stages of binding reported in TraceEvent.Stage.
+const (
+	// TRACE_BIND reports a field set from the request.
+	TRACE_BIND = "bind"
+	// TRACE_REJECT reports a value that could not be converted.
+	TRACE_REJECT = "reject"
+	// TRACE_VALIDATE reports the rules applied to a field and the errors
+	// they raised, if any.
+	TRACE_VALIDATE = "validate"
+)
+
+type (
+	// TraceEvent describes a decision taken while binding a request.
+	TraceEvent struct {
+		Stage string
+		// Field is the path of the field, e.g. "Address.City".
+		Field string
+		// Source is the part of the request the value was read from.
+		Source string
+		// Value is the submitted value, omitted for fields marked
+		// Sensitive and for JSON bodies.
+		Value string
+		// Rules are the rules of the field, for TRACE_VALIDATE.
+		Rules []string
+		// Errors are the errors raised for the field, if any.
+		Errors Errors
+	}
+
+	// Tracer receives the decisions taken while binding, to debug why a
+	// field ended up empty or was rejected.
+	Tracer interface {
+		Trace(TraceEvent)
+	}
+
+	// TracerFunc is an adapter to use a function as a Tracer.
+	TracerFunc func(TraceEvent)
+)
+
+// Trace calls f(ev).
+func (f TracerFunc) Trace(ev TraceEvent) {
+	f(ev)
+}
+
+var tracer Tracer
+
+// SetTracer sets the tracer receiving the binding decisions of all
+// requests, or disables tracing if t is nil, the default.
+func SetTracer(t Tracer) {
+	tracer = t
+}
+
+// WithTracer sets the tracer receiving the binding decisions; see
+// SetTracer.
+func WithTracer(t Tracer) Option {
+	return func(b *Binder) {
+		b.tracer = t
+	}
+}
+
+// trace reports ev to the tracer of b, if any.
+func (b *Binder) trace(ev TraceEvent) {
+	if b.tracer != nil {
+		b.tracer.Trace(ev)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package binding
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogTracer returns a Tracer logging the binding decisions to logger at
+// Debug level.
+func SlogTracer(logger *slog.Logger) Tracer {
+	return TracerFunc(func(ev TraceEvent) {
+		if !logger.Enabled(context.Background(), slog.LevelDebug) {
+			return
+		}
+		attrs := []slog.Attr{slog.String("stage", ev.Stage), slog.String("field", ev.Field)}
+		if ev.Source != "" {
+			attrs = append(attrs, slog.String("source", ev.Source))
+		}
+		if ev.Value != "" {
+			attrs = append(attrs, slog.String("value", ev.Value))
+		}
+		if len(ev.Rules) > 0 {
+			attrs = append(attrs, slog.Any("rules", ev.Rules))
+		}
+		for _, err := range ev.Errors {
+			attrs = append(attrs, slog.Group("error", slog.String("classification", err.Classification), slog.String("message", err.Message)))
+		}
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "binding", attrs...)
+	})
+}
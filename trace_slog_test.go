@@ -0,0 +1,43 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package binding
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SlogTracer(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	b := New(WithTracer(SlogTracer(logger)))
+
+	req, _ := http.NewRequest("GET", "/?title=x", nil)
+	var post Post
+	b.Form(req, &post)
+	assert.Contains(t, buf.String(), "level=DEBUG msg=binding stage=bind field=Title source=query value=x")
+
+	buf.Reset()
+	logger = slog.New(slog.NewTextHandler(&buf, nil))
+	New(WithTracer(SlogTracer(logger))).Form(req, &post)
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tracer(t *testing.T) {
+	type loginForm struct {
+		User     string `form:"user" binding:"Required;AlphaDash"`
+		Password string `form:"password" binding:"Required;Sensitive"`
+		Age      int    `form:"age"`
+	}
+
+	var events []TraceEvent
+	b := New(WithTracer(TracerFunc(func(ev TraceEvent) {
+		events = append(events, ev)
+	})))
+
+	req, _ := http.NewRequest("GET", "/?user=a.b&password=secret&age=x", nil)
+	var form loginForm
+	errs := b.Form(req, &form)
+	assert.Len(t, errs, 2)
+	assert.Len(t, events, 5)
+	assert.EqualValues(t, TraceEvent{Stage: TRACE_BIND, Field: "User", Source: SOURCE_QUERY, Value: "a.b"}, events[0])
+	assert.EqualValues(t, TraceEvent{Stage: TRACE_BIND, Field: "Password", Source: SOURCE_QUERY}, events[1])
+	assert.EqualValues(t, TRACE_REJECT, events[2].Stage)
+	assert.EqualValues(t, "Age", events[2].Field)
+	assert.EqualValues(t, ERR_INTERGER_TYPE, events[2].Errors[0].Classification)
+	assert.EqualValues(t, TRACE_VALIDATE, events[3].Stage)
+	assert.EqualValues(t, []string{"Required", "AlphaDash"}, events[3].Rules)
+	assert.EqualValues(t, ERR_ALPHA_DASH, events[3].Errors[0].Classification)
+	assert.EqualValues(t, TRACE_VALIDATE, events[4].Stage)
+	assert.Empty(t, events[4].Errors)
+
+	events = nil
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"title":"Hello, world","author":{"name":"A"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	var post BlogPost
+	b.Bind(req, &post)
+	assert.EqualValues(t, TraceEvent{Stage: TRACE_BIND, Field: "Post.Title", Source: SOURCE_BODY}, events[0])
+	assert.EqualValues(t, TraceEvent{Stage: TRACE_BIND, Field: "Author.Name", Source: SOURCE_BODY}, events[2])
+
+	// Tracing is off by default.
+	events = nil
+	req, _ = http.NewRequest("GET", "/?user=a.b", nil)
+	Form(req, &form)
+	assert.Empty(t, events)
+}
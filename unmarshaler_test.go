@@ -0,0 +1,129 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sku is parsed from "AAA-1234" values and remembers where it came from.
+type sku struct {
+	Prefix string
+	Number string
+	Source string
+}
+
+func (s *sku) UnmarshalBinding(source, value string) error {
+	parts := strings.SplitN(value, "-", 2)
+	if len(parts) != 2 {
+		return Error{Classification: "SKUError", Message: "malformed SKU"}
+	}
+	if parts[0] == "" {
+		return errors.New("missing SKU prefix")
+	}
+	s.Prefix, s.Number, s.Source = parts[0], parts[1], source
+	return nil
+}
+
+type skuForm struct {
+	Primary sku    `form:"primary"`
+	Backup  *sku   `form:"backup"`
+	Others  []sku  `form:"others"`
+	Ignored string `form:"ignored"`
+}
+
+func Test_Unmarshaler(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/?primary=ABC-1&others=D-2&others=E-3", strings.NewReader("backup=XYZ-9"))
+	req.Header.Set("Content-Type", formContentType)
+	var form skuForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, sku{"ABC", "1", SOURCE_QUERY}, form.Primary)
+	assert.EqualValues(t, &sku{"XYZ", "9", SOURCE_FORM}, form.Backup)
+	assert.EqualValues(t, []sku{{"D", "2", SOURCE_QUERY}, {"E", "3", SOURCE_QUERY}}, form.Others)
+
+	req, _ = http.NewRequest("GET", "/?primary=ABC&backup=-1", nil)
+	form = skuForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, "SKUError", errs[0].Classification)
+	assert.EqualValues(t, "malformed SKU", errs[0].Message)
+	assert.EqualValues(t, []string{"primary"}, errs[0].FieldNames)
+	assert.EqualValues(t, ERR_DESERIALIZATION, errs[1].Classification)
+	assert.EqualValues(t, "missing SKU prefix", errs[1].Message)
+	assert.Nil(t, form.Backup)
+}
+
+// level is an enum parsed from its name.
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	for i, name := range []string{"debug", "info", "warn"} {
+		if string(text) == name {
+			*l = level(i)
+			return nil
+		}
+	}
+	return errors.New("unknown level " + string(text))
+}
+
+// semver is a struct parsed from "1.2" values as a whole.
+type semver struct {
+	Major, Minor string
+}
+
+func (v *semver) UnmarshalText(text []byte) error {
+	parts := strings.SplitN(string(text), ".", 2)
+	if len(parts) != 2 {
+		return Error{Classification: ERR_VERSION, Message: "malformed version"}
+	}
+	v.Major, v.Minor = parts[0], parts[1]
+	return nil
+}
+
+func Test_TextUnmarshaler(t *testing.T) {
+	type logQuery struct {
+		Level   level   `query:"level"`
+		Min     *level  `query:"min"`
+		Levels  []level `query:"levels"`
+		Version semver  `query:"-" header:"X-Version"`
+	}
+
+	req, _ := http.NewRequest("GET", "/?level=warn&min=info&levels=debug&levels=warn", nil)
+	req.Header.Set("X-Version", "2.1")
+	var query logQuery
+	errs := Bind(req, &query)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, 2, query.Level)
+	assert.EqualValues(t, 1, *query.Min)
+	assert.EqualValues(t, []level{0, 2}, query.Levels)
+	assert.EqualValues(t, semver{"2", "1"}, query.Version)
+
+	req, _ = http.NewRequest("GET", "/?level=fatal", nil)
+	req.Header.Set("X-Version", "2")
+	query = logQuery{}
+	errs = Bind(req, &query)
+	if assert.Len(t, errs, 2) {
+		assert.EqualValues(t, []string{"level"}, errs[0].FieldNames)
+		assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+		assert.EqualValues(t, "unknown level fatal", errs[0].Message)
+		assert.EqualValues(t, ERR_VERSION, errs[1].Classification)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// BindInto binds req over obj, a pointer to an existing model, and returns
+// the paths of the fields whose values changed, e.g. ["Title",
+// "Address.City"], to drive audit logs or skip writes without changes.
+// Fields that are not submitted keep their values.
+func BindInto(req *http.Request, obj interface{}) (changed []string, errs Errors) {
+	ensurePointer(obj)
+	before := deepCopy(reflect.ValueOf(obj).Elem())
+	errs = Bind(req, obj)
+	return diffFields(nil, "", before, reflect.ValueOf(obj).Elem()), errs
+}
+
+// Merge binds req over obj, a pointer to an existing model, with PATCH
+// semantics: only fields present in the request overwrite obj, nested
+// structs are merged field by field, and all other fields keep their
+// values. Unlike binding over obj directly, submitted slices and maps
+// replace those of obj instead of being merged into them. obj is
+// validated as a whole afterwards.
+func Merge(req *http.Request, obj interface{}) Errors {
+	decode, fromQuery, errors := decoderFor(req)
+	if decode == nil {
+		stashErrors(req, errors)
+		return errors
+	}
+	return bindWith(req, obj, func(req *http.Request, obj interface{}) Errors {
+		target := reflect.ValueOf(obj).Elem()
+		patch := reflect.New(target.Type())
+		errors := decode(req, patch.Interface())
+
+		paths := Provided(req)
+		present := newFieldPaths()
+		for i, path := range paths {
+			present.add(path)
+			if i+1 < len(paths) && strings.HasPrefix(paths[i+1], path+".") {
+				// Nested struct whose fields are merged individually.
+				continue
+			}
+			copyPath(target, patch.Elem(), strings.Split(path, "."))
+		}
+		setProvided(req, obj, present)
+		return errors
+	}, fromQuery)
+}
+
+// copyPath copies the field at path from the struct src to dst, allocating
+// embedded struct pointers of dst as needed.
+func copyPath(dst, src reflect.Value, path []string) {
+	for _, name := range path {
+		for src.Kind() == reflect.Ptr {
+			if src.IsNil() {
+				return
+			}
+			if dst.IsNil() {
+				dst.Set(reflect.New(dst.Type().Elem()))
+			}
+			src, dst = src.Elem(), dst.Elem()
+		}
+		src, dst = src.FieldByName(name), dst.FieldByName(name)
+	}
+	dst.Set(deepCopy(src))
+}
+
+// diffFields appends the paths of the fields that differ between the
+// structs a and b to changed. Nested structs are compared field by field
+// unless they have unexported fields, like time.Time.
+func diffFields(changed []string, prefix string, a, b reflect.Value) []string {
+	typ := a.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fa, fb := a.Field(i), b.Field(i)
+		if reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct && allExported(field.Type) {
+			changed = diffFields(changed, prefix+field.Name+".", fa, fb)
+		} else {
+			changed = append(changed, prefix+field.Name)
+		}
+	}
+	return changed
+}
+
+func allExported(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).PkgPath != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// deepCopy returns a copy of v that shares no pointers, slices or maps
+// with it, so it is not affected by binding over v.
+func deepCopy(v reflect.Value) reflect.Value {
+	cp := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			elem := deepCopy(v.Elem())
+			cp.Set(reflect.New(elem.Type()))
+			cp.Elem().Set(elem)
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			cp.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+			for i := 0; i < v.Len(); i++ {
+				cp.Index(i).Set(deepCopy(v.Index(i)))
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			cp.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+			iter := v.MapRange()
+			for iter.Next() {
+				cp.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+			}
+		}
+	case reflect.Struct:
+		cp.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if cp.Field(i).CanSet() {
+				cp.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+	default:
+		cp.Set(v)
+	}
+	return cp
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type address struct {
+	Street string `json:"street"`
+	City   string `json:"city" binding:"Required"`
+}
+
+type profile struct {
+	Name      string    `json:"name" binding:"Required"`
+	Tags      []string  `json:"tags"`
+	Address   address   `json:"address"`
+	Birthday  time.Time `json:"birthday"`
+	Followers int       `json:"followers"`
+}
+
+func Test_BindInto(t *testing.T) {
+	existing := profile{
+		Name:      "Alice",
+		Tags:      []string{"admin", "ops"},
+		Address:   address{Street: "Main St", City: "Springfield"},
+		Birthday:  time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Followers: 10,
+	}
+	body := `{"name":"Alice","tags":["dev","ops"],"address":{"city":"Shelbyville"},"birthday":"1990-01-02T00:00:00Z"}`
+	req, _ := http.NewRequest("PUT", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	changed, errs := BindInto(req, &existing)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, []string{"Tags", "Address.City", "Birthday"}, changed)
+	assert.EqualValues(t, "Main St", existing.Address.Street)
+	assert.EqualValues(t, 10, existing.Followers)
+
+	req, _ = http.NewRequest("PUT", "/", strings.NewReader(`{"name":""}`))
+	req.Header.Set("Content-Type", "application/json")
+	changed, errs = BindInto(req, &existing)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, []string{"Name"}, changed)
+}
+
+func Test_BindIntoFalse(t *testing.T) {
+	type settings struct {
+		Active bool `form:"active"`
+		Count  int  `form:"count"`
+	}
+	existing := settings{Active: true, Count: 1}
+	req, _ := http.NewRequest("PUT", "/", strings.NewReader("active=false&count=2"))
+	req.Header.Set("Content-Type", formContentType)
+	changed, errs := BindInto(req, &existing)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, []string{"Active", "Count"}, changed)
+	assert.EqualValues(t, settings{Active: false, Count: 2}, existing)
+}
+
+func Test_Merge(t *testing.T) {
+	existing := profile{
+		Name:      "Alice",
+		Tags:      []string{"admin", "ops"},
+		Address:   address{Street: "Main St", City: "Springfield"},
+		Followers: 10,
+	}
+	req, _ := http.NewRequest("PATCH", "/", strings.NewReader(`{"tags":["dev"],"address":{"city":"Shelbyville"},"followers":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = withState(req)
+	errs := Merge(req, &existing)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, profile{
+		Name:    "Alice",
+		Tags:    []string{"dev"},
+		Address: address{Street: "Main St", City: "Shelbyville"},
+	}, existing)
+	assert.EqualValues(t, []string{"Tags", "Address", "Address.City", "Followers"}, Provided(req))
+
+	req, _ = http.NewRequest("PATCH", "/", strings.NewReader("name=&street=Elm+St"))
+	req.Header.Set("Content-Type", formContentType)
+	errs = Merge(req, &existing)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, []string{"Name"}, errs[0].FieldNames)
+	assert.EqualValues(t, "Elm St", existing.Address.Street)
+	assert.EqualValues(t, "Shelbyville", existing.Address.City)
+
+	req, _ = http.NewRequest("PATCH", "/", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "text/plain")
+	errs = Merge(req, &existing)
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, ERR_CONTENT_TYPE, errs[0].Classification)
+
+	// Query strings of bodyless requests are bound once.
+	type page struct {
+		Page int `query:"page"`
+	}
+	current := page{Page: 1}
+	req, _ = http.NewRequest("GET", "/?page=x", nil)
+	errs = Merge(req, &current)
+	if assert.Len(t, errs, 1) {
+		assert.EqualValues(t, []string{"page"}, errs[0].FieldNames)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Fields of type url.URL and *url.URL are parsed as absolute URLs, from
+// form values as well as JSON strings.
+func init() {
+	parseURL := func(val string) (*url.URL, error) {
+		u, err := url.Parse(val)
+		if err != nil || !u.IsAbs() {
+			return nil, Error{Classification: ERR_URL, Message: "Value could not be parsed as absolute URL"}
+		}
+		return u, nil
+	}
+	AddConverter(&url.URL{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return (*url.URL)(nil), nil
+		}
+		return parseURL(val)
+	})
+	AddConverter(url.URL{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return url.URL{}, nil
+		}
+		u, err := parseURL(val)
+		if err != nil {
+			return nil, err
+		}
+		return *u, nil
+	})
+}
+
+// hasScheme reports whether the URL held by a url.URL, *url.URL or string
+// value, or a pointer to a string, uses one of the comma separated schemes.
+func hasScheme(v interface{}, schemes string) bool {
+	var scheme string
+	switch v := v.(type) {
+	case url.URL:
+		scheme = v.Scheme
+	case *url.URL:
+		if v == nil {
+			return false
+		}
+		scheme = v.Scheme
+	default:
+		u, err := url.Parse(ruleString(v))
+		if err != nil {
+			return false
+		}
+		scheme = u.Scheme
+	}
+	for _, s := range strings.Split(schemes, ",") {
+		if strings.EqualFold(strings.TrimSpace(s), scheme) {
+			return true
+		}
+	}
+	return false
+}
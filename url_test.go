@@ -0,0 +1,84 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type webhookForm struct {
+	Target   *url.URL `form:"target" binding:"Required;Scheme(http,https)"`
+	Fallback url.URL  `form:"fallback"`
+	Raw      string   `form:"raw" binding:"Scheme(ftp)"`
+}
+
+func Test_URLField(t *testing.T) {
+	query := url.Values{
+		"target":   {"https://example.com/hook?x=1"},
+		"fallback": {"mailto:ops@example.com"},
+		"raw":      {"ftp://example.com/file"},
+	}
+	req, _ := http.NewRequest("GET", "/?"+query.Encode(), nil)
+	var form webhookForm
+	errs := Form(req, &form)
+	assert.Empty(t, errs)
+	if assert.NotNil(t, form.Target) {
+		assert.EqualValues(t, "example.com", form.Target.Host)
+		assert.EqualValues(t, "1", form.Target.Query().Get("x"))
+	}
+	assert.EqualValues(t, "mailto", form.Fallback.Scheme)
+
+	query = url.Values{
+		"target":   {"javascript:alert(1)"},
+		"fallback": {"/relative"},
+		"raw":      {"http://example.com"},
+	}
+	req, _ = http.NewRequest("GET", "/?"+query.Encode(), nil)
+	form = webhookForm{}
+	errs = Form(req, &form)
+	assert.Len(t, errs, 3)
+	assert.EqualValues(t, ERR_URL, errs[0].Classification)
+	assert.EqualValues(t, []string{"fallback"}, errs[0].FieldNames)
+	assert.EqualValues(t, ERR_SCHEME, errs[1].Classification)
+	assert.EqualValues(t, ERR_SCHEME, errs[2].Classification)
+}
+
+func Test_URLFieldJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"target":"https://example.com/hook?x=1","fallback":"mailto:ops@example.com"}`))
+	var form webhookForm
+	errs := JSON(req, &form)
+	assert.Empty(t, errs)
+	if assert.NotNil(t, form.Target) {
+		assert.EqualValues(t, "example.com", form.Target.Host)
+	}
+	assert.EqualValues(t, "mailto", form.Fallback.Scheme)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"target":"/relative","raw":"ftp://example.com/file"}`))
+	form = webhookForm{}
+	errs = JSON(req, &form)
+	if assert.Len(t, errs, 2) {
+		assert.EqualValues(t, ERR_URL, errs[0].Classification)
+		assert.EqualValues(t, []string{"Target"}, errs[0].FieldNames)
+		assert.EqualValues(t, "/relative", errs[0].Value)
+		assert.EqualValues(t, ERR_REQUIRED, errs[1].Classification)
+	}
+	assert.Nil(t, form.Target)
+	assert.EqualValues(t, "ftp://example.com/file", form.Raw)
+}
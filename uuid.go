@@ -0,0 +1,64 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+func init() {
+	AddConverter(uuid.UUID{}, func(val string) (interface{}, error) {
+		if val == "" {
+			return uuid.Nil, nil
+		}
+		id, err := uuid.Parse(val)
+		if err != nil {
+			return nil, Error{Classification: ERR_UUID, Message: "Value could not be parsed as UUID"}
+		}
+		return id, nil
+	})
+}
+
+// uuidValue returns the UUID held by v, a uuid.UUID or a string in the
+// canonical 8-4-4-4-12 form, or a pointer to either, and whether it is one.
+func uuidValue(v interface{}) (uuid.UUID, bool) {
+	switch v := v.(type) {
+	case uuid.UUID:
+		return v, true
+	case *uuid.UUID:
+		if v == nil {
+			return uuid.Nil, false
+		}
+		return *v, true
+	}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.String || rv.Len() != 36 {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(rv.String())
+	return id, err == nil
+}
+
+// isUUID reports whether v is a UUID of the given version, or of any
+// version if version is 0.
+func isUUID(v interface{}, version uuid.Version) bool {
+	id, ok := uuidValue(v)
+	if !ok {
+		return false
+	}
+	return version == 0 || id.Version() == version && id.Variant() == uuid.RFC4122
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type uuidForm struct {
+	ID       uuid.UUID   `form:"id" json:"id" binding:"Required"`
+	ParentID *uuid.UUID  `form:"parent_id" json:"parent_id"`
+	Related  []uuid.UUID `form:"related" json:"related"`
+}
+
+func Test_UUID(t *testing.T) {
+	const id = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	t.Run("Form", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/?id="+id+"&parent_id="+id+"&related="+id, nil)
+		var form uuidForm
+		errs := Form(req, &form)
+		assert.Empty(t, errs)
+		assert.EqualValues(t, id, form.ID.String())
+		if assert.NotNil(t, form.ParentID) {
+			assert.EqualValues(t, id, form.ParentID.String())
+		}
+		assert.Len(t, form.Related, 1)
+
+		req, _ = http.NewRequest("GET", "/?id=nope", nil)
+		form = uuidForm{}
+		errs = Form(req, &form)
+		assert.Len(t, errs, 2)
+		assert.EqualValues(t, ERR_UUID, errs[0].Classification)
+		assert.EqualValues(t, "uuid.UUID", errs[0].ExpectedType)
+		assert.EqualValues(t, ERR_REQUIRED, errs[1].Classification)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"id":"`+id+`"}`))
+		var form uuidForm
+		errs := JSON(req, &form)
+		assert.Empty(t, errs)
+		assert.EqualValues(t, id, form.ID.String())
+
+		req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"id":"nope"}`))
+		errs = JSON(req, &form)
+		if assert.Len(t, errs, 1) {
+			assert.EqualValues(t, []string{"ID"}, errs[0].FieldNames)
+			assert.EqualValues(t, ERR_UUID, errs[0].Classification)
+			assert.EqualValues(t, "uuid.UUID", errs[0].ExpectedType)
+			assert.EqualValues(t, "nope", errs[0].Value)
+		}
+
+		req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"id":"`+id+`","related":["`+id+`","urn:nope"]}`))
+		errs = JSON(req, &uuidForm{})
+		if assert.Len(t, errs, 1) {
+			assert.EqualValues(t, []string{"Related[1]"}, errs[0].FieldNames)
+			assert.EqualValues(t, ERR_UUID, errs[0].Classification)
+		}
+	})
+}
@@ -16,12 +16,19 @@
 package binding
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	chi "github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -509,6 +516,1208 @@ var validationTestCases = []validationTestCase{
 			},
 		},
 	},
+	{
+		description: "UUID rules with valid UUIDs",
+		data: struct {
+			Any   string     `binding:"UUID"`
+			V4    string     `binding:"UUID4"`
+			Typed uuid.UUID  `binding:"UUID4"`
+			Ptr   *uuid.UUID `binding:"UUID"`
+			Str   *string    `binding:"UUID4"`
+			Upper string     `binding:"UUID4"`
+		}{
+			Any:   "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			V4:    "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+			Typed: uuid.MustParse("f47ac10b-58cc-4372-a567-0e02b2c3d479"),
+			Ptr:   uuidPointer("6ba7b810-9dad-11d1-80b4-00c04fd430c8"),
+			Str:   stringPointer("f47ac10b-58cc-4372-a567-0e02b2c3d479"),
+			Upper: "F47AC10B-58CC-4372-A567-0E02B2C3D479",
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "UUID rules with invalid UUIDs",
+		data: struct {
+			Any   string    `binding:"UUID"`
+			V4    string    `binding:"UUID4"`
+			Typed uuid.UUID `binding:"UUID4"`
+			Str   *string   `binding:"UUID"`
+			Bare  string    `binding:"UUID"`
+		}{
+			Any:   "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			V4:    "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			Typed: uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"),
+			Str:   stringPointer("nope"),
+			Bare:  "6ba7b8109dad11d180b400c04fd430c8",
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Any"},
+				Classification: ERR_UUID,
+				Message:        "UUID",
+			},
+			Error{
+				FieldNames:     []string{"V4"},
+				Classification: ERR_UUID,
+				Message:        "UUID4",
+			},
+			Error{
+				FieldNames:     []string{"Typed"},
+				Classification: ERR_UUID,
+				Message:        "UUID4",
+			},
+			Error{
+				FieldNames:     []string{"Str"},
+				Classification: ERR_UUID,
+				Message:        "UUID",
+			},
+			Error{
+				FieldNames:     []string{"Bare"},
+				Classification: ERR_UUID,
+				Message:        "UUID",
+			},
+		},
+	},
+	{
+		description: "Match with matching values",
+		data: struct {
+			Username string  `binding:"Required;Match(^[a-z0-9_]+$);MaxSize(16)"`
+			Code     string  `binding:"Match(^(AB|CD)-\\d{2,4}$)"`
+			Pairs    string  `binding:"Match(^(\\w+=\\w+\\;)*$)"`
+			Nickname *string `binding:"Match(^[a-z]+$)"`
+		}{
+			Username: "jane_doe",
+			Code:     "AB-123",
+			Pairs:    "a=1;b=2;",
+			Nickname: stringPointer("jane"),
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "Match with values not matching",
+		data: struct {
+			Username string  `binding:"Required;Match(^[a-z0-9_]+$);MaxSize(16)"`
+			Code     string  `binding:"Match(^(AB|CD)-\\d{2,4}$)"`
+			Pairs    string  `binding:"Match(^(\\w+=\\w+\\;)*$)"`
+			Nickname *string `binding:"Match(^[a-z]+$)"`
+		}{
+			Username: "Jane Doe",
+			Code:     "EF-1",
+			Pairs:    "a=1,b=2",
+			Nickname: stringPointer("Jane"),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Username"},
+				Classification: ERR_MATCH,
+				Message:        "Match",
+			},
+			Error{
+				FieldNames:     []string{"Code"},
+				Classification: ERR_MATCH,
+				Message:        "Match",
+			},
+			Error{
+				FieldNames:     []string{"Pairs"},
+				Classification: ERR_MATCH,
+				Message:        "Match",
+			},
+			Error{
+				FieldNames:     []string{"Nickname"},
+				Classification: ERR_MATCH,
+				Message:        "Match",
+			},
+		},
+	},
+	{
+		description: "Alpha rules with valid values",
+		data: struct {
+			Alpha        string  `binding:"Alpha"`
+			AlphaNumeric string  `binding:"AlphaNumeric"`
+			AlphaUnicode string  `binding:"AlphaUnicode"`
+			Cyrillic     string  `binding:"AlphaUnicode"`
+			Devanagari   string  `binding:"AlphaUnicode"`
+			Pointer      *string `binding:"Alpha"`
+		}{
+			Alpha:        "Jane",
+			AlphaNumeric: "Jane42",
+			AlphaUnicode: "Zoë",
+			Cyrillic:     "Здравствуйте",
+			Devanagari:   "नमस्ते",
+			Pointer:      stringPointer("Jane"),
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "Alpha rules with invalid values",
+		data: struct {
+			Alpha        string  `binding:"Alpha"`
+			AlphaNumeric string  `binding:"AlphaNumeric"`
+			AlphaUnicode string  `binding:"AlphaUnicode"`
+			Digits       string  `binding:"Alpha"`
+			UnicodeDigit string  `binding:"AlphaUnicode"`
+			Pointer      *string `binding:"Alpha"`
+		}{
+			Alpha:        "Zoë",
+			AlphaNumeric: "Jane_42",
+			AlphaUnicode: "Jane Doe",
+			Digits:       "Jane42",
+			UnicodeDigit: "Zoë2",
+			Pointer:      stringPointer("Jane Doe"),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Alpha"},
+				Classification: ERR_ALPHA,
+				Message:        "Alpha",
+			},
+			Error{
+				FieldNames:     []string{"AlphaNumeric"},
+				Classification: ERR_ALPHA_NUMERIC,
+				Message:        "AlphaNumeric",
+			},
+			Error{
+				FieldNames:     []string{"AlphaUnicode"},
+				Classification: ERR_ALPHA_UNICODE,
+				Message:        "AlphaUnicode",
+			},
+			Error{
+				FieldNames:     []string{"Digits"},
+				Classification: ERR_ALPHA,
+				Message:        "Alpha",
+			},
+			Error{
+				FieldNames:     []string{"UnicodeDigit"},
+				Classification: ERR_ALPHA_UNICODE,
+				Message:        "AlphaUnicode",
+			},
+			Error{
+				FieldNames:     []string{"Pointer"},
+				Classification: ERR_ALPHA,
+				Message:        "Alpha",
+			},
+		},
+	},
+	{
+		description: "Numeric rules with values in range",
+		data: struct {
+			Quantity uint     `binding:"Min(1);Max(100)"`
+			Discount float64  `binding:"Between(0,0.5)"`
+			Offset   int      `binding:"Min(-10)"`
+			Weight   *float32 `binding:"Max(2.5)"`
+			Total    big.Int  `binding:"Max(1000000000000000000000)"`
+			Name     string   `binding:"Min(5)"`
+		}{
+			Quantity: 100,
+			Discount: 0.5,
+			Offset:   -10,
+			Weight:   float32Pointer(2.5),
+			Total:    bigInt("1000000000000000000000"),
+			Name:     "ab",
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "Numeric rules with values out of range",
+		data: struct {
+			Quantity uint     `binding:"Min(1);Max(100)"`
+			Discount float64  `binding:"Between(0,0.5)"`
+			Offset   int      `binding:"Min(-10)"`
+			Weight   *float32 `binding:"Max(2.5)"`
+			Total    big.Int  `binding:"Max(1000000000000000000000)"`
+			Negative float64  `binding:"Between(0,0.5)"`
+			NaN      float64  `binding:"Between(0,0.5)"`
+			Infinity float64  `binding:"Min(0)"`
+		}{
+			Quantity: 101,
+			Discount: 0.51,
+			Offset:   -11,
+			Weight:   float32Pointer(2.51),
+			Total:    bigInt("1000000000000000000001"),
+			Negative: -0.1,
+			NaN:      math.NaN(),
+			Infinity: math.Inf(-1),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Quantity"},
+				Classification: ERR_MAX,
+				Message:        "Max",
+			},
+			Error{
+				FieldNames:     []string{"Discount"},
+				Classification: ERR_BETWEEN,
+				Message:        "Between",
+			},
+			Error{
+				FieldNames:     []string{"Offset"},
+				Classification: ERR_MIN,
+				Message:        "Min",
+			},
+			Error{
+				FieldNames:     []string{"Weight"},
+				Classification: ERR_MAX,
+				Message:        "Max",
+			},
+			Error{
+				FieldNames:     []string{"Total"},
+				Classification: ERR_MAX,
+				Message:        "Max",
+			},
+			Error{
+				FieldNames:     []string{"Negative"},
+				Classification: ERR_BETWEEN,
+				Message:        "Between",
+			},
+			Error{
+				FieldNames:     []string{"NaN"},
+				Classification: ERR_BETWEEN,
+				Message:        "Between",
+			},
+			Error{
+				FieldNames:     []string{"Infinity"},
+				Classification: ERR_MIN,
+				Message:        "Min",
+			},
+		},
+	},
+	{
+		description: "IP rules with valid addresses",
+		data: struct {
+			Any     string  `binding:"IP"`
+			V4      string  `binding:"IPv4"`
+			V6      string  `binding:"IPv6"`
+			Mapped  string  `binding:"IPv6"`
+			Source  string  `binding:"CIDR"`
+			Range   string  `binding:"CIDR"`
+			Typed   net.IP  `binding:"IPv4"`
+			Pointer *string `binding:"IP"`
+			Network *string `binding:"CIDR"`
+		}{
+			Any:     "::1",
+			V4:      "192.168.0.1",
+			V6:      "2001:db8::1",
+			Mapped:  "::ffff:10.0.0.1",
+			Source:  "192.168.0.0/16",
+			Range:   "2001:db8::/32",
+			Typed:   net.ParseIP("10.0.0.1"),
+			Pointer: stringPointer("10.0.0.1"),
+			Network: stringPointer("10.0.0.0/8"),
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "IP rules with invalid addresses",
+		data: struct {
+			Any     string  `binding:"IP"`
+			V4      string  `binding:"IPv4"`
+			V6      string  `binding:"IPv6"`
+			Source  string  `binding:"CIDR"`
+			Range   string  `binding:"CIDR"`
+			Pointer *string `binding:"IPv4"`
+		}{
+			Any:     "localhost",
+			V4:      "2001:db8::1",
+			V6:      "192.168.0.1",
+			Source:  "192.168.0.1",
+			Range:   "10.0.0.0/33",
+			Pointer: stringPointer("10.0.0.256"),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Any"},
+				Classification: ERR_IP,
+				Message:        "IP",
+			},
+			Error{
+				FieldNames:     []string{"V4"},
+				Classification: ERR_IP,
+				Message:        "IPv4",
+			},
+			Error{
+				FieldNames:     []string{"V6"},
+				Classification: ERR_IP,
+				Message:        "IPv6",
+			},
+			Error{
+				FieldNames:     []string{"Source"},
+				Classification: ERR_CIDR,
+				Message:        "CIDR",
+			},
+			Error{
+				FieldNames:     []string{"Range"},
+				Classification: ERR_CIDR,
+				Message:        "CIDR",
+			},
+			Error{
+				FieldNames:     []string{"Pointer"},
+				Classification: ERR_IP,
+				Message:        "IPv4",
+			},
+		},
+	},
+	{
+		description: "MAC with valid addresses",
+		data: struct {
+			Colons   string           `binding:"Required;MAC"`
+			Dashes   string           `binding:"MAC"`
+			Dots     string           `binding:"MAC"`
+			EUI64    string           `binding:"MAC"`
+			Hardware net.HardwareAddr `binding:"MAC"`
+			Pointer  *string          `binding:"MAC"`
+		}{
+			Colons:   "00:00:5e:00:53:01",
+			Dashes:   "00-00-5E-00-53-01",
+			Dots:     "0000.5e00.5301",
+			EUI64:    "02:00:5e:10:00:00:00:01",
+			Hardware: net.HardwareAddr{0x02, 0x00, 0x5e, 0x10, 0x00, 0x00, 0x00, 0x01},
+			Pointer:  stringPointer("00:00:5e:00:53:01"),
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "MAC with invalid addresses",
+		data: struct {
+			Short   string  `binding:"Required;MAC"`
+			Letters string  `binding:"MAC"`
+			Long    string  `binding:"MAC"`
+			Pointer *string `binding:"MAC"`
+		}{
+			Short:   "00:00:5e:00:53",
+			Letters: "00:00:5e:00:53:zz",
+			Long:    "00:00:5e:00:53:01:02",
+			Pointer: stringPointer("00:00:5e"),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Short"},
+				Classification: ERR_MAC,
+				Message:        "MAC",
+			},
+			Error{
+				FieldNames:     []string{"Letters"},
+				Classification: ERR_MAC,
+				Message:        "MAC",
+			},
+			Error{
+				FieldNames:     []string{"Long"},
+				Classification: ERR_MAC,
+				Message:        "MAC",
+			},
+			Error{
+				FieldNames:     []string{"Pointer"},
+				Classification: ERR_MAC,
+				Message:        "MAC",
+			},
+		},
+	},
+	{
+		description: "Host name rules with valid names",
+		data: struct {
+			Local     string  `binding:"Hostname"`
+			Dotted    string  `binding:"Hostname"`
+			Digit     string  `binding:"Hostname"`
+			LongLabel string  `binding:"Hostname"`
+			Domain    string  `binding:"FQDN"`
+			Rooted    string  `binding:"FQDN"`
+			Punycode  string  `binding:"FQDN"`
+			Nested    string  `binding:"FQDN"`
+			Label     string  `binding:"DNSLabel"`
+			Letter    string  `binding:"DNSLabel"`
+			Numeric   string  `binding:"DNSLabel"`
+			Longest   string  `binding:"DNSLabel"`
+			Host      *string `binding:"Hostname"`
+			FQDN      *string `binding:"FQDN"`
+			Team      *string `binding:"DNSLabel"`
+		}{
+			Local:     "localhost",
+			Dotted:    "www.example.com",
+			Digit:     "1host.example",
+			LongLabel: strings.Repeat("a", 63) + ".example",
+			Domain:    "example.com",
+			Rooted:    "www.example.com.",
+			Punycode:  "xn--bcher-kva.example",
+			Nested:    "a.b.c.io",
+			Label:     "my-team",
+			Letter:    "a",
+			Numeric:   "123",
+			Longest:   strings.Repeat("a", 63),
+			Host:      stringPointer("localhost"),
+			FQDN:      stringPointer("example.com"),
+			Team:      stringPointer("my-team"),
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "Host name rules with invalid names",
+		data: struct {
+			Leading    string  `binding:"Hostname"`
+			Underscore string  `binding:"Hostname"`
+			Trailing   string  `binding:"Hostname"`
+			Space      string  `binding:"Hostname"`
+			TooLong    string  `binding:"Hostname"`
+			Single     string  `binding:"FQDN"`
+			Address    string  `binding:"FQDN"`
+			Empty      string  `binding:"FQDN"`
+			Spaced     string  `binding:"FQDN"`
+			Hyphen     string  `binding:"FQDN"`
+			Snake      string  `binding:"DNSLabel"`
+			Lead       string  `binding:"DNSLabel"`
+			Trail      string  `binding:"DNSLabel"`
+			Long       string  `binding:"DNSLabel"`
+			Dot        string  `binding:"DNSLabel"`
+			Host       *string `binding:"Hostname"`
+			FQDN       *string `binding:"FQDN"`
+			Team       *string `binding:"DNSLabel"`
+		}{
+			Leading:    "-lead.example",
+			Underscore: "under_score.example",
+			Trailing:   "trailing-.example",
+			Space:      "sp ace",
+			TooLong:    strings.Repeat("a.", 127) + "ab",
+			Single:     "localhost",
+			Address:    "10.0.0.1",
+			Empty:      "example..com",
+			Spaced:     "exa mple.com",
+			Hyphen:     "-example.com",
+			Snake:      "my_team",
+			Lead:       "-team",
+			Trail:      "team-",
+			Long:       strings.Repeat("a", 64),
+			Dot:        "a.b",
+			Host:       stringPointer("sp ace"),
+			FQDN:       stringPointer("localhost"),
+			Team:       stringPointer("my_team"),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Leading"},
+				Classification: ERR_HOSTNAME,
+				Message:        "Hostname",
+			},
+			Error{
+				FieldNames:     []string{"Underscore"},
+				Classification: ERR_HOSTNAME,
+				Message:        "Hostname",
+			},
+			Error{
+				FieldNames:     []string{"Trailing"},
+				Classification: ERR_HOSTNAME,
+				Message:        "Hostname",
+			},
+			Error{
+				FieldNames:     []string{"Space"},
+				Classification: ERR_HOSTNAME,
+				Message:        "Hostname",
+			},
+			Error{
+				FieldNames:     []string{"TooLong"},
+				Classification: ERR_HOSTNAME,
+				Message:        "Hostname",
+			},
+			Error{
+				FieldNames:     []string{"Single"},
+				Classification: ERR_FQDN,
+				Message:        "FQDN",
+			},
+			Error{
+				FieldNames:     []string{"Address"},
+				Classification: ERR_FQDN,
+				Message:        "FQDN",
+			},
+			Error{
+				FieldNames:     []string{"Empty"},
+				Classification: ERR_FQDN,
+				Message:        "FQDN",
+			},
+			Error{
+				FieldNames:     []string{"Spaced"},
+				Classification: ERR_FQDN,
+				Message:        "FQDN",
+			},
+			Error{
+				FieldNames:     []string{"Hyphen"},
+				Classification: ERR_FQDN,
+				Message:        "FQDN",
+			},
+			Error{
+				FieldNames:     []string{"Snake"},
+				Classification: ERR_DNS_LABEL,
+				Message:        "DNSLabel",
+			},
+			Error{
+				FieldNames:     []string{"Lead"},
+				Classification: ERR_DNS_LABEL,
+				Message:        "DNSLabel",
+			},
+			Error{
+				FieldNames:     []string{"Trail"},
+				Classification: ERR_DNS_LABEL,
+				Message:        "DNSLabel",
+			},
+			Error{
+				FieldNames:     []string{"Long"},
+				Classification: ERR_DNS_LABEL,
+				Message:        "DNSLabel",
+			},
+			Error{
+				FieldNames:     []string{"Dot"},
+				Classification: ERR_DNS_LABEL,
+				Message:        "DNSLabel",
+			},
+			Error{
+				FieldNames:     []string{"Host"},
+				Classification: ERR_HOSTNAME,
+				Message:        "Hostname",
+			},
+			Error{
+				FieldNames:     []string{"FQDN"},
+				Classification: ERR_FQDN,
+				Message:        "FQDN",
+			},
+			Error{
+				FieldNames:     []string{"Team"},
+				Classification: ERR_DNS_LABEL,
+				Message:        "DNSLabel",
+			},
+		},
+	},
+	{
+		description: "Port rules with valid ports",
+		data: struct {
+			Lowest    int     `binding:"Port"`
+			Highest   string  `binding:"Port"`
+			Alternate uint16  `binding:"Port"`
+			Named     string  `binding:"HostPort"`
+			Address   string  `binding:"HostPort"`
+			Bracketed string  `binding:"HostPort"`
+			Local     string  `binding:"HostPort"`
+			Listen    *int    `binding:"Port"`
+			Upstream  *string `binding:"HostPort"`
+		}{
+			Lowest:    1,
+			Highest:   "65535",
+			Alternate: 8080,
+			Named:     "example.com:443",
+			Address:   "10.0.0.1:8080",
+			Bracketed: "[2001:db8::1]:53",
+			Local:     "localhost:1",
+			Listen:    intPointer(443),
+			Upstream:  stringPointer("example.com:443"),
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "Port rules with invalid ports",
+		data: struct {
+			TooHigh     int     `binding:"Port"`
+			Zero        string  `binding:"Port"`
+			Negative    int     `binding:"Port"`
+			Signed      string  `binding:"Port"`
+			Scheme      string  `binding:"Port"`
+			Spaced      string  `binding:"Port"`
+			Listen      *int    `binding:"Port"`
+			NoPort      string  `binding:"HostPort"`
+			Unbracketed string  `binding:"HostPort"`
+			PortZero    string  `binding:"HostPort"`
+			NoHost      string  `binding:"HostPort"`
+			BadHost     string  `binding:"HostPort"`
+			Upstream    *string `binding:"HostPort"`
+		}{
+			TooHigh:     65536,
+			Zero:        "0",
+			Negative:    -1,
+			Signed:      "+80",
+			Scheme:      "http",
+			Spaced:      "8080 ",
+			Listen:      intPointer(70000),
+			NoPort:      "example.com",
+			Unbracketed: "2001:db8::1:53",
+			PortZero:    "example.com:0",
+			NoHost:      ":8080",
+			BadHost:     "exa_mple.com:80",
+			Upstream:    stringPointer("example.com"),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"TooHigh"},
+				Classification: ERR_PORT,
+				Message:        "Port",
+			},
+			Error{
+				FieldNames:     []string{"Zero"},
+				Classification: ERR_PORT,
+				Message:        "Port",
+			},
+			Error{
+				FieldNames:     []string{"Negative"},
+				Classification: ERR_PORT,
+				Message:        "Port",
+			},
+			Error{
+				FieldNames:     []string{"Signed"},
+				Classification: ERR_PORT,
+				Message:        "Port",
+			},
+			Error{
+				FieldNames:     []string{"Scheme"},
+				Classification: ERR_PORT,
+				Message:        "Port",
+			},
+			Error{
+				FieldNames:     []string{"Spaced"},
+				Classification: ERR_PORT,
+				Message:        "Port",
+			},
+			Error{
+				FieldNames:     []string{"Listen"},
+				Classification: ERR_PORT,
+				Message:        "Port",
+			},
+			Error{
+				FieldNames:     []string{"NoPort"},
+				Classification: ERR_HOST_PORT,
+				Message:        "HostPort",
+			},
+			Error{
+				FieldNames:     []string{"Unbracketed"},
+				Classification: ERR_HOST_PORT,
+				Message:        "HostPort",
+			},
+			Error{
+				FieldNames:     []string{"PortZero"},
+				Classification: ERR_HOST_PORT,
+				Message:        "HostPort",
+			},
+			Error{
+				FieldNames:     []string{"NoHost"},
+				Classification: ERR_HOST_PORT,
+				Message:        "HostPort",
+			},
+			Error{
+				FieldNames:     []string{"BadHost"},
+				Classification: ERR_HOST_PORT,
+				Message:        "HostPort",
+			},
+			Error{
+				FieldNames:     []string{"Upstream"},
+				Classification: ERR_HOST_PORT,
+				Message:        "HostPort",
+			},
+		},
+	},
+	{
+		description: "Json with valid JSON",
+		data: struct {
+			Object   string          `binding:"Required;Json"`
+			Array    string          `binding:"Json"`
+			Text     string          `binding:"Json"`
+			Null     string          `binding:"Json"`
+			Number   string          `binding:"Json"`
+			Defaults json.RawMessage `binding:"Json"`
+			Extra    []byte          `binding:"Json"`
+			Pointer  *string         `binding:"Json"`
+		}{
+			Object:   `{"retries": 3}`,
+			Array:    `[1, 2]`,
+			Text:     `"text"`,
+			Null:     ` null `,
+			Number:   `42`,
+			Defaults: json.RawMessage(`{}`),
+			Extra:    []byte(`[]`),
+			Pointer:  stringPointer(`{}`),
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "Json with invalid JSON",
+		data: struct {
+			Trailing   string          `binding:"Required;Json"`
+			Unbalanced string          `binding:"Json"`
+			Missing    string          `binding:"Json"`
+			Comma      string          `binding:"Json"`
+			Quotes     string          `binding:"Json"`
+			Octal      string          `binding:"Json"`
+			Defaults   json.RawMessage `binding:"Json"`
+			Extra      []byte          `binding:"Json"`
+			Pointer    *string         `binding:"Json"`
+		}{
+			Trailing:   `{"retries": 3} x`,
+			Unbalanced: `{"retries": 3}}`,
+			Missing:    `{"retries": }`,
+			Comma:      `[1, 2,]`,
+			Quotes:     `{'a': 1}`,
+			Octal:      `01`,
+			Defaults:   json.RawMessage(`{`),
+			Extra:      []byte(`nul`),
+			Pointer:    stringPointer(`{`),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Trailing"},
+				Classification: ERR_JSON,
+				Message:        "Json",
+			},
+			Error{
+				FieldNames:     []string{"Unbalanced"},
+				Classification: ERR_JSON,
+				Message:        "Json",
+			},
+			Error{
+				FieldNames:     []string{"Missing"},
+				Classification: ERR_JSON,
+				Message:        "Json",
+			},
+			Error{
+				FieldNames:     []string{"Comma"},
+				Classification: ERR_JSON,
+				Message:        "Json",
+			},
+			Error{
+				FieldNames:     []string{"Quotes"},
+				Classification: ERR_JSON,
+				Message:        "Json",
+			},
+			Error{
+				FieldNames:     []string{"Octal"},
+				Classification: ERR_JSON,
+				Message:        "Json",
+			},
+			Error{
+				FieldNames:     []string{"Defaults"},
+				Classification: ERR_JSON,
+				Message:        "Json",
+			},
+			Error{
+				FieldNames:     []string{"Extra"},
+				Classification: ERR_JSON,
+				Message:        "Json",
+			},
+			Error{
+				FieldNames:     []string{"Pointer"},
+				Classification: ERR_JSON,
+				Message:        "Json",
+			},
+		},
+	},
+	{
+		description: "Encoding rules with valid values",
+		data: struct {
+			Data1   string  `binding:"Base64"`
+			Data2   string  `binding:"Base64"`
+			Data3   string  `binding:"Base64"`
+			Token1  string  `binding:"Base64URL"`
+			Token2  string  `binding:"Base64URL"`
+			Token3  string  `binding:"Base64URL"`
+			Digest1 string  `binding:"Hexadecimal"`
+			Digest2 string  `binding:"Hexadecimal"`
+			Digest3 string  `binding:"Hexadecimal"`
+			Color1  string  `binding:"HexColor"`
+			Color2  string  `binding:"HexColor"`
+			Color3  string  `binding:"HexColor"`
+			Pointer *string `binding:"Base64"`
+		}{
+			Data1:   "aGVsbG8=",
+			Data2:   "+/+/",
+			Data3:   "aGk=",
+			Token1:  "aGVsbG8",
+			Token2:  "-_-_",
+			Token3:  "aGk=",
+			Digest1: "deadBEEF",
+			Digest2: "0x1F",
+			Digest3: "7",
+			Color1:  "#fff",
+			Color2:  "#1A2b3C",
+			Color3:  "#000000",
+			Pointer: stringPointer("aGk="),
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "Encoding rules with invalid values",
+		data: struct {
+			Data1   string  `binding:"Base64"`
+			Data2   string  `binding:"Base64"`
+			Data3   string  `binding:"Base64"`
+			Data4   string  `binding:"Base64"`
+			Token1  string  `binding:"Base64URL"`
+			Token2  string  `binding:"Base64URL"`
+			Token3  string  `binding:"Base64URL"`
+			Token4  string  `binding:"Base64URL"`
+			Digest1 string  `binding:"Hexadecimal"`
+			Digest2 string  `binding:"Hexadecimal"`
+			Digest3 string  `binding:"Hexadecimal"`
+			Digest4 string  `binding:"Hexadecimal"`
+			Color1  string  `binding:"HexColor"`
+			Color2  string  `binding:"HexColor"`
+			Color3  string  `binding:"HexColor"`
+			Color4  string  `binding:"HexColor"`
+			Pointer *string `binding:"HexColor"`
+		}{
+			Data1:   "aGVsbG8",
+			Data2:   "-_-_",
+			Data3:   "aGVs\nbG8=",
+			Data4:   "aGk=aGk=",
+			Token1:  "aGVsbG8+",
+			Token2:  "aGk==",
+			Token3:  "aGk=x",
+			Token4:  "aGl",
+			Digest1: "0x",
+			Digest2: "xyz",
+			Digest3: "12 34",
+			Digest4: "-1",
+			Color1:  "fff",
+			Color2:  "#ffff",
+			Color3:  "#ggg",
+			Color4:  "#12345",
+			Pointer: stringPointer("fff"),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Data1"},
+				Classification: ERR_BASE64,
+				Message:        "Base64",
+			},
+			Error{
+				FieldNames:     []string{"Data2"},
+				Classification: ERR_BASE64,
+				Message:        "Base64",
+			},
+			Error{
+				FieldNames:     []string{"Data3"},
+				Classification: ERR_BASE64,
+				Message:        "Base64",
+			},
+			Error{
+				FieldNames:     []string{"Data4"},
+				Classification: ERR_BASE64,
+				Message:        "Base64",
+			},
+			Error{
+				FieldNames:     []string{"Token1"},
+				Classification: ERR_BASE64_URL,
+				Message:        "Base64URL",
+			},
+			Error{
+				FieldNames:     []string{"Token2"},
+				Classification: ERR_BASE64_URL,
+				Message:        "Base64URL",
+			},
+			Error{
+				FieldNames:     []string{"Token3"},
+				Classification: ERR_BASE64_URL,
+				Message:        "Base64URL",
+			},
+			Error{
+				FieldNames:     []string{"Token4"},
+				Classification: ERR_BASE64_URL,
+				Message:        "Base64URL",
+			},
+			Error{
+				FieldNames:     []string{"Digest1"},
+				Classification: ERR_HEXADECIMAL,
+				Message:        "Hexadecimal",
+			},
+			Error{
+				FieldNames:     []string{"Digest2"},
+				Classification: ERR_HEXADECIMAL,
+				Message:        "Hexadecimal",
+			},
+			Error{
+				FieldNames:     []string{"Digest3"},
+				Classification: ERR_HEXADECIMAL,
+				Message:        "Hexadecimal",
+			},
+			Error{
+				FieldNames:     []string{"Digest4"},
+				Classification: ERR_HEXADECIMAL,
+				Message:        "Hexadecimal",
+			},
+			Error{
+				FieldNames:     []string{"Color1"},
+				Classification: ERR_HEX_COLOR,
+				Message:        "HexColor",
+			},
+			Error{
+				FieldNames:     []string{"Color2"},
+				Classification: ERR_HEX_COLOR,
+				Message:        "HexColor",
+			},
+			Error{
+				FieldNames:     []string{"Color3"},
+				Classification: ERR_HEX_COLOR,
+				Message:        "HexColor",
+			},
+			Error{
+				FieldNames:     []string{"Color4"},
+				Classification: ERR_HEX_COLOR,
+				Message:        "HexColor",
+			},
+			Error{
+				FieldNames:     []string{"Pointer"},
+				Classification: ERR_HEX_COLOR,
+				Message:        "HexColor",
+			},
+		},
+	},
+	{
+		description: "CreditCard with valid card numbers",
+		data: struct {
+			Card1     string  `binding:"CreditCard"`
+			Card2     string  `binding:"CreditCard"`
+			Card3     string  `binding:"CreditCard"`
+			Card4     string  `binding:"CreditCard"`
+			Accepted1 string  `binding:"CreditCard(visa, mastercard)"`
+			Accepted2 string  `binding:"CreditCard(visa, mastercard)"`
+			Accepted3 string  `binding:"CreditCard(visa, mastercard)"`
+			Accepted4 string  `binding:"CreditCard(visa, mastercard)"`
+			Pointer   *string `binding:"CreditCard"`
+		}{
+			Card1:     "4111111111111111",
+			Card2:     "378282246310005",
+			Card3:     "6011111111111117",
+			Card4:     "3530111333300000",
+			Accepted1: "4111 1111 1111 1111",
+			Accepted2: "5555-5555-5555-4444",
+			Accepted3: "2223003122003222",
+			Accepted4: "4012888888881881",
+			Pointer:   stringPointer("4111111111111111"),
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "CreditCard with invalid card numbers",
+		data: struct {
+			Card1     string  `binding:"CreditCard"`
+			Card2     string  `binding:"CreditCard"`
+			Card3     string  `binding:"CreditCard"`
+			Card4     string  `binding:"CreditCard"`
+			Accepted1 string  `binding:"CreditCard(visa, mastercard)"`
+			Accepted2 string  `binding:"CreditCard(visa, mastercard)"`
+			Accepted3 string  `binding:"CreditCard(visa, mastercard)"`
+			Accepted4 string  `binding:"CreditCard(visa, mastercard)"`
+			Pointer   *string `binding:"CreditCard"`
+		}{
+			Card1:     "4111111111111112",
+			Card2:     "411111111111111a",
+			Card3:     "41111111111",
+			Card4:     "4111_1111_1111_1111",
+			Accepted1: "378282246310005",
+			Accepted2: "6011111111111117",
+			Accepted3: "4111111111111112",
+			Accepted4: "2721000000000004",
+			Pointer:   stringPointer("4111111111111112"),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Card1"},
+				Classification: ERR_CREDIT_CARD,
+				Message:        "CreditCard",
+			},
+			Error{
+				FieldNames:     []string{"Card2"},
+				Classification: ERR_CREDIT_CARD,
+				Message:        "CreditCard",
+			},
+			Error{
+				FieldNames:     []string{"Card3"},
+				Classification: ERR_CREDIT_CARD,
+				Message:        "CreditCard",
+			},
+			Error{
+				FieldNames:     []string{"Card4"},
+				Classification: ERR_CREDIT_CARD,
+				Message:        "CreditCard",
+			},
+			Error{
+				FieldNames:     []string{"Accepted1"},
+				Classification: ERR_CREDIT_CARD,
+				Message:        "CreditCard",
+			},
+			Error{
+				FieldNames:     []string{"Accepted2"},
+				Classification: ERR_CREDIT_CARD,
+				Message:        "CreditCard",
+			},
+			Error{
+				FieldNames:     []string{"Accepted3"},
+				Classification: ERR_CREDIT_CARD,
+				Message:        "CreditCard",
+			},
+			Error{
+				FieldNames:     []string{"Accepted4"},
+				Classification: ERR_CREDIT_CARD,
+				Message:        "CreditCard",
+			},
+			Error{
+				FieldNames:     []string{"Pointer"},
+				Classification: ERR_CREDIT_CARD,
+				Message:        "CreditCard",
+			},
+		},
+	},
+	{
+		description: "Phone with valid numbers",
+		data: struct {
+			Mobile1 string  `binding:"Required;Phone"`
+			Mobile2 string  `binding:"Required;Phone"`
+			Mobile3 string  `binding:"Required;Phone"`
+			Mobile4 string  `binding:"Required;Phone"`
+			Home1   string  `binding:"Phone(national)"`
+			Home2   string  `binding:"Phone(national)"`
+			Home3   string  `binding:"Phone(national)"`
+			Home4   string  `binding:"Phone(national)"`
+			Pointer *string `binding:"Phone"`
+		}{
+			Mobile1: "+14155552671",
+			Mobile2: "+493012345678",
+			Mobile3: "+442071838750",
+			Mobile4: "+861012345678",
+			Home1:   "(415) 555-2671",
+			Home2:   "030 1234567",
+			Home3:   "+44 20 7183 8750",
+			Home4:   "415.555.2671",
+			Pointer: stringPointer("+14155552671"),
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "Phone with invalid numbers",
+		data: struct {
+			Mobile1 string  `binding:"Required;Phone"`
+			Mobile2 string  `binding:"Required;Phone"`
+			Mobile3 string  `binding:"Required;Phone"`
+			Mobile4 string  `binding:"Required;Phone"`
+			Mobile5 string  `binding:"Required;Phone"`
+			Home1   string  `binding:"Phone(national)"`
+			Home2   string  `binding:"Phone(national)"`
+			Home3   string  `binding:"Phone(national)"`
+			Home4   string  `binding:"Phone(national)"`
+			Home5   string  `binding:"Phone(national)"`
+			Pointer *string `binding:"Phone"`
+		}{
+			Mobile1: "14155552671",
+			Mobile2: "+04155552671",
+			Mobile3: "+1 415 555 2671",
+			Mobile4: "+1234567890123456",
+			Mobile5: "+1",
+			Home1:   "555-267",
+			Home2:   "call 415 555 2671",
+			Home3:   "+1 (415) 555-2671 ext. 3",
+			Home4:   "1234567890123456",
+			Home5:   "++14155552671",
+			Pointer: stringPointer("14155552671"),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Mobile1"},
+				Classification: ERR_PHONE,
+				Message:        "Phone",
+			},
+			Error{
+				FieldNames:     []string{"Mobile2"},
+				Classification: ERR_PHONE,
+				Message:        "Phone",
+			},
+			Error{
+				FieldNames:     []string{"Mobile3"},
+				Classification: ERR_PHONE,
+				Message:        "Phone",
+			},
+			Error{
+				FieldNames:     []string{"Mobile4"},
+				Classification: ERR_PHONE,
+				Message:        "Phone",
+			},
+			Error{
+				FieldNames:     []string{"Mobile5"},
+				Classification: ERR_PHONE,
+				Message:        "Phone",
+			},
+			Error{
+				FieldNames:     []string{"Home1"},
+				Classification: ERR_PHONE,
+				Message:        "Phone",
+			},
+			Error{
+				FieldNames:     []string{"Home2"},
+				Classification: ERR_PHONE,
+				Message:        "Phone",
+			},
+			Error{
+				FieldNames:     []string{"Home3"},
+				Classification: ERR_PHONE,
+				Message:        "Phone",
+			},
+			Error{
+				FieldNames:     []string{"Home4"},
+				Classification: ERR_PHONE,
+				Message:        "Phone",
+			},
+			Error{
+				FieldNames:     []string{"Home5"},
+				Classification: ERR_PHONE,
+				Message:        "Phone",
+			},
+			Error{
+				FieldNames:     []string{"Pointer"},
+				Classification: ERR_PHONE,
+				Message:        "Phone",
+			},
+		},
+	},
+	{
+		description: "Scheme with allowed schemes",
+		data: struct {
+			Homepage string  `binding:"Scheme(http,https)"`
+			Upper    string  `binding:"Scheme(http, https)"`
+			Pointer  *string `binding:"Scheme(mailto)"`
+		}{
+			Homepage: "https://example.com",
+			Upper:    "HTTP://example.com",
+			Pointer:  stringPointer("mailto:jane@example.com"),
+		},
+		expectedErrors: Errors{},
+	},
+	{
+		description: "Scheme with other schemes",
+		data: struct {
+			Homepage string  `binding:"Scheme(http,https)"`
+			Pointer  *string `binding:"Scheme(https)"`
+		}{
+			Homepage: "ftp://example.com",
+			Pointer:  stringPointer("http://example.com"),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Homepage"},
+				Classification: ERR_SCHEME,
+				Message:        "Scheme",
+			},
+			Error{
+				FieldNames:     []string{"Pointer"},
+				Classification: ERR_SCHEME,
+				Message:        "Scheme",
+			},
+		},
+	},
+	{
+		description: "Email with a pointer field",
+		data: struct {
+			Valid   *string `binding:"Email"`
+			Invalid *string `binding:"Email"`
+		}{
+			Valid:   stringPointer("jane@example.com"),
+			Invalid: stringPointer("jane"),
+		},
+		expectedErrors: Errors{
+			Error{
+				FieldNames:     []string{"Invalid"},
+				Classification: ERR_EMAIL,
+				Message:        "Email",
+			},
+		},
+	},
 }
 
 func Test_Validation(t *testing.T) {
@@ -548,3 +1757,87 @@ type (
 		expectedErrors Errors
 	}
 )
+
+// panicValue returns the value f panics with, formatted.
+func panicValue(f func()) (v string) {
+	defer func() {
+		v = fmt.Sprint(recover())
+	}()
+	f()
+	return ""
+}
+
+func stringPointer(s string) *string {
+	return &s
+}
+
+func float32Pointer(f float32) *float32 {
+	return &f
+}
+
+func bigInt(s string) big.Int {
+	n, _ := new(big.Int).SetString(s, 10)
+	return *n
+}
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func uuidPointer(s string) *uuid.UUID {
+	id := uuid.MustParse(s)
+	return &id
+}
+
+func Test_SearchQuery(t *testing.T) {
+	assert.EqualValues(t, "foo bar", SanitizeSearchQuery("  foo\x00 bar\t\n", 0, false))
+	assert.EqualValues(t, "héllo", SanitizeSearchQuery("héllo wörld", 5, false))
+	assert.EqualValues(t, `50\% off\_sale\*`, SanitizeSearchQuery("50% off_sale*", 0, true))
+	assert.EqualValues(t, `50\%`, SanitizeSearchQuery("50% off_sale*", 4, true))
+	assert.EqualValues(t, `50`, SanitizeSearchQuery("50% off_sale*", 3, true))
+	assert.EqualValues(t, `50\% off`, SanitizeSearchQuery("50% off_sale*", 8, true))
+
+	term := " 100%_match "
+	form := struct {
+		Term    string  `binding:"SearchQuery(8,escape)"`
+		Inner   struct{ Term string `binding:"SearchQuery"` }
+		Ignored int     `binding:"SearchQuery"`
+		Pointer *string `binding:"SearchQuery(8,escape)"`
+	}{Term: " 100%_match ", Ignored: 1, Pointer: &term}
+	form.Inner.Term = "\x1b[31m red "
+
+	errs := Validate(nil, &form)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, `100\%\_m`, form.Term)
+	assert.EqualValues(t, "[31m red", form.Inner.Term)
+	assert.EqualValues(t, `100\%\_m`, *form.Pointer)
+
+	errs = Validate(nil, struct {
+		Term string `binding:"SearchQuery"`
+	}{" term "})
+	assert.True(t, errs.Has(ERR_SEARCH_QUERY))
+}
+
+func Test_InvalidRuleParameters(t *testing.T) {
+	type pattern struct {
+		Name string `binding:"Match([a-z)"`
+	}
+	const msg = "binding: invalid tags of binding.pattern.Name: Match([a-z): error parsing regexp"
+	assert.Contains(t, panicValue(func() { RawValidate(pattern{"x"}) }), msg)
+	assert.Contains(t, panicValue(func() { RawValidate(pattern{}) }), msg)
+
+	// Parameters are checked whatever the value, so zero values panic too.
+	for rule, msg := range map[string]string{
+		"Min(x)":           `Min(x): invalid number "x"`,
+		"Max()":            `Max(): invalid number ""`,
+		"Between(1)":       "Between(1): expected 2 numbers",
+		"Between(1,2,3)":   "Between(1,2,3): expected 2 numbers",
+		"CreditCard(vsia)": `CreditCard(vsia): unknown card brand "vsia"`,
+		"Phone(local)":     `Phone(local): unknown phone number format "local"`,
+		"MaxDuration(1d)":  "MaxDuration(1d): time: ",
+		"MaxBits(64b)":     `MaxBits(64b): invalid number of bits "64b"`,
+	} {
+		typ := reflect.StructOf([]reflect.StructField{{Name: "Count", Type: reflect.TypeOf(0), Tag: reflect.StructTag(`binding:"` + rule + `"`)}})
+		assert.Contains(t, panicValue(func() { RawValidate(reflect.New(typ).Elem().Interface()) }), msg, rule)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+type (
+	// BodyVerifier checks the raw request body, e.g. a webhook signature,
+	// before it is decoded. The body is the one sent, before a
+	// Content-Encoding such as gzip is removed. It is buffered by the
+	// package, so it can still be decoded afterwards.
+	BodyVerifier interface {
+		VerifyBody(req *http.Request, body []byte) error
+	}
+
+	// BodyVerifierFunc is an adapter to use ordinary functions as
+	// BodyVerifier.
+	BodyVerifierFunc func(req *http.Request, body []byte) error
+)
+
+// VerifyBody calls f(req, body).
+func (f BodyVerifierFunc) VerifyBody(req *http.Request, body []byte) error {
+	return f(req, body)
+}
+
+var bodyVerifiers []BodyVerifier
+
+// AddBodyVerifier registers a verifier that runs for every bound request.
+// Bound structs may also implement BodyVerifier themselves, in which case
+// they are verified after the registered verifiers.
+func AddBodyVerifier(v BodyVerifier) {
+	bodyVerifiers = append(bodyVerifiers, v)
+}
+
+// verifyBody buffers the body of req and runs the verifiers that apply
+// to obj over it. A failed verification is reported as ERR_SIGNATURE.
+func verifyBody(req *http.Request, obj interface{}) Errors {
+	verifiers := bodyVerifiers
+	if v, ok := obj.(BodyVerifier); ok {
+		verifiers = append(verifiers[:len(verifiers):len(verifiers)], v)
+	}
+	if len(verifiers) == 0 {
+		return nil
+	}
+
+	var errs Errors
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			errs.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+			return errs
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	for _, v := range verifiers {
+		if err := v.VerifyBody(req, body); err != nil {
+			errs.Add([]string{}, ERR_SIGNATURE, err.Error())
+			return errs
+		}
+	}
+	return nil
+}
+
+// HMACVerifier returns a verifier that checks the hex encoded HMAC of the
+// body, keyed with secret, against the value of header. A prefix naming
+// the algorithm, as in "sha256=...", is ignored.
+func HMACVerifier(header string, secret []byte, h func() hash.Hash) BodyVerifier {
+	return BodyVerifierFunc(func(req *http.Request, body []byte) error {
+		signature := req.Header.Get(header)
+		if i := strings.IndexByte(signature, '='); i >= 0 {
+			signature = signature[i+1:]
+		}
+		if len(signature) == 0 {
+			return errors.New("Missing signature")
+		}
+		expected, err := hex.DecodeString(signature)
+		if err != nil {
+			return errors.New("Malformed signature")
+		}
+		mac := hmac.New(h, secret)
+		mac.Write(body)
+		if !hmac.Equal(mac.Sum(nil), expected) {
+			return errors.New("Invalid signature")
+		}
+		return nil
+	})
+}
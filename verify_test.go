@@ -0,0 +1,77 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type webhook struct {
+	Action string `json:"action" binding:"Required"`
+}
+
+func (webhook) VerifyBody(req *http.Request, body []byte) error {
+	return HMACVerifier("X-Hub-Signature-256", []byte("secret"), sha256.New).VerifyBody(req, body)
+}
+
+func Test_BodyVerifier(t *testing.T) {
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	newRequest := func(body, signature string) *http.Request {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if len(signature) > 0 {
+			req.Header.Set("X-Hub-Signature-256", signature)
+		}
+		return req
+	}
+
+	var hook webhook
+	body := `{"action":"opened"}`
+	errs := Bind(newRequest(body, sign(body)), &hook)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "opened", hook.Action)
+
+	for _, signature := range []string{"", "sha256=zz", sign(`{"action":"closed"}`)} {
+		hook = webhook{}
+		errs = Bind(newRequest(body, signature), &hook)
+		assert.Len(t, errs, 1)
+		assert.EqualValues(t, ERR_SIGNATURE, errs[0].Classification)
+		assert.Empty(t, hook.Action)
+	}
+
+	defer func() { bodyVerifiers = nil }()
+	var verified []string
+	AddBodyVerifier(BodyVerifierFunc(func(req *http.Request, body []byte) error {
+		verified = append(verified, string(body))
+		return nil
+	}))
+	var post Post
+	req := newRequest(`{"title":"Hello, world"}`, "")
+	errs = Bind(req, &post)
+	assert.Empty(t, errs)
+	assert.EqualValues(t, "Hello, world", post.Title)
+	assert.EqualValues(t, []string{`{"title":"Hello, world"}`}, verified)
+}
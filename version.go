@@ -0,0 +1,85 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"mime"
+	"net/http"
+	"reflect"
+)
+
+// Versions binds requests into one of several registered struct versions,
+// selected by a request header or by the version parameter of the media
+// type, e.g. "application/vnd.api+json;version=2".
+type Versions struct {
+	// Header is the name of the request header holding the version.
+	// It takes precedence over the media type parameter.
+	Header string
+	// Default is the version used when the request does not specify one.
+	// If empty, requests without a version are rejected.
+	Default string
+
+	types map[string]reflect.Type
+}
+
+// NewVersions returns a dispatcher that reads the version from header
+// and falls back to defaultVersion.
+func NewVersions(header, defaultVersion string) *Versions {
+	return &Versions{Header: header, Default: defaultVersion}
+}
+
+// Register binds requests of the given version into new values of the
+// type of obj, which must be a pointer to a struct.
+func (v *Versions) Register(version string, obj interface{}) *Versions {
+	ensurePointer(obj)
+	if v.types == nil {
+		v.types = make(map[string]reflect.Type)
+	}
+	v.types[version] = reflect.TypeOf(obj).Elem()
+	return v
+}
+
+// Version returns the version requested by req, or Default.
+func (v *Versions) Version(req *http.Request) string {
+	if v.Header != "" {
+		if version := req.Header.Get(v.Header); version != "" {
+			return version
+		}
+	}
+	if _, params, err := mime.ParseMediaType(req.Header.Get("Content-Type")); err == nil && params["version"] != "" {
+		return params["version"]
+	}
+	return v.Default
+}
+
+// Bind creates a value of the struct registered for the requested version,
+// binds and validates req into it and returns a pointer to it. Unknown or
+// missing versions are reported as ERR_VERSION.
+func (v *Versions) Bind(req *http.Request) (interface{}, Errors) {
+	var errors Errors
+	version := v.Version(req)
+	typ, ok := v.types[version]
+	if !ok {
+		if version == "" {
+			errors.Add([]string{}, ERR_VERSION, "Missing API version")
+		} else {
+			errors.Add([]string{}, ERR_VERSION, "Unsupported API version")
+		}
+		return nil, errors
+	}
+
+	obj := reflect.New(typ).Interface()
+	return obj, Bind(req, obj)
+}
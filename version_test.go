@@ -0,0 +1,70 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type createUserV1 struct {
+	Name string `json:"name" binding:"Required"`
+}
+
+type createUserV2 struct {
+	FirstName string `json:"first_name" binding:"Required"`
+	LastName  string `json:"last_name" binding:"Required"`
+}
+
+func Test_Versions(t *testing.T) {
+	versions := NewVersions("X-API-Version", "1").
+		Register("1", &createUserV1{}).
+		Register("2", &createUserV2{})
+
+	newRequest := func(contentType, version, body string) *http.Request {
+		req, _ := http.NewRequest("POST", "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		if version != "" {
+			req.Header.Set("X-API-Version", version)
+		}
+		return req
+	}
+
+	obj, errs := versions.Bind(newRequest("application/json", "", `{"name":"Alice"}`))
+	assert.Empty(t, errs)
+	assert.EqualValues(t, &createUserV1{Name: "Alice"}, obj)
+
+	obj, errs = versions.Bind(newRequest("application/vnd.api+json;version=2", "", `{"first_name":"Alice","last_name":"Liddell"}`))
+	assert.Empty(t, errs)
+	assert.EqualValues(t, &createUserV2{FirstName: "Alice", LastName: "Liddell"}, obj)
+
+	obj, errs = versions.Bind(newRequest("application/vnd.api+json;version=1", "2", `{"name":"Alice"}`))
+	assert.Len(t, errs, 2)
+	assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+	assert.IsType(t, &createUserV2{}, obj)
+
+	obj, errs = versions.Bind(newRequest("application/json", "3", `{}`))
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, ERR_VERSION, errs[0].Classification)
+	assert.Nil(t, obj)
+
+	versions.Default = ""
+	_, errs = versions.Bind(newRequest("application/json", "", `{}`))
+	assert.Len(t, errs, 1)
+	assert.EqualValues(t, "Missing API version", errs[0].Message)
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// XML is middleware to deserialize an XML payload from the request into
+// the struct that is passed in, like JSON. Malformed documents are
+// reported as ERR_DESERIALIZATION.
+func XML(req *http.Request, xmlStruct interface{}) Errors {
+	return bindWith(req, xmlStruct, decodeXML, false)
+}
+
+// BindXML binds the XML body of req into obj and validates it. Unlike
+// Bind it does not look at the Content-Type.
+func BindXML(req *http.Request, obj interface{}) Errors {
+	return XML(req, obj)
+}
+
+func decodeXML(req *http.Request, xmlStruct interface{}) Errors {
+	var errors Errors
+	if req.Body == nil {
+		return errors
+	}
+	defer req.Body.Close()
+
+	b := binderFrom(req)
+	var data bytes.Buffer
+	dec := xml.NewDecoder(io.TeeReader(req.Body, &data))
+	err := dec.Decode(xmlStruct)
+	if err != nil && err != io.EOF {
+		errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+		b.trace(TraceEvent{Stage: TRACE_REJECT, Source: SOURCE_BODY, Errors: errors})
+		return errors
+	}
+
+	present := newFieldPaths()
+	if root := parseXMLTree(data.Bytes()); root != nil {
+		addXMLPresence(present, reflect.TypeOf(xmlStruct), root)
+	}
+	setProvided(req, xmlStruct, present)
+	for _, path := range *present.paths {
+		b.trace(TraceEvent{Stage: TRACE_BIND, Field: path, Source: SOURCE_BODY})
+	}
+	return errors
+}
+
+// xmlNode is an element or attribute of an XML document.
+type xmlNode struct {
+	name     string
+	children []*xmlNode
+}
+
+// parseXMLTree returns the names of the elements and attributes of the
+// document data, under its root element, or nil if there is none.
+func parseXMLTree(data []byte) *xmlNode {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlNode
+	var root *xmlNode
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return root
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			el := &xmlNode{name: tok.Name.Local}
+			for _, attr := range tok.Attr {
+				el.children = append(el.children, &xmlNode{name: attr.Name.Local})
+			}
+			if len(stack) == 0 {
+				if root != nil {
+					return root
+				}
+				root = el
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, el)
+			}
+			stack = append(stack, el)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// child returns the child element or attribute of n called name.
+func (n *xmlNode) child(name string) *xmlNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func addXMLPresence(present fieldPaths, typ reflect.Type, el *xmlNode) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("xml")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" || hasFormOption(tag, "chardata") || hasFormOption(tag, "innerxml") ||
+			hasFormOption(tag, "comment") || field.Name == "XMLName" {
+			continue
+		}
+		if name == "" && field.Anonymous {
+			addXMLPresence(present.at(field.Name), field.Type, el)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		// Paths such as "a>b" name elements nested in others.
+		child := el
+		for _, part := range strings.Split(name, ">") {
+			if child = child.child(part); child == nil {
+				break
+			}
+		}
+		if child != nil {
+			present.add(field.Name)
+			addXMLPresence(present.at(field.Name), field.Type, child)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type xmlOrder struct {
+	ID       int64    `xml:"id,attr" binding:"Required"`
+	Customer string   `xml:"customer" binding:"Required"`
+	Items    []string `xml:"items>item" binding:"MaxSize(2)"`
+	Paid     bool     `xml:"paid"`
+	Address  struct {
+		City string `xml:"city"`
+	} `xml:"address"`
+}
+
+func Test_XML(t *testing.T) {
+	newRequest := func(contentType, body string) *http.Request {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		return withState(req)
+	}
+
+	req := newRequest("application/xml", `<order id="7"><customer>Alice</customer><items><item>a</item><item>b</item></items><paid>false</paid><address><city>Paris</city></address></order>`)
+	var order xmlOrder
+	assert.Empty(t, Bind(req, &order))
+	assert.EqualValues(t, 7, order.ID)
+	assert.EqualValues(t, "Alice", order.Customer)
+	assert.EqualValues(t, []string{"a", "b"}, order.Items)
+	assert.EqualValues(t, "Paris", order.Address.City)
+	assert.EqualValues(t, []string{"ID", "Customer", "Items", "Paid", "Address", "Address.City"}, Provided(req))
+
+	req = newRequest("text/xml; charset=utf-8", `<order><items><item>a</item><item>b</item><item>c</item></items></order>`)
+	order = xmlOrder{}
+	errs := Bind(req, &order)
+	if assert.Len(t, errs, 3) {
+		assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+		assert.EqualValues(t, ERR_REQUIRED, errs[1].Classification)
+		assert.EqualValues(t, ERR_MAX_SIZE, errs[2].Classification)
+	}
+
+	req = newRequest("application/xml", `<order><customer>Alice</order>`)
+	errs = XML(req, &order)
+	assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+
+	// Empty bodies are not malformed.
+	req = newRequest("application/xml", "")
+	order = xmlOrder{}
+	errs = BindXML(req, &order)
+	assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+}
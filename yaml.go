@@ -0,0 +1,102 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML is middleware to deserialize a YAML payload from the request into
+// the struct that is passed in, like JSON. Fields are named by their yaml
+// tag, or else by their lowercased name. Malformed documents are reported
+// as ERR_DESERIALIZATION.
+func YAML(req *http.Request, yamlStruct interface{}) Errors {
+	return bindWith(req, yamlStruct, decodeYAML, false)
+}
+
+// BindYAML binds the YAML body of req into obj and validates it. Unlike
+// Bind it does not look at the Content-Type.
+func BindYAML(req *http.Request, obj interface{}) Errors {
+	return YAML(req, obj)
+}
+
+func decodeYAML(req *http.Request, yamlStruct interface{}) Errors {
+	var errors Errors
+	if req.Body == nil {
+		return errors
+	}
+	defer req.Body.Close()
+
+	b := binderFrom(req)
+	var doc yaml.Node
+	err := yaml.NewDecoder(req.Body).Decode(&doc)
+	if err == nil {
+		err = doc.Decode(yamlStruct)
+		present := newFieldPaths()
+		addYAMLPresence(present, reflect.TypeOf(yamlStruct), &doc)
+		setProvided(req, yamlStruct, present)
+		for _, path := range *present.paths {
+			b.trace(TraceEvent{Stage: TRACE_BIND, Field: path, Source: SOURCE_BODY})
+		}
+	}
+	if err != nil && err != io.EOF {
+		errors.Add([]string{}, ERR_DESERIALIZATION, err.Error())
+		b.trace(TraceEvent{Stage: TRACE_REJECT, Source: SOURCE_BODY, Errors: errors})
+	}
+	return errors
+}
+
+func addYAMLPresence(present fieldPaths, typ reflect.Type, node *yaml.Node) {
+	for node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if node.Kind != yaml.MappingNode || typ.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get("yaml")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if hasFormOption(tag, "inline") {
+			addYAMLPresence(present.at(field.Name), field.Type, node)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == name {
+				present.add(field.Name)
+				addYAMLPresence(present.at(field.Name), field.Type, node.Content[j+1])
+				break
+			}
+		}
+	}
+}
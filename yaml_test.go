@@ -0,0 +1,72 @@
+// Copyright 2020 The Gitea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type yamlConfig struct {
+	Name     string   `yaml:"name" binding:"Required"`
+	Replicas int      `binding:"Range(1,10)"`
+	Enabled  bool     `yaml:"enabled"`
+	Labels   []string `yaml:"labels"`
+	Limits   struct {
+		CPU string `yaml:"cpu"`
+	} `yaml:"limits"`
+}
+
+func Test_YAML(t *testing.T) {
+	newRequest := func(contentType, body string) *http.Request {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		return withState(req)
+	}
+
+	req := newRequest("application/x-yaml", "name: web\nreplicas: 3\nenabled: false\nlabels: [a, b]\nlimits:\n  cpu: 500m\n")
+	var cfg yamlConfig
+	assert.Empty(t, Bind(req, &cfg))
+	assert.EqualValues(t, "web", cfg.Name)
+	assert.EqualValues(t, 3, cfg.Replicas)
+	assert.EqualValues(t, []string{"a", "b"}, cfg.Labels)
+	assert.EqualValues(t, "500m", cfg.Limits.CPU)
+	assert.EqualValues(t, []string{"Name", "Replicas", "Enabled", "Labels", "Limits", "Limits.CPU"}, Provided(req))
+
+	req = newRequest("text/yaml", "replicas: 20\n")
+	cfg = yamlConfig{}
+	errs := Bind(req, &cfg)
+	if assert.Len(t, errs, 2) {
+		assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+		assert.EqualValues(t, ERR_RANGE, errs[1].Classification)
+	}
+
+	req = newRequest("text/yaml", "name: [web\n")
+	errs = YAML(req, &cfg)
+	assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+
+	req = newRequest("text/yaml", "replicas: many\n")
+	errs = YAML(req, &cfg)
+	assert.EqualValues(t, ERR_DESERIALIZATION, errs[0].Classification)
+
+	// Empty bodies are not malformed.
+	req = newRequest("text/yaml", "")
+	cfg = yamlConfig{}
+	errs = BindYAML(req, &cfg)
+	assert.EqualValues(t, ERR_REQUIRED, errs[0].Classification)
+}